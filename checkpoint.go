@@ -0,0 +1,189 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/seal-io/meta-api/schema"
+)
+
+// Checkpoint holds the ingesting progress of the specified type dataset.
+type Checkpoint struct {
+	// Type is the type of the ingesting dataset.
+	Type schema.DatasetIngestRequestType `json:"type"`
+	// Since is the since condition of the ingesting dataset.
+	Since time.Time `json:"since"`
+	// Window is the last window that has been parsed successfully.
+	Window int32 `json:"window"`
+	// NextWindow is the window to resume from.
+	NextWindow int32 `json:"nextWindow"`
+	// WindowSize is the window size of the NextWindow,
+	// which is the responded one if the window size is decided by the exposing service,
+	// zero means the window size is unknown.
+	WindowSize int32 `json:"windowSize,omitempty"`
+	// UpdateTime is the time of recording this checkpoint.
+	UpdateTime time.Time `json:"updateTime"`
+}
+
+// CheckpointStore holds the actions for persisting the ingesting progress.
+type CheckpointStore interface {
+	// Get returns the Checkpoint of the given type and since,
+	// returns nil if not found.
+	Get(ctx context.Context, typ schema.DatasetIngestRequestType, since time.Time) (*Checkpoint, error)
+
+	// Set records the given Checkpoint.
+	Set(ctx context.Context, cp Checkpoint) error
+
+	// Delete removes the Checkpoint of the given type and since.
+	Delete(ctx context.Context, typ schema.DatasetIngestRequestType, since time.Time) error
+}
+
+// NewMemoryCheckpointStore returns a CheckpointStore keeping in memory,
+// which is lost after the process exits.
+func NewMemoryCheckpointStore() CheckpointStore {
+	return &memoryCheckpointStore{
+		records: map[string]Checkpoint{},
+	}
+}
+
+type memoryCheckpointStore struct {
+	m       sync.RWMutex
+	records map[string]Checkpoint
+}
+
+func (in *memoryCheckpointStore) Get(_ context.Context, typ schema.DatasetIngestRequestType, since time.Time) (*Checkpoint, error) {
+	in.m.RLock()
+	defer in.m.RUnlock()
+	var cp, exist = in.records[checkpointKey(typ, since)]
+	if !exist {
+		return nil, nil
+	}
+	return &cp, nil
+}
+
+func (in *memoryCheckpointStore) Set(_ context.Context, cp Checkpoint) error {
+	in.m.Lock()
+	defer in.m.Unlock()
+	in.records[checkpointKey(cp.Type, cp.Since)] = cp
+	return nil
+}
+
+func (in *memoryCheckpointStore) Delete(_ context.Context, typ schema.DatasetIngestRequestType, since time.Time) error {
+	in.m.Lock()
+	defer in.m.Unlock()
+	delete(in.records, checkpointKey(typ, since))
+	return nil
+}
+
+// NewFileCheckpointStore returns a CheckpointStore persisting in the given file,
+// the file is created if not exists.
+func NewFileCheckpointStore(path string) (CheckpointStore, error) {
+	var cps []Checkpoint
+	var err = readJSONFile(path, "checkpoint", &cps)
+	if err != nil {
+		return nil, err
+	}
+	var records = make(map[string]Checkpoint, len(cps))
+	for i := range cps {
+		records[checkpointKey(cps[i].Type, cps[i].Since)] = cps[i]
+	}
+	return &fileCheckpointStore{
+		memoryCheckpointStore: memoryCheckpointStore{
+			records: records,
+		},
+		path: path,
+	}, nil
+}
+
+type fileCheckpointStore struct {
+	memoryCheckpointStore
+	w    sync.Mutex
+	path string
+}
+
+func (in *fileCheckpointStore) Set(ctx context.Context, cp Checkpoint) error {
+	in.w.Lock()
+	defer in.w.Unlock()
+	var _ = in.memoryCheckpointStore.Set(ctx, cp)
+	return in.flush()
+}
+
+func (in *fileCheckpointStore) Delete(ctx context.Context, typ schema.DatasetIngestRequestType, since time.Time) error {
+	in.w.Lock()
+	defer in.w.Unlock()
+	var _ = in.memoryCheckpointStore.Delete(ctx, typ, since)
+	return in.flush()
+}
+
+// flush persists all records.
+func (in *fileCheckpointStore) flush() error {
+	in.m.RLock()
+	var cps = make([]Checkpoint, 0, len(in.records))
+	for k := range in.records {
+		cps = append(cps, in.records[k])
+	}
+	in.m.RUnlock()
+	sort.Slice(cps, func(i, j int) bool {
+		if cps[i].Type != cps[j].Type {
+			return cps[i].Type < cps[j].Type
+		}
+		return cps[i].Since.Before(cps[j].Since)
+	})
+
+	return writeJSONFile(in.path, "checkpoint", cps)
+}
+
+func checkpointKey(typ schema.DatasetIngestRequestType, since time.Time) string {
+	return typ.String() + "@" + since.UTC().Format(time.RFC3339Nano)
+}
+
+// readJSONFile decodes the given JSON file of the specified kind into v,
+// v is untouched if the file does not exist or is empty.
+func readJSONFile(path, kind string, v any) error {
+	var bs, err = os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("error reading %s file %s: %w", kind, path, err)
+	}
+	if len(bs) == 0 {
+		return nil
+	}
+	err = json.Unmarshal(bs, v)
+	if err != nil {
+		return fmt.Errorf("error decoding %s file %s: %w", kind, path, err)
+	}
+	return nil
+}
+
+// writeJSONFile writes v as JSON into a temporary file of the specified kind and then renames it to the given path,
+// which prevents from corrupting the persisted file if the process is killed during writing.
+func writeJSONFile(path, kind string, v any) error {
+	var bs, err = json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("error encoding %s file %s: %w", kind, path, err)
+	}
+	var tmp = path + ".tmp"
+	err = os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return fmt.Errorf("error creating %s directory: %w", kind, err)
+	}
+	err = os.WriteFile(tmp, bs, 0o600)
+	if err != nil {
+		return fmt.Errorf("error writing %s file %s: %w", kind, tmp, err)
+	}
+	err = os.Rename(tmp, path)
+	if err != nil {
+		return fmt.Errorf("error renaming %s file %s: %w", kind, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/seal-io/meta-api/schema"
+)
+
+func TestCheckpointStore(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "checkpoint", "ingest.json")
+	var fileStore, err = NewFileCheckpointStore(path)
+	if err != nil {
+		t.Fatalf("error creating file checkpoint store: %v", err)
+	}
+	var testCases = []struct {
+		name  string
+		given CheckpointStore
+	}{
+		{
+			name:  "memory",
+			given: NewMemoryCheckpointStore(),
+		},
+		{
+			name:  "file",
+			given: fileStore,
+		},
+	}
+	var ctx = context.Background()
+	var since = time.Date(2022, 11, 3, 11, 18, 47, 0, time.UTC)
+	for _, c := range testCases {
+		var s = c.given
+		var cp, err = s.Get(ctx, schema.DatasetIngestRequestType_Weakness_Vulnerability, since)
+		if err != nil || cp != nil {
+			t.Fatalf("%s: expected no checkpoint, but got %v, %v", c.name, cp, err)
+		}
+		for _, given := range []Checkpoint{
+			{Type: schema.DatasetIngestRequestType_Weakness_Vulnerability, Since: since, Window: 3, NextWindow: 4},
+			{Type: schema.DatasetIngestRequestType_Weakness_Vulnerability, Window: 7, NextWindow: 8},
+			{Type: schema.DatasetIngestRequestType_Compliance_License, Since: since, Window: 1, NextWindow: 2},
+		} {
+			err = s.Set(ctx, given)
+			if err != nil {
+				t.Fatalf("%s: error setting checkpoint: %v", c.name, err)
+			}
+		}
+		cp, _ = s.Get(ctx, schema.DatasetIngestRequestType_Weakness_Vulnerability, since.In(time.Local))
+		if cp == nil || cp.NextWindow != 4 {
+			t.Errorf("%s: expected next window 4, but got %+v", c.name, cp)
+		}
+		cp, _ = s.Get(ctx, schema.DatasetIngestRequestType_Weakness_Vulnerability, time.Time{})
+		if cp == nil || cp.NextWindow != 8 {
+			t.Errorf("%s: expected next window 8, but got %+v", c.name, cp)
+		}
+		err = s.Delete(ctx, schema.DatasetIngestRequestType_Weakness_Vulnerability, since)
+		if err != nil {
+			t.Fatalf("%s: error deleting checkpoint: %v", c.name, err)
+		}
+		cp, _ = s.Get(ctx, schema.DatasetIngestRequestType_Weakness_Vulnerability, since)
+		if cp != nil {
+			t.Errorf("%s: expected no checkpoint after deleting, but got %+v", c.name, cp)
+		}
+	}
+
+	// reload from file.
+	reloaded, err := NewFileCheckpointStore(path)
+	if err != nil {
+		t.Fatalf("error reloading file checkpoint store: %v", err)
+	}
+	var cp, _ = reloaded.Get(ctx, schema.DatasetIngestRequestType_Compliance_License, since)
+	if cp == nil || cp.Window != 1 || cp.NextWindow != 2 {
+		t.Errorf("expected reloaded checkpoint of window 1, but got %+v", cp)
+	}
+	cp, _ = reloaded.Get(ctx, schema.DatasetIngestRequestType_Weakness_Vulnerability, since)
+	if cp != nil {
+		t.Errorf("expected deleted checkpoint not reloaded, but got %+v", cp)
+	}
+}
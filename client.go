@@ -2,11 +2,20 @@ package api
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/seal-io/meta-api/schema"
@@ -16,87 +25,549 @@ import (
 type Client interface {
 	// Ingest ingests specified type dataset from the exposing service,
 	// and parses dataset with the given IngestParser.
-	Ingest(ctx context.Context, typ schema.DatasetIngestRequestType, since time.Time, parse IngestParser) (err error)
+	Ingest(ctx context.Context, typ schema.DatasetIngestRequestType, since time.Time, parse IngestParser, opts ...IngestOption) (err error)
 
 	// IngestAll ingests all types dataset from the exposing service,
 	// and parses dataset with the given IngestParser.
-	IngestAll(ctx context.Context, since time.Time, parse IngestParser) (err error)
+	IngestAll(ctx context.Context, since time.Time, parse IngestParser, opts ...IngestOption) (err error)
 
 	// Close closes the client.
 	Close() error
 }
 
-// GetClient returns the Client.
-func GetClient(ctx context.Context, listenOn string) (Client, error) {
-	var opts = []grpc.DialOption{
+// GetClient returns the Client,
+// by default, it dials the exposing service in plaintext and blocks until the connection is up.
+func GetClient(ctx context.Context, listenOn string, opts ...ClientOption) (Client, error) {
+	var o = _ClientOptions{
+		MaxMessageSize: defaultMaxMessageSize,
+	}
+	for i := range opts {
+		if opts[i] == nil {
+			continue
+		}
+		opts[i](&o)
+	}
+
+	var dopts = []grpc.DialOption{
 		grpc.WithBlock(),
-		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(128 * 1024 * 1024)),
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(o.MaxMessageSize),
+			grpc.MaxCallSendMsgSize(o.MaxMessageSize)),
+	}
+	var tlsConfig = o.TLSConfig
+	if len(o.ClientCertificates) != 0 {
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		} else {
+			tlsConfig = tlsConfig.Clone()
+		}
+		tlsConfig.Certificates = append(tlsConfig.Certificates, o.ClientCertificates...)
+	}
+	if tlsConfig != nil {
+		dopts = append(dopts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		dopts = append(dopts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+	if o.PerRPCCredentials != nil {
+		dopts = append(dopts, grpc.WithPerRPCCredentials(o.PerRPCCredentials))
+	}
+	if o.Keepalive != nil {
+		dopts = append(dopts, grpc.WithKeepaliveParams(*o.Keepalive))
 	}
-	var cc, err = grpc.DialContext(ctx, listenOn, opts...)
+	dopts = append(dopts, o.DialOptions...)
+	if o.DialTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.DialTimeout)
+		defer cancel()
+	}
+
+	var cc, err = grpc.DialContext(ctx, listenOn, dopts...)
 	if err != nil {
 		return nil, fmt.Errorf("error dialing %s: %w", listenOn, err)
 	}
 	var cli = &client{
-		cc: cc,
+		cc:             cc,
+		maxMessageSize: o.MaxMessageSize,
 	}
 	return cli, nil
 }
 
+const defaultMaxMessageSize = 128 * 1024 * 1024
+
+type _ClientOptions struct {
+	TLSConfig          *tls.Config
+	ClientCertificates []tls.Certificate
+	PerRPCCredentials  credentials.PerRPCCredentials
+	DialTimeout        time.Duration
+	Keepalive          *keepalive.ClientParameters
+	MaxMessageSize     int
+	DialOptions        []grpc.DialOption
+}
+
+// ClientOption configures the Client at GetClient.
+type ClientOption func(*_ClientOptions)
+
+// WithTLSConfig dials the exposing service over TLS with the given tls.Config.
+func WithTLSConfig(cfg *tls.Config) ClientOption {
+	return func(o *_ClientOptions) {
+		o.TLSConfig = cfg
+	}
+}
+
+// WithClientCertificate presents the given certificate to the exposing service for mutual TLS,
+// it enables TLS even if WithTLSConfig is not specified.
+func WithClientCertificate(cert tls.Certificate) ClientOption {
+	return func(o *_ClientOptions) {
+		o.ClientCertificates = append(o.ClientCertificates, cert)
+	}
+}
+
+// WithPerRPCCredentials attaches the given credentials.PerRPCCredentials to every call.
+func WithPerRPCCredentials(creds credentials.PerRPCCredentials) ClientOption {
+	return func(o *_ClientOptions) {
+		o.PerRPCCredentials = creds
+	}
+}
+
+// WithBearerToken attaches the given token as "authorization: Bearer <token>" to every call,
+// which requires TLS.
+func WithBearerToken(token string) ClientOption {
+	return WithPerRPCCredentials(bearerToken(token))
+}
+
+// WithDialTimeout limits the duration of dialing the exposing service.
+func WithDialTimeout(d time.Duration) ClientOption {
+	return func(o *_ClientOptions) {
+		o.DialTimeout = d
+	}
+}
+
+// WithKeepalive configures the keepalive parameters of the connection.
+func WithKeepalive(params keepalive.ClientParameters) ClientOption {
+	return func(o *_ClientOptions) {
+		o.Keepalive = &params
+	}
+}
+
+// WithMaxMessageSize configures the maximum bytes of the message to receive and send,
+// default is 128MiB.
+func WithMaxMessageSize(n int) ClientOption {
+	return func(o *_ClientOptions) {
+		if n > 0 {
+			o.MaxMessageSize = n
+		}
+	}
+}
+
+// WithDialOptions appends the given grpc.DialOption,
+// which are applied after the other options.
+func WithDialOptions(opts ...grpc.DialOption) ClientOption {
+	return func(o *_ClientOptions) {
+		o.DialOptions = append(o.DialOptions, opts...)
+	}
+}
+
+type bearerToken string
+
+func (in bearerToken) GetRequestMetadata(_ context.Context, _ ...string) (map[string]string, error) {
+	return map[string]string{
+		"authorization": "Bearer " + string(in),
+	}, nil
+}
+
+func (in bearerToken) RequireTransportSecurity() bool {
+	return true
+}
+
 type client struct {
-	cc *grpc.ClientConn
+	cc             *grpc.ClientConn
+	maxMessageSize int
 }
 
 // IngestParser is the parser to parse the given api.DatasetIngestResponseBody.
 type IngestParser func(currentWindow int32, body schema.DatasetIngestResponseBody) error
 
-func (in *client) Ingest(ctx context.Context, typ schema.DatasetIngestRequestType, since time.Time, parse IngestParser) error {
-	var cli, err = schema.NewDatasetServiceClient(in.cc).Ingest(ctx)
-	if err != nil {
-		return fmt.Errorf("error creating ingest client: %w", err)
+type _IngestOptions struct {
+	Checkpoint        CheckpointStore
+	Retry             RetryPolicy
+	Concurrency       int
+	SerializedParsing bool
+	Snapshot          *SnapshotWriter
+	WindowSize        int32
+	AdaptiveWindow    *AdaptiveWindowPolicy
+	Observers         ingestObservers
+
+	// maxMessageSize is the max receive message size of the Client.
+	maxMessageSize int
+}
+
+// IngestOption configures the ingesting of Client.
+type IngestOption func(*_IngestOptions)
+
+// WithCheckpoint resumes the ingesting from the Checkpoint recorded by the given CheckpointStore,
+// records the Checkpoint after each window parsed, and removes it after the whole dataset ingested.
+func WithCheckpoint(store CheckpointStore) IngestOption {
+	return func(o *_IngestOptions) {
+		o.Checkpoint = store
+	}
+}
+
+// WithRetry retries the failed window with the given RetryPolicy,
+// by default, the failed window is not retried.
+func WithRetry(policy RetryPolicy) IngestOption {
+	return func(o *_IngestOptions) {
+		o.Retry = policy
+	}
+}
+
+// WithConcurrency makes IngestAll ingest the types dataset concurrently with at most n workers,
+// each type is ingested on its own stream and the IngestParser might be called concurrently,
+// the errors of all types are aggregated as IngestErrors.
+func WithConcurrency(n int) IngestOption {
+	return func(o *_IngestOptions) {
+		o.Concurrency = n
+	}
+}
+
+// WithSerializedParsing calls the IngestParser one by one during concurrent ingesting,
+// which is useful if the IngestParser is not safe for concurrent use.
+func WithSerializedParsing() IngestOption {
+	return func(o *_IngestOptions) {
+		o.SerializedParsing = true
+	}
+}
+
+// WithSnapshot records each window into the given SnapshotWriter after it is parsed,
+// which can be replayed by the Client of OpenSnapshotClient,
+// the ingesting fails if it is going to resume from the Checkpoint of WithCheckpoint.
+func WithSnapshot(w *SnapshotWriter) IngestOption {
+	return func(o *_IngestOptions) {
+		o.Snapshot = w
+	}
+}
+
+// WithWindowSize requests the given count of items in a window,
+// by default, the window size is decided by the exposing service,
+// which might respond a different window size, e.g. limited by the exposing service.
+func WithWindowSize(n int32) IngestOption {
+	return func(o *_IngestOptions) {
+		if n > 0 {
+			o.WindowSize = n
+		}
+	}
+}
+
+// WithAdaptiveWindowSize adjusts the window size with the given AdaptiveWindowPolicy,
+// the window is shrunk and requested again if its message exceeds the max receive message size,
+// instead of failing the ingesting.
+func WithAdaptiveWindowSize(policy AdaptiveWindowPolicy) IngestOption {
+	return func(o *_IngestOptions) {
+		o.AdaptiveWindow = &policy
 	}
+}
+
+func getIngestOptions(opts []IngestOption) _IngestOptions {
+	var o _IngestOptions
+	for i := range opts {
+		if opts[i] == nil {
+			continue
+		}
+		opts[i](&o)
+	}
+	return o
+}
+
+func (in *client) Ingest(ctx context.Context, typ schema.DatasetIngestRequestType, since time.Time, parse IngestParser, opts ...IngestOption) error {
+	var o = getIngestOptions(opts)
+	o.maxMessageSize = in.maxMessageSize
+	return ingest(ctx, &ingestStream{cc: in.cc}, typ, since, parse, o)
+}
+
+// ingestExchanger holds the actions for exchanging the ingest request and response.
+type ingestExchanger interface {
+	// exchange returns the response of the given request.
+	exchange(ctx context.Context, req *schema.DatasetIngestRequest) (*schema.DatasetIngestResponse, error)
+	// close releases the resources, the ingestExchanger is still usable after closing.
+	close()
+}
+
+// ingest ingests specified type dataset window by window through the given ingestExchanger.
+func ingest(ctx context.Context, stream ingestExchanger, typ schema.DatasetIngestRequestType, since time.Time, parse IngestParser, o _IngestOptions) (err error) {
+	var sizer = newWindowSizer(o)
 	var window int32
+	if o.Checkpoint != nil {
+		var cp, err = o.Checkpoint.Get(ctx, typ, since)
+		if err != nil {
+			return fmt.Errorf("error getting ingest checkpoint: %w", err)
+		}
+		if cp != nil {
+			if o.Snapshot != nil {
+				// NB: the snapshot must be replayable from the first window.
+				return fmt.Errorf("error recording ingest snapshot: cannot resume from window %d of checkpoint",
+					cp.NextWindow)
+			}
+			window = sizer.resume(cp)
+		}
+	}
+	defer stream.close()
+	var stat = IngestEndEvent{Type: typ}
+	if len(o.Observers) != 0 {
+		var start = time.Now()
+		o.Observers.OnIngestStart(IngestStartEvent{Type: typ, Since: since, Window: window})
+		defer func() {
+			stat.Duration = time.Since(start)
+			stat.Err = err
+			o.Observers.OnIngestEnd(stat)
+		}()
+	}
+	var attempts int
 	for window >= 0 {
 		var req = &schema.DatasetIngestRequest{
-			Window: window,
-			Type:   typ,
+			Window:     window,
+			WindowSize: sizer.request(),
+			Type:       typ,
 		}
 		if !since.IsZero() {
 			req.Since = timestamppb.New(since)
 		}
-		err = cli.Send(req)
+		var start = time.Now()
+		var resp, err = stream.exchange(ctx, req)
+		var latency = time.Since(start)
 		if err != nil {
-			return fmt.Errorf("error sending ingest request: %w", err)
+			if w, ok := sizer.shrink(window, err); ok {
+				// NB: the stream is broken by the oversize message.
+				stream.close()
+				window = w
+				continue
+			}
+			attempts++
+			if !o.Retry.IsRetryable(err, attempts) || ctx.Err() != nil {
+				return err
+			}
+			stream.close()
+			var backoff = o.Retry.Backoff(attempts)
+			stat.Retries++
+			o.Observers.OnIngestRetry(IngestRetryEvent{
+				Type:     typ,
+				Window:   window,
+				Attempts: attempts,
+				Backoff:  backoff,
+				Err:      err,
+			})
+			err = wait(ctx, backoff)
+			if err != nil {
+				return fmt.Errorf("error waiting to retry ingest request: %w", err)
+			}
+			continue
 		}
-		var resp *schema.DatasetIngestResponse
-		resp, err = cli.Recv()
-		if err != nil {
-			return fmt.Errorf("error receiving ingest response: %w", err)
+		attempts = 0
+		if w, ok := sizer.reconcile(window, resp.GetWindowSize()); !ok {
+			window = w
+			continue
 		}
+		start = time.Now()
 		if parse != nil && resp.GetBody() != nil {
 			err = parse(window, resp.GetBody())
 			if err != nil {
 				return fmt.Errorf("error parsing ingest response: %w", err)
 			}
 		}
+		var parseLatency = time.Since(start)
+		var size int
+		if len(o.Observers) != 0 || sizer.adaptive != nil {
+			// NB: measuring the message size requires marshaling,
+			// which is only worthy for the observers or the adaptive window.
+			size = proto.Size(resp)
+		}
+		if len(o.Observers) != 0 {
+			var items = countItems(resp.GetBody())
+			stat.Windows++
+			stat.Items += items
+			stat.Bytes += size
+			o.Observers.OnIngestWindow(IngestWindowEvent{
+				Type:         typ,
+				Window:       window,
+				WindowSize:   resp.GetWindowSize(),
+				Items:        items,
+				Bytes:        size,
+				Latency:      latency,
+				ParseLatency: parseLatency,
+			})
+		}
+		if o.Snapshot != nil {
+			err = o.Snapshot.Record(typ, window, resp)
+			if err != nil {
+				return fmt.Errorf("error recording ingest snapshot: %w", err)
+			}
+		}
+		var currentWindow = window
 		window = resp.GetNextWindow()
 		if resp.NextWindow == nil {
 			window = -1
 		}
+		window = sizer.observe(window, size, parseLatency)
+		if o.Checkpoint != nil {
+			if window < 0 {
+				err = o.Checkpoint.Delete(ctx, typ, since)
+			} else {
+				err = o.Checkpoint.Set(ctx, Checkpoint{
+					Type:       typ,
+					Since:      since,
+					Window:     currentWindow,
+					NextWindow: window,
+					WindowSize: sizer.current(),
+					UpdateTime: time.Now(),
+				})
+			}
+			if err != nil {
+				return fmt.Errorf("error recording ingest checkpoint: %w", err)
+			}
+		}
 	}
 	return nil
 }
 
-func (in *client) IngestAll(ctx context.Context, since time.Time, parse IngestParser) error {
-	for typ := 0; typ < len(schema.DatasetIngestRequestType_name); typ++ {
-		var err = in.Ingest(ctx, schema.DatasetIngestRequestType(typ), since, parse)
+// ingestStream wraps the schema.DatasetService_IngestClient,
+// which is able to be re-established after closing.
+type ingestStream struct {
+	cc     grpc.ClientConnInterface
+	cli    schema.DatasetService_IngestClient
+	cancel context.CancelFunc
+}
+
+// exchange sends the given request and then receives the response,
+// the underlay stream is established if not yet.
+func (in *ingestStream) exchange(ctx context.Context, req *schema.DatasetIngestRequest) (*schema.DatasetIngestResponse, error) {
+	if in.cli == nil {
+		var sctx, cancel = context.WithCancel(ctx)
+		var cli, err = schema.NewDatasetServiceClient(in.cc).Ingest(sctx)
 		if err != nil {
-			return err
+			cancel()
+			return nil, fmt.Errorf("error creating ingest client: %w", err)
+		}
+		in.cli = cli
+		in.cancel = cancel
+	}
+	var err = in.cli.Send(req)
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("error sending ingest request: %w", err)
+		}
+		// NB: the stream is broken if sending returns io.EOF,
+		// the actual error can be found by receiving.
+	}
+	var resp *schema.DatasetIngestResponse
+	resp, err = in.cli.Recv()
+	if err != nil {
+		return nil, fmt.Errorf("error receiving ingest response: %w", err)
+	}
+	return resp, nil
+}
+
+// close closes the underlay stream.
+func (in *ingestStream) close() {
+	if in.cancel != nil {
+		in.cancel()
+	}
+	in.cli = nil
+	in.cancel = nil
+}
+
+func (in *client) IngestAll(ctx context.Context, since time.Time, parse IngestParser, opts ...IngestOption) error {
+	return ingestAll(ctx, in, since, parse, opts)
+}
+
+// ingestAll ingests all types dataset through the given Client.
+func ingestAll(ctx context.Context, in Client, since time.Time, parse IngestParser, opts []IngestOption) error {
+	var o = getIngestOptions(opts)
+	if o.Concurrency <= 1 {
+		for typ := 0; typ < len(schema.DatasetIngestRequestType_name); typ++ {
+			var err = in.Ingest(ctx, schema.DatasetIngestRequestType(typ), since, parse, opts...)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if parse != nil && o.SerializedParsing {
+		var m sync.Mutex
+		var p = parse
+		parse = func(currentWindow int32, body schema.DatasetIngestResponseBody) error {
+			m.Lock()
+			defer m.Unlock()
+			return p(currentWindow, body)
 		}
 	}
+	var (
+		wg   sync.WaitGroup
+		m    sync.Mutex
+		errs = IngestErrors{}
+		sema = make(chan struct{}, o.Concurrency)
+	)
+	for typ := 0; typ < len(schema.DatasetIngestRequestType_name); typ++ {
+		var typ = schema.DatasetIngestRequestType(typ)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case <-ctx.Done():
+				m.Lock()
+				errs[typ] = ctx.Err()
+				m.Unlock()
+				return
+			case sema <- struct{}{}:
+			}
+			defer func() { <-sema }()
+			var err = in.Ingest(ctx, typ, since, parse, opts...)
+			if err != nil {
+				m.Lock()
+				errs[typ] = err
+				m.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(errs) != 0 {
+		return errs
+	}
 	return nil
 }
 
+// IngestErrors holds the errors of ingesting each type dataset.
+type IngestErrors map[schema.DatasetIngestRequestType]error
+
+func (e IngestErrors) Error() string {
+	var typs = make([]schema.DatasetIngestRequestType, 0, len(e))
+	for typ := range e {
+		typs = append(typs, typ)
+	}
+	sort.Slice(typs, func(i, j int) bool {
+		return typs[i] < typs[j]
+	})
+	var sb strings.Builder
+	for i := range typs {
+		if i != 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString("error ingesting ")
+		sb.WriteString(typs[i].String())
+		sb.WriteString(": ")
+		sb.WriteString(e[typs[i]].Error())
+	}
+	return sb.String()
+}
+
+// Is returns true if any error of IngestErrors matches the given target.
+func (e IngestErrors) Is(target error) bool {
+	for typ := range e {
+		if errors.Is(e[typ], target) {
+			return true
+		}
+	}
+	return false
+}
+
 func (in *client) Close() error {
 	if in.cc != nil {
 		return in.cc.Close()
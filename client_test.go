@@ -2,15 +2,35 @@ package api
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
+	"io"
+	"math/big"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 
 	"github.com/seal-io/meta-api/schema"
 )
@@ -70,6 +90,346 @@ func TestClient(t *testing.T) {
 	}
 }
 
+func TestClient_Ingest_WithCheckpoint(t *testing.T) {
+	var srv = &testingDatasetServer{
+		windows: map[schema.DatasetIngestRequestType][]schema.DatasetIngestResponseBody{
+			schema.DatasetIngestRequestType_Compliance_License_Tag: testingLicenseTagWindows(4),
+		},
+	}
+	var cli = testingClient(t, srv)
+	var store = NewMemoryCheckpointStore()
+	var ctx = context.Background()
+	var typ = schema.DatasetIngestRequestType_Compliance_License_Tag
+
+	// break at the 3rd window.
+	var errBreak = errors.New("break")
+	var parsed []int32
+	var err = cli.Ingest(ctx, typ, time.Time{}, func(currentWindow int32, _ schema.DatasetIngestResponseBody) error {
+		if currentWindow == 2 {
+			return errBreak
+		}
+		parsed = append(parsed, currentWindow)
+		return nil
+	}, WithCheckpoint(store))
+	if !errors.Is(err, errBreak) {
+		t.Fatalf("expected break error, but got %v", err)
+	}
+	var cp, _ = store.Get(ctx, typ, time.Time{})
+	if cp == nil || cp.Window != 1 || cp.NextWindow != 2 {
+		t.Fatalf("expected checkpoint of window 1, but got %+v", cp)
+	}
+
+	// resume from the 3rd window.
+	err = cli.Ingest(ctx, typ, time.Time{}, func(currentWindow int32, _ schema.DatasetIngestResponseBody) error {
+		parsed = append(parsed, currentWindow)
+		return nil
+	}, WithCheckpoint(store))
+	if err != nil {
+		t.Fatalf("error resuming: %v", err)
+	}
+	if len(parsed) != 4 || parsed[2] != 2 || parsed[3] != 3 {
+		t.Errorf("expected parsed windows [0 1 2 3], but got %v", parsed)
+	}
+	cp, _ = store.Get(ctx, typ, time.Time{})
+	if cp != nil {
+		t.Errorf("expected checkpoint removed after completing, but got %+v", cp)
+	}
+	if requested := srv.requestedWindows(typ); len(requested) != 5 || requested[3] != 2 {
+		t.Errorf("expected requested windows [0 1 2 2 3], but got %v", requested)
+	}
+}
+
+func TestClient_IngestAll_WithConcurrency(t *testing.T) {
+	var srv = &testingDatasetServer{
+		windows: map[schema.DatasetIngestRequestType][]schema.DatasetIngestResponseBody{},
+		intercept: func(req *schema.DatasetIngestRequest) error {
+			if req.GetType() == schema.DatasetIngestRequestType_Weakness_Vulnerability && req.GetWindow() == 1 {
+				return status.Error(codes.InvalidArgument, "invalid")
+			}
+			return nil
+		},
+	}
+	for typ := range schema.DatasetIngestRequestType_name {
+		srv.windows[schema.DatasetIngestRequestType(typ)] = testingLicenseTagWindows(3)
+	}
+	var cli = testingClient(t, srv)
+
+	var (
+		m          sync.Mutex
+		parsed     int
+		inflight   int32
+		overlapped bool
+	)
+	var err = cli.IngestAll(context.Background(), time.Time{}, func(currentWindow int32, _ schema.DatasetIngestResponseBody) error {
+		if atomic.AddInt32(&inflight, 1) > 1 {
+			overlapped = true
+		}
+		defer atomic.AddInt32(&inflight, -1)
+		time.Sleep(time.Millisecond)
+		m.Lock()
+		parsed++
+		m.Unlock()
+		return nil
+	}, WithConcurrency(3), WithSerializedParsing())
+	var errs IngestErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected IngestErrors, but got %v", err)
+	}
+	if len(errs) != 1 || errs[schema.DatasetIngestRequestType_Weakness_Vulnerability] == nil {
+		t.Errorf("expected only error of %s, but got %v", schema.DatasetIngestRequestType_Weakness_Vulnerability, errs)
+	}
+	if overlapped {
+		t.Error("expected serialized parsing, but got overlapped parsing")
+	}
+	// 4 types with 3 windows, and 1 type with 1 window.
+	if parsed != 13 {
+		t.Errorf("expected 13 parsed windows, but got %d", parsed)
+	}
+}
+
+func TestGetClient_WithOptions(t *testing.T) {
+	var ca, serverCert, clientCert = testingCertificates(t)
+	var srv = &testingDatasetServer{
+		windows: map[schema.DatasetIngestRequestType][]schema.DatasetIngestResponseBody{
+			schema.DatasetIngestRequestType_Compliance_License_Tag: testingLicenseTagWindows(2),
+		},
+	}
+	var lis, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error listening: %v", err)
+	}
+	var authorizations = make(chan string, 8)
+	var gs = grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(&tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{serverCert},
+			ClientCAs:    ca,
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+		})),
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			var md, _ = metadata.FromIncomingContext(ss.Context())
+			authorizations <- strings.Join(md.Get("authorization"), ",")
+			return handler(srv, ss)
+		}))
+	schema.RegisterDatasetServiceServer(gs, srv)
+	go func() { _ = gs.Serve(lis) }()
+	t.Cleanup(gs.Stop)
+
+	var ctx = context.Background()
+	var tlsConfig = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    ca,
+		ServerName: "localhost",
+	}
+
+	// without client certificate.
+	_, err = GetClient(ctx, lis.Addr().String(),
+		WithTLSConfig(tlsConfig),
+		WithDialTimeout(500*time.Millisecond))
+	if err == nil {
+		t.Fatal("expected error dialing without client certificate")
+	}
+
+	// with client certificate and bearer token.
+	cli, err := GetClient(ctx, lis.Addr().String(),
+		WithTLSConfig(tlsConfig),
+		WithClientCertificate(clientCert),
+		WithBearerToken("secret"),
+		WithKeepalive(keepalive.ClientParameters{Time: time.Minute}),
+		WithDialTimeout(5*time.Second))
+	if err != nil {
+		t.Fatalf("error dialing: %v", err)
+	}
+	defer func() { _ = cli.Close() }()
+	var parsed int
+	err = cli.Ingest(ctx, schema.DatasetIngestRequestType_Compliance_License_Tag, time.Time{},
+		func(int32, schema.DatasetIngestResponseBody) error {
+			parsed++
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("error ingesting: %v", err)
+	}
+	if parsed != 2 {
+		t.Errorf("expected 2 parsed windows, but got %d", parsed)
+	}
+	if auth := <-authorizations; auth != "Bearer secret" {
+		t.Errorf("expected authorization 'Bearer secret', but got '%s'", auth)
+	}
+
+	// with tiny message size.
+	tiny, err := GetClient(ctx, lis.Addr().String(),
+		WithTLSConfig(tlsConfig),
+		WithClientCertificate(clientCert),
+		WithMaxMessageSize(8),
+		WithDialOptions(grpc.WithUserAgent("testing")))
+	if err != nil {
+		t.Fatalf("error dialing: %v", err)
+	}
+	defer func() { _ = tiny.Close() }()
+	err = tiny.Ingest(ctx, schema.DatasetIngestRequestType_Compliance_License_Tag, time.Time{}, nil)
+	if testingStatusCode(err) != codes.ResourceExhausted {
+		t.Errorf("expected resource exhausted error, but got %v", err)
+	}
+}
+
+// testingDatasetServer is a fake schema.DatasetServiceServer,
+// which responses the given windows in order, a window includes at most one item.
+type testingDatasetServer struct {
+	schema.UnimplementedDatasetServiceServer
+
+	windows map[schema.DatasetIngestRequestType][]schema.DatasetIngestResponseBody
+	// intercept is called before responding the received request,
+	// the stream is aborted if it returns error.
+	intercept func(req *schema.DatasetIngestRequest) error
+
+	m         sync.Mutex
+	requested map[schema.DatasetIngestRequestType][]int32
+}
+
+func (s *testingDatasetServer) Ingest(stream schema.DatasetService_IngestServer) error {
+	for {
+		var req, err = stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		s.m.Lock()
+		if s.requested == nil {
+			s.requested = map[schema.DatasetIngestRequestType][]int32{}
+		}
+		s.requested[req.GetType()] = append(s.requested[req.GetType()], req.GetWindow())
+		s.m.Unlock()
+		if s.intercept != nil {
+			err = s.intercept(req)
+			if err != nil {
+				return err
+			}
+		}
+		var bodies = s.windows[req.GetType()]
+		var resp = &schema.DatasetIngestResponse{
+			WindowSize: 1,
+		}
+		if w := int(req.GetWindow()); w < len(bodies) {
+			resp.Body = bodies[w]
+			if w+1 < len(bodies) {
+				var next = int32(w + 1)
+				resp.NextWindow = &next
+			}
+		}
+		err = stream.Send(resp)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *testingDatasetServer) requestedWindows(typ schema.DatasetIngestRequestType) []int32 {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return append([]int32(nil), s.requested[typ]...)
+}
+
+// testingClient serves the given schema.DatasetServiceServer in memory,
+// and returns a Client connecting to it.
+func testingClient(t *testing.T, srv schema.DatasetServiceServer) Client {
+	var lis = bufconn.Listen(1024 * 1024)
+	var gs = grpc.NewServer()
+	schema.RegisterDatasetServiceServer(gs, srv)
+	go func() { _ = gs.Serve(lis) }()
+	t.Cleanup(gs.Stop)
+
+	var cc, err = grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("error dialing testing server: %v", err)
+	}
+	var cli = &client{cc: cc}
+	t.Cleanup(func() { _ = cli.Close() })
+	return cli
+}
+
+func testingLicenseTagWindows(n int) []schema.DatasetIngestResponseBody {
+	var r = make([]schema.DatasetIngestResponseBody, 0, n)
+	for i := 0; i < n; i++ {
+		r = append(r, &schema.DatasetIngestResponse_ComplianceLicenseTags{
+			ComplianceLicenseTags: &schema.ComplianceLicenseTags{
+				Items: []*schema.ComplianceLicenseTag{
+					{
+						Name: "tag-" + string(rune('a'+i)),
+					},
+				},
+			},
+		})
+	}
+	return r
+}
+
+// testingStatusCode returns the gRPC status code of the given (wrapped) error.
+func testingStatusCode(err error) codes.Code {
+	var se interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &se) {
+		return se.GRPCStatus().Code()
+	}
+	return status.Code(err)
+}
+
+// testingCertificates returns a CA pool, a server certificate of localhost and a client certificate,
+// both certificates are signed by the CA.
+func testingCertificates(t *testing.T) (*x509.CertPool, tls.Certificate, tls.Certificate) {
+	var caKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	var caTmpl = &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "testing-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("error creating ca certificate: %v", err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatalf("error parsing ca certificate: %v", err)
+	}
+	var ca = x509.NewCertPool()
+	ca.AddCert(caCert)
+
+	var issue = func(serial int64, usage x509.ExtKeyUsage) tls.Certificate {
+		var key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("error generating key: %v", err)
+		}
+		var tmpl = &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "localhost"},
+			DNSNames:     []string{"localhost"},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatalf("error creating certificate: %v", err)
+		}
+		return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	}
+	return ca, issue(2, x509.ExtKeyUsageServerAuth), issue(3, x509.ExtKeyUsageClientAuth)
+}
+
 func testingServer(t *testing.T) string {
 	var bin = "seal-meta-" + runtime.GOOS + "-" + runtime.GOARCH
 	var path, _ = filepath.Abs(filepath.Join("../bin", bin))
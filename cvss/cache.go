@@ -0,0 +1,206 @@
+package cvss
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/seal-io/meta-api/cvss/compatible"
+)
+
+// Scored holds the parsed CVSS vector and its scores.
+type Scored struct {
+	Vector                compatible.Vector
+	BaseScore             float64
+	BaseSeverity          string
+	TemporalScore         float64
+	TemporalSeverity      string
+	EnvironmentalScore    float64
+	EnvironmentalSeverity string
+}
+
+// Cache caches the parsed CVSS vectors and their scores by the canonical vector string,
+// and evicts the least recently used one if exceeding the capacity,
+// it is safe for concurrent use.
+// The non-canonical vector strings are remembered as the aliases of the canonical ones,
+// which do not occupy the capacity of the canonical ones,
+// and are dropped all together if exceeding the same capacity.
+type Cache struct {
+	capacity int
+
+	m       sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+	aliases map[string]string
+}
+
+type cacheEntry struct {
+	key    string
+	scored *Scored
+}
+
+// DefaultCacheCapacity is the capacity of the Cache if the given capacity is not positive.
+const DefaultCacheCapacity = 16384
+
+// NewCache returns a Cache holding at most the given capacity of vector strings,
+// DefaultCacheCapacity is used if the given capacity is not positive.
+func NewCache(capacity int) *Cache {
+	if capacity <= 0 {
+		capacity = DefaultCacheCapacity
+	}
+	return &Cache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element, capacity),
+		order:    list.New(),
+		aliases:  map[string]string{},
+	}
+}
+
+// Len returns the count of the cached canonical vector strings, the aliases are not counted.
+func (in *Cache) Len() int {
+	in.m.Lock()
+	defer in.m.Unlock()
+	return in.order.Len()
+}
+
+// Score returns the Scored of the given vector string,
+// it parses and scores the vector string on missing,
+// and caches the result by the canonical vector string,
+// also remembers the given vector string as an alias if it is not canonical.
+func (in *Cache) Score(s string) (Scored, error) {
+	var r, err = in.score(s)
+	if err != nil {
+		return Scored{}, err
+	}
+	return *r, nil
+}
+
+// ScoreBatch returns the Scored of the given vector strings in order,
+// the returning error is BatchErrors if any vector string cannot be parsed,
+// and the other items of the returning Scored list are still valid,
+// the item of the failed vector string is nil.
+// The returning items are shared with the Cache to avoid copying, which must not be modified.
+func (in *Cache) ScoreBatch(ss []string) ([]*Scored, error) {
+	var rs = make([]*Scored, len(ss))
+	var errs BatchErrors
+	for i := range ss {
+		var r, err = in.score(ss[i])
+		if err != nil {
+			errs = append(errs, BatchError{Index: i, Vector: ss[i], Err: err})
+			continue
+		}
+		rs[i] = r
+	}
+	if len(errs) != 0 {
+		return rs, errs
+	}
+	return rs, nil
+}
+
+func (in *Cache) score(s string) (*Scored, error) {
+	s = strings.TrimSpace(s)
+	if r, ok := in.get(s); ok {
+		return r, nil
+	}
+
+	var v, err = Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	var r = &Scored{Vector: v}
+	r.BaseScore, r.BaseSeverity,
+		r.TemporalScore, r.TemporalSeverity,
+		r.EnvironmentalScore, r.EnvironmentalSeverity = v.ScoreAndSeverity()
+
+	var k = v.String()
+	in.m.Lock()
+	defer in.m.Unlock()
+	in.put(k, r)
+	if s != k {
+		if len(in.aliases) >= in.capacity {
+			in.aliases = map[string]string{}
+		}
+		in.aliases[s] = k
+	}
+	return r, nil
+}
+
+// get returns the cached Scored of the given canonical vector string or alias.
+func (in *Cache) get(k string) (*Scored, bool) {
+	in.m.Lock()
+	defer in.m.Unlock()
+	var e, ok = in.entries[k]
+	if !ok {
+		var ck, isAlias = in.aliases[k]
+		if !isAlias {
+			return nil, false
+		}
+		e, ok = in.entries[ck]
+		if !ok {
+			// NB: the canonical one has been evicted.
+			delete(in.aliases, k)
+			return nil, false
+		}
+	}
+	in.order.MoveToFront(e)
+	return e.Value.(*cacheEntry).scored, true
+}
+
+// put must be called with holding the lock.
+func (in *Cache) put(k string, r *Scored) {
+	if e, ok := in.entries[k]; ok {
+		e.Value.(*cacheEntry).scored = r
+		in.order.MoveToFront(e)
+		return
+	}
+	in.entries[k] = in.order.PushFront(&cacheEntry{key: k, scored: r})
+	for in.order.Len() > in.capacity {
+		var e = in.order.Back()
+		in.order.Remove(e)
+		delete(in.entries, e.Value.(*cacheEntry).key)
+	}
+}
+
+// BatchError holds the error of a vector string in the batch.
+type BatchError struct {
+	// Index is the index of the vector string in the batch.
+	Index int
+	// Vector is the vector string.
+	Vector string
+	Err    error
+}
+
+// Error implements error.
+func (e BatchError) Error() string {
+	return fmt.Sprintf("error scoring vector #%d %q: %v", e.Index, e.Vector, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e BatchError) Unwrap() error {
+	return e.Err
+}
+
+// BatchErrors holds the errors of the batch in order.
+type BatchErrors []BatchError
+
+// Error implements error.
+func (e BatchErrors) Error() string {
+	var sb strings.Builder
+	for i := range e {
+		if i > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(e[i].Error())
+	}
+	return sb.String()
+}
+
+// Indexes returns the indexes of the failed vector strings.
+func (e BatchErrors) Indexes() []int {
+	var r = make([]int, 0, len(e))
+	for i := range e {
+		r = append(r, e[i].Index)
+	}
+	return r
+}
@@ -0,0 +1,191 @@
+package cvss
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestCache_Score(t *testing.T) {
+	var c = NewCache(3)
+
+	var r, err = c.Score(" CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var expected = Scored{
+		Vector:                ShouldParse("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"),
+		BaseScore:             9.8,
+		BaseSeverity:          "CRITICAL",
+		TemporalScore:         9.8,
+		TemporalSeverity:      "CRITICAL",
+		EnvironmentalScore:    9.8,
+		EnvironmentalSeverity: "CRITICAL",
+	}
+	if !reflect.DeepEqual(r, expected) {
+		t.Errorf("score == %#v, but got %#v", expected, r)
+	}
+	if c.Len() != 1 {
+		t.Errorf("cached size should be 1, but got %d", c.Len())
+	}
+
+	// non-canonical vector string is remembered as an alias without occupying the capacity.
+	_, _ = c.Score("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/E:X")
+	if c.Len() != 1 {
+		t.Errorf("cached size should be 1, but got %d", c.Len())
+	}
+
+	// least recently used vector string is evicted, and so is its alias.
+	_, _ = c.Score("AV:N/AC:L/Au:N/C:P/I:P/A:P")
+	_, _ = c.Score("CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N")
+	if c.Len() != 3 {
+		t.Errorf("cached size should be 3, but got %d", c.Len())
+	}
+	if _, actual := c.get("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/E:X"); !actual {
+		t.Errorf("alias should be cached")
+	}
+	_, _ = c.Score("CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H")
+	_, _ = c.Score("AV:N/AC:L/Au:N/C:P/I:P/A:P")
+	_, _ = c.Score("CVSS:3.1/AV:L/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H")
+	if c.Len() != 3 {
+		t.Errorf("cached size should be 3, but got %d", c.Len())
+	}
+	for k, expected := range map[string]bool{
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H":                    false,
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/E:X":                false,
+		"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N": false,
+		"CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H":                    true,
+		"AV:N/AC:L/Au:N/C:P/I:P/A:P":                                      true,
+		"CVSS:3.1/AV:L/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H":                    true,
+	} {
+		if _, actual := c.get(k); actual != expected {
+			t.Errorf("cached %s == %v, but got %v", k, expected, actual)
+		}
+	}
+
+	// aliases are bounded by the capacity.
+	for _, s := range []string{"E:X", "RL:X", "RC:X", "CR:X"} {
+		_, _ = c.Score("CVSS:3.1/AV:L/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/" + s)
+	}
+	if len(c.aliases) > 3 {
+		t.Errorf("aliases size should not exceed 3, but got %d", len(c.aliases))
+	}
+
+	if _, err = c.Score("CVSS:3.1/AV:N"); err == nil {
+		t.Errorf("error should be returned for illegal vector")
+	}
+	if c.Len() != 3 {
+		t.Errorf("cached size should be 3, but got %d", c.Len())
+	}
+}
+
+func TestCache_ScoreBatch(t *testing.T) {
+	var c = NewCache(0)
+	var rs, err = c.ScoreBatch([]string{
+		"AV:N/AC:L/Au:N/C:P/I:P/A:P",
+		"CVSS:3.1/AV:N",
+		"CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+		"",
+		"AV:N/AC:L/Au:N/C:P/I:P/A:P",
+	})
+	if len(rs) != 5 {
+		t.Fatalf("result size should be 5, but got %d", len(rs))
+	}
+	var errs BatchErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error should be BatchErrors, but got %v", err)
+	}
+	if actual := errs.Indexes(); !reflect.DeepEqual(actual, []int{1, 3}) {
+		t.Errorf("failed indexes == [1 3], but got %v", actual)
+	}
+	for i, expected := range []float64{7.5, 0, 9.8, 0, 7.5} {
+		if expected == 0 {
+			if rs[i] != nil {
+				t.Errorf("failed item #%d should be nil, but got %v", i, rs[i])
+			}
+			continue
+		}
+		if rs[i] == nil || rs[i].BaseScore != expected {
+			t.Errorf("base score of #%d == %v, but got %v", i, expected, rs[i])
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("cached size should be 2, but got %d", c.Len())
+	}
+}
+
+func TestCache_concurrency(t *testing.T) {
+	var c = NewCache(8)
+	var vs = benchmarkVectors(32, 1)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := range vs {
+				var s = vs[(i+g)%len(vs)]
+				var r, err = c.Score(s)
+				if err != nil {
+					t.Errorf("unexpected error of %s: %v", s, err)
+					return
+				}
+				if r.Vector.String() != s {
+					t.Errorf("vector == %s, but got %s", s, r.Vector.String())
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if c.Len() > 8 {
+		t.Errorf("cached size should not exceed 8, but got %d", c.Len())
+	}
+}
+
+// benchmarkVectors returns the given distinct count of CVSS(V3.1) vector strings,
+// each of them is repeated by the given times.
+func benchmarkVectors(distinct, repeat int) []string {
+	var avs = []string{"N", "A", "L", "P"}
+	var cias = []string{"N", "L", "H"}
+	var r = make([]string, 0, distinct*repeat)
+	for i := 0; i < repeat; i++ {
+		for j := 0; j < distinct; j++ {
+			r = append(r, fmt.Sprintf("CVSS:3.1/AV:%s/AC:L/PR:N/UI:N/S:U/C:%s/I:%s/A:%s/E:P",
+				avs[j%4], cias[(j/4)%3], cias[(j/12)%3], cias[(j/36)%3]))
+		}
+	}
+	return r
+}
+
+func BenchmarkParse(b *testing.B) {
+	var vs = benchmarkVectors(100, 100)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range vs {
+			var v, _ = Parse(s)
+			v.ScoreAndSeverity()
+		}
+	}
+}
+
+func BenchmarkCache_Score(b *testing.B) {
+	var vs = benchmarkVectors(100, 100)
+	var c = NewCache(0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, s := range vs {
+			_, _ = c.Score(s)
+		}
+	}
+}
+
+func BenchmarkCache_ScoreBatch(b *testing.B) {
+	var vs = benchmarkVectors(100, 100)
+	var c = NewCache(0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = c.ScoreBatch(vs)
+	}
+}
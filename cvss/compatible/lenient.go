@@ -0,0 +1,128 @@
+package compatible
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseWarningKind is the kind of the ParseWarning.
+type ParseWarningKind string
+
+// constants of ParseWarningKind.
+const (
+	// ParseWarningParenthesized means the enclosing parentheses are stripped.
+	ParseWarningParenthesized ParseWarningKind = "parenthesized"
+	// ParseWarningTrailingSlash means the trailing slash is stripped.
+	ParseWarningTrailingSlash ParseWarningKind = "trailing-slash"
+	// ParseWarningLowercase means the metric name or value is converted to the canonical case.
+	ParseWarningLowercase ParseWarningKind = "lowercase"
+	// ParseWarningDuplicated means the duplicated metric is dropped.
+	ParseWarningDuplicated ParseWarningKind = "duplicated"
+	// ParseWarningUnordered means the metrics are reordered.
+	ParseWarningUnordered ParseWarningKind = "unordered"
+	// ParseWarningMissingPrefix means the missing version prefix is added.
+	ParseWarningMissingPrefix ParseWarningKind = "missing-prefix"
+)
+
+// ParseWarning holds a fix-up applied by the lenient parsing.
+type ParseWarning struct {
+	Kind ParseWarningKind
+	// Metric is the abbreviated metric name, e.g. AV,
+	// it is empty if the fix-up is not about a metric.
+	Metric string
+	// Message describes the fix-up.
+	Message string
+}
+
+// String returns the string format of this ParseWarning.
+func (in ParseWarning) String() string {
+	return string(in.Kind) + ": " + in.Message
+}
+
+// NormalizeMetrics splits the given vector string into the metric parts,
+// in form of <abbreviation>:<value>, e.g. AV:N,
+// and normalizes them in the order of the given metric names,
+// which are the canonical abbreviations of the vector, e.g. CVSS, AV, Au.
+//
+// It strips the enclosing parentheses and the trailing slash,
+// converts the metric names and values to the canonical case,
+// drops the duplicated metrics and reorders the metrics,
+// and returns a ParseWarning for each fix-up.
+func NormalizeMetrics(s string, names []string) (parts []string, ws []ParseWarning, err error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		s = strings.TrimSpace(s[1 : len(s)-1])
+		ws = append(ws, ParseWarning{
+			Kind:    ParseWarningParenthesized,
+			Message: "stripped the enclosing parentheses",
+		})
+	}
+	if strings.HasSuffix(s, "/") {
+		s = strings.TrimRight(s, "/")
+		ws = append(ws, ParseWarning{
+			Kind:    ParseWarningTrailingSlash,
+			Message: "stripped the trailing slash",
+		})
+	}
+
+	var indexes = make(map[string]int, len(names))
+	for i := range names {
+		indexes[strings.ToUpper(names[i])] = i
+	}
+	var values = make([]string, len(names))
+	var last = -1
+	var unordered bool
+	for _, part := range strings.Split(s, "/") {
+		var kv = strings.SplitN(part, ":", 2)
+		if len(kv) != 2 {
+			return nil, nil, fmt.Errorf("incomplete metric '%s'", part)
+		}
+		var mn, mv = strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		if mn == "" || mv == "" {
+			return nil, nil, fmt.Errorf("incomplete metric '%s'", part)
+		}
+		var i, exist = indexes[strings.ToUpper(mn)]
+		if !exist {
+			return nil, nil, fmt.Errorf("unknown metric '%s'", mn)
+		}
+		var n, v = names[i], strings.ToUpper(mv)
+		if mn != n || mv != v {
+			ws = append(ws, ParseWarning{
+				Kind:    ParseWarningLowercase,
+				Metric:  n,
+				Message: fmt.Sprintf("converted '%s:%s' to '%s:%s'", mn, mv, n, v),
+			})
+		}
+		if values[i] != "" {
+			if values[i] != v {
+				return nil, nil, fmt.Errorf("conflicting values '%s' and '%s' of metric '%s'", values[i], v, n)
+			}
+			ws = append(ws, ParseWarning{
+				Kind:    ParseWarningDuplicated,
+				Metric:  n,
+				Message: fmt.Sprintf("dropped the duplicated '%s:%s'", n, v),
+			})
+			continue
+		}
+		values[i] = v
+		if i < last {
+			unordered = true
+		}
+		last = i
+	}
+	if unordered {
+		ws = append(ws, ParseWarning{
+			Kind:    ParseWarningUnordered,
+			Message: "reordered the metrics",
+		})
+	}
+
+	parts = make([]string, 0, len(names))
+	for i := range names {
+		if values[i] == "" {
+			continue
+		}
+		parts = append(parts, names[i]+":"+values[i])
+	}
+	return parts, ws, nil
+}
@@ -0,0 +1,72 @@
+package compatible
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeMetrics(t *testing.T) {
+	type output struct {
+		parts    string
+		warnings string
+		err      string
+	}
+	var names = []string{"CVSS", "AV", "Au", "E"}
+	var testCases = []struct {
+		given    string
+		expected output
+	}{
+		{
+			given: "CVSS:3.1/AV:N/E:P",
+			expected: output{
+				parts: "CVSS:3.1/AV:N/E:P",
+			},
+		},
+		{
+			given: " ( e:p/AU:n/AV:N/E:P/ ) ",
+			expected: output{
+				parts: "AV:N/Au:N/E:P",
+				warnings: "parenthesized: stripped the enclosing parentheses; " +
+					"trailing-slash: stripped the trailing slash; " +
+					"lowercase: converted 'e:p' to 'E:P'; " +
+					"lowercase: converted 'AU:n' to 'Au:N'; " +
+					"duplicated: dropped the duplicated 'E:P'; " +
+					"unordered: reordered the metrics",
+			},
+		},
+		{
+			given: "AV:N/AV:L",
+			expected: output{
+				err: "conflicting values 'N' and 'L' of metric 'AV'",
+			},
+		},
+		{
+			given: "AV:N/PR:L",
+			expected: output{
+				err: "unknown metric 'PR'",
+			},
+		},
+		{
+			given: "AV:N/E",
+			expected: output{
+				err: "incomplete metric 'E'",
+			},
+		},
+	}
+	for _, c := range testCases {
+		var parts, ws, err = NormalizeMetrics(c.given, names)
+		var actual output
+		if err != nil {
+			actual.err = err.Error()
+		}
+		actual.parts = strings.Join(parts, "/")
+		var wss = make([]string, 0, len(ws))
+		for i := range ws {
+			wss = append(wss, ws[i].String())
+		}
+		actual.warnings = strings.Join(wss, "; ")
+		if actual != c.expected {
+			t.Errorf("normalize %q == %#v, but got %#v", c.given, c.expected, actual)
+		}
+	}
+}
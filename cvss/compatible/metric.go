@@ -0,0 +1,99 @@
+package compatible
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MetricGroup is the group of the Metric.
+type MetricGroup string
+
+// constants of MetricGroup.
+const (
+	MetricGroupBase          MetricGroup = "base"
+	MetricGroupTemporal      MetricGroup = "temporal"
+	MetricGroupThreat        MetricGroup = "threat"
+	MetricGroupEnvironmental MetricGroup = "environmental"
+	MetricGroupSupplemental  MetricGroup = "supplemental"
+)
+
+// Metric holds the chosen value of a metric of the CVSS vector.
+type Metric struct {
+	// Group is the group of the metric.
+	Group MetricGroup
+	// Abbreviation is the abbreviated metric name in the vector string, e.g. AV.
+	Abbreviation string
+	// Name is the full metric name, e.g. Attack Vector.
+	Name string
+	// Value is the abbreviated metric value in the vector string, e.g. N.
+	Value string
+	// ValueName is the full metric value, e.g. Network.
+	ValueName string
+	// Weight is the numeric weight of the value used in the score calculating,
+	// the weight of the base metric is used if the modified metric is not defined,
+	// CVSS(V4) metrics are not weighted, so it is always 0.
+	Weight float64
+}
+
+// GetMetric returns the Metric of the given abbreviation from the given Vector.
+func GetMetric(v Vector, abbreviation string) (Metric, bool) {
+	if v == nil {
+		return Metric{}, false
+	}
+	var ms = v.Metrics()
+	for i := range ms {
+		if ms[i].Abbreviation == abbreviation {
+			return ms[i], true
+		}
+	}
+	return Metric{}, false
+}
+
+// Explanation holds the derivation of the scores of the CVSS vector.
+type Explanation struct {
+	// Version is the cvss version of the vector.
+	Version string
+	// Steps is the derived values in order of calculating.
+	Steps []Step
+}
+
+// Step holds a derived value of the score calculating.
+type Step struct {
+	// Group is the metric group which the derived value belongs to.
+	Group MetricGroup
+	// Name is the name of the derived value, e.g. Impact Subscore.
+	Name string
+	// Formula is the formula of the derived value, e.g. 6.42 × ISS.
+	Formula string
+	// Expression is the Formula with the operands substituted, e.g. 6.42 × 0.8064.
+	Expression string
+	// Value is the derived value.
+	Value float64
+}
+
+// String returns the explanation in lines, each line is formatted as "Name = Formula = Expression = Value".
+func (in Explanation) String() string {
+	var sb strings.Builder
+	for i, s := range in.Steps {
+		if i != 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(s.Name)
+		sb.WriteString(" = ")
+		sb.WriteString(s.Formula)
+		if s.Expression != "" && s.Expression != s.Formula {
+			sb.WriteString(" = ")
+			sb.WriteString(s.Expression)
+		}
+		sb.WriteString(" = ")
+		sb.WriteString(FormatFloat(s.Value))
+	}
+	return sb.String()
+}
+
+// FormatFloat formats the given float in the shortest decimal representation,
+// which keeps at most six decimal places to hide the floating error.
+func FormatFloat(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1e6)/1e6, 'f', -1, 64)
+}
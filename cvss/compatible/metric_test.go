@@ -0,0 +1,62 @@
+package compatible
+
+import (
+	"testing"
+)
+
+func TestExplanation_String(t *testing.T) {
+	var testCases = []struct {
+		given    Explanation
+		expected string
+	}{
+		{
+			given:    Explanation{},
+			expected: "",
+		},
+		{
+			given: Explanation{
+				Version: "3.1",
+				Steps: []Step{
+					{
+						Group:      MetricGroupBase,
+						Name:       "Impact",
+						Formula:    "6.42 × ISS",
+						Expression: "6.42 × 0.5",
+						Value:      3.21,
+					},
+					{
+						Group:   MetricGroupBase,
+						Name:    "Base Score",
+						Formula: "0 if Impact <= 0",
+						Value:   0,
+					},
+				},
+			},
+			expected: "Impact = 6.42 × ISS = 6.42 × 0.5 = 3.21\nBase Score = 0 if Impact <= 0 = 0",
+		},
+	}
+	for _, c := range testCases {
+		var actual = c.given.String()
+		if actual != c.expected {
+			t.Errorf("String() == %q, but got %q", c.expected, actual)
+		}
+	}
+}
+
+func TestFormatFloat(t *testing.T) {
+	var testCases = []struct {
+		given    float64
+		expected string
+	}{
+		{given: 0, expected: "0"},
+		{given: 1.08, expected: "1.08"},
+		{given: 0.8064000000000001, expected: "0.8064"},
+		{given: 6.0477304915445185, expected: "6.04773"},
+	}
+	for _, c := range testCases {
+		var actual = FormatFloat(c.given)
+		if actual != c.expected {
+			t.Errorf("FormatFloat(%v) == %s, but got %s", c.given, c.expected, actual)
+		}
+	}
+}
@@ -33,6 +33,11 @@ type Vector interface {
 	// - TemporalScore and TemporalSeverity
 	// - EnvironmentalScore and EnvironmentalSeverity
 	ScoreAndSeverity() (bs float64, bsv string, ts float64, tsv string, es float64, esv string)
+	// Metrics returns the metrics of this CVSS vector in order of the vector string,
+	// including the not defined optional metrics.
+	Metrics() []Metric
+	// Explain returns the derivation of the scores of this CVSS vector.
+	Explain() Explanation
 
 	// GetVersion returns the cvss version of this CVSS vector.
 	GetVersion() string
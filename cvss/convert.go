@@ -0,0 +1,451 @@
+package cvss
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/seal-io/meta-api/cvss/compatible"
+	"github.com/seal-io/meta-api/cvss/cvssv2"
+	"github.com/seal-io/meta-api/cvss/cvssv3"
+	"github.com/seal-io/meta-api/cvss/cvssv4"
+)
+
+// FidelityKind is the kind of the FidelityEntry.
+type FidelityKind string
+
+// constants of FidelityKind.
+const (
+	// FidelityApproximated means the metric is mapped to a non-equivalent value,
+	// or to the same value in a different meaning.
+	FidelityApproximated FidelityKind = "approximated"
+	// FidelityDefaulted means the metric cannot be derived, and is set to the default value.
+	FidelityDefaulted FidelityKind = "defaulted"
+	// FidelityDropped means the metric cannot be represented, and is dropped.
+	FidelityDropped FidelityKind = "dropped"
+)
+
+// FidelityEntry holds a metric which is not converted exactly.
+type FidelityEntry struct {
+	Kind FidelityKind
+	// From is the source metric in form of <abbreviation>:<value>, e.g. AC:M,
+	// it is empty if the metric is defaulted.
+	From string
+	// To is the target metric in form of <abbreviation>:<value>, e.g. AC:L,
+	// it is empty if the metric is dropped.
+	To string
+	// Note explains the entry if the value is kept but its meaning is changed, optional.
+	Note string
+}
+
+// String returns the string format of this FidelityEntry.
+func (in FidelityEntry) String() string {
+	var from, to = in.From, in.To
+	if from == "" {
+		from = "-"
+	}
+	if to == "" {
+		to = "-"
+	}
+	var r = string(in.Kind) + " " + from + " -> " + to
+	if in.Note != "" {
+		r += " (" + in.Note + ")"
+	}
+	return r
+}
+
+// Fidelity holds the report of the conversion.
+type Fidelity struct {
+	// From is the version of the source vector.
+	From string
+	// To is the version of the target vector.
+	To string
+	// Entries is the metrics which are not converted exactly,
+	// including the intermediate version metrics if converting between CVSS(V2) and CVSS(V4).
+	Entries []FidelityEntry
+}
+
+// IsExact returns true if all metrics are converted exactly.
+func (in Fidelity) IsExact() bool {
+	return len(in.Entries) == 0
+}
+
+func (in *Fidelity) add(k FidelityKind, from, to string) {
+	in.Entries = append(in.Entries, FidelityEntry{Kind: k, From: from, To: to})
+}
+
+func (in *Fidelity) addNote(k FidelityKind, from, to, note string) {
+	in.Entries = append(in.Entries, FidelityEntry{Kind: k, From: from, To: to, Note: note})
+}
+
+// Convert converts the given vector to the given version, e.g. 2.0, 3.0, 3.1 or 4.0,
+// the conversion between CVSS(V2) and CVSS(V4) goes through CVSS(V3.1),
+// and returns the Fidelity to indicate which metrics are approximated, defaulted or dropped.
+func Convert(v compatible.Vector, version string) (compatible.Vector, Fidelity, error) {
+	if v == nil {
+		return nil, Fidelity{}, errors.New("nil vector")
+	}
+	var f = Fidelity{From: v.GetVersion(), To: version}
+
+	switch version {
+	default:
+		return nil, Fidelity{}, fmt.Errorf("unknown CVSS version: %s", version)
+	case "2.0":
+		switch t := v.(type) {
+		case cvssv2.Vector:
+			return t, f, nil
+		case cvssv3.Vector:
+			return v3ToV2(t, &f), f, nil
+		case cvssv4.Vector:
+			return v3ToV2(v4ToV3(t, &f), &f), f, nil
+		}
+	case string(cvssv3.Version30), string(cvssv3.Version31):
+		var r cvssv3.Vector
+		switch t := v.(type) {
+		case cvssv2.Vector:
+			r = v2ToV3(t, &f)
+		case cvssv3.Vector:
+			r = cvssv3.DefaultVector().Override(t)
+		case cvssv4.Vector:
+			r = v4ToV3(t, &f)
+		default:
+			return nil, Fidelity{}, fmt.Errorf("unknown CVSS vector: %T", v)
+		}
+		r.Version = cvssv3.Version(version)
+		return r, f, nil
+	case string(cvssv4.Version40):
+		switch t := v.(type) {
+		case cvssv2.Vector:
+			return v3ToV4(v2ToV3(t, &f), &f), f, nil
+		case cvssv3.Vector:
+			return v3ToV4(t, &f), f, nil
+		case cvssv4.Vector:
+			return t, f, nil
+		}
+	}
+	return nil, Fidelity{}, fmt.Errorf("unknown CVSS vector: %T", v)
+}
+
+// v2ToV3 converts the CVSS(V2) vector via cvssv2.Vector.ToLatest.
+func v2ToV3(in cvssv2.Vector, f *Fidelity) cvssv3.Vector {
+	var s = cvssv2.DefaultVector().Override(in)
+	var out = s.ToLatest().(cvssv3.Vector)
+
+	switch s.AccessComplexity {
+	case cvssv2.AccessComplexityHigh, cvssv2.AccessComplexityMedium:
+		f.add(FidelityApproximated, "AC:"+string(s.AccessComplexity),
+			"AC:"+string(out.AttackComplexity)+"/UI:"+string(out.UserInteraction))
+	}
+	switch {
+	case s.Authentication == cvssv2.AuthenticationNone && out.PrivilegesRequired == cvssv3.PrivilegesRequiredNone:
+	default:
+		f.add(FidelityApproximated, "Au:"+string(s.Authentication), "PR:"+string(out.PrivilegesRequired))
+	}
+	if out.Scope == cvssv3.ScopeChanged {
+		f.add(FidelityApproximated, "", "S:"+string(out.Scope))
+	} else {
+		f.add(FidelityDefaulted, "", "S:"+string(out.Scope))
+	}
+	for _, m := range [][4]string{
+		{"C", string(s.ConfidentialityImpact), "C", string(out.ConfidentialityImpact)},
+		{"I", string(s.IntegrityImpact), "I", string(out.IntegrityImpact)},
+		{"A", string(s.AvailabilityImpact), "A", string(out.AvailabilityImpact)},
+	} {
+		if m[1] == "P" {
+			f.add(FidelityApproximated, m[0]+":"+m[1], m[2]+":"+m[3])
+		}
+	}
+	if s.CollateralDamagePotential != cvssv2.CollateralDamagePotentialNotDefined {
+		f.add(FidelityDropped, "CDP:"+string(s.CollateralDamagePotential), "")
+	}
+	if s.TargetDistribution != cvssv2.TargetDistributionNotDefined {
+		f.add(FidelityDropped, "TD:"+string(s.TargetDistribution), "")
+	}
+	return out
+}
+
+func v3ToV2(in cvssv3.Vector, f *Fidelity) cvssv2.Vector {
+	var s = cvssv3.DefaultVector().Override(in)
+	var out = cvssv2.DefaultVector()
+
+	// basic metrics
+	switch s.AttackVector {
+	case cvssv3.AttackVectorPhysical:
+		out.AccessVector = cvssv2.AccessVectorLocal
+		f.add(FidelityApproximated, "AV:P", "AV:L")
+	case cvssv3.AttackVectorLocal:
+		out.AccessVector = cvssv2.AccessVectorLocal
+	case cvssv3.AttackVectorAdjacent:
+		out.AccessVector = cvssv2.AccessVectorAdjacentNetwork
+	case cvssv3.AttackVectorNetwork:
+		out.AccessVector = cvssv2.AccessVectorNetwork
+	}
+	switch {
+	case s.AttackComplexity == cvssv3.AttackComplexityHigh:
+		out.AccessComplexity = cvssv2.AccessComplexityHigh
+		if s.UserInteraction == cvssv3.UserInteractionRequired {
+			f.add(FidelityApproximated, "AC:H/UI:R", "AC:H")
+		}
+	case s.UserInteraction == cvssv3.UserInteractionRequired:
+		out.AccessComplexity = cvssv2.AccessComplexityMedium
+		f.add(FidelityApproximated, "AC:L/UI:R", "AC:M")
+	default:
+		out.AccessComplexity = cvssv2.AccessComplexityLow
+	}
+	switch s.PrivilegesRequired {
+	case cvssv3.PrivilegesRequiredHigh:
+		out.Authentication = cvssv2.AuthenticationMultiple
+		f.add(FidelityApproximated, "PR:H", "Au:M")
+	case cvssv3.PrivilegesRequiredLow:
+		out.Authentication = cvssv2.AuthenticationSingle
+		f.add(FidelityApproximated, "PR:L", "Au:S")
+	case cvssv3.PrivilegesRequiredNone:
+		out.Authentication = cvssv2.AuthenticationNone
+	}
+	if s.Scope == cvssv3.ScopeChanged {
+		f.add(FidelityDropped, "S:C", "")
+	}
+	var impact = func(v string) string {
+		switch v {
+		case "H":
+			return "C"
+		case "L":
+			return "P"
+		}
+		return "N"
+	}
+	out.ConfidentialityImpact = cvssv2.ConfidentialityImpact(impact(string(s.ConfidentialityImpact)))
+	out.IntegrityImpact = cvssv2.IntegrityImpact(impact(string(s.IntegrityImpact)))
+	out.AvailabilityImpact = cvssv2.AvailabilityImpact(impact(string(s.AvailabilityImpact)))
+
+	// temporal metrics
+	out.Exploitability = map[cvssv3.ExploitCodeMaturity]cvssv2.Exploitability{
+		cvssv3.ExploitCodeMaturityNotDefined:     cvssv2.ExploitabilityNotDefined,
+		cvssv3.ExploitCodeMaturityUnproven:       cvssv2.ExploitabilityUnproven,
+		cvssv3.ExploitCodeMaturityProofOfConcept: cvssv2.ExploitabilityProofOfConcept,
+		cvssv3.ExploitCodeMaturityFunctional:     cvssv2.ExploitabilityFunctional,
+		cvssv3.ExploitCodeMaturityHigh:           cvssv2.ExploitabilityHigh,
+	}[s.ExploitCodeMaturity]
+	out.RemediationLevel = map[cvssv3.RemediationLevel]cvssv2.RemediationLevel{
+		cvssv3.RemediationLevelNotDefined:   cvssv2.RemediationLevelNotDefined,
+		cvssv3.RemediationLevelOfficialFix:  cvssv2.RemediationLevelOfficialFix,
+		cvssv3.RemediationLevelTemporaryFix: cvssv2.RemediationLevelTemporaryFix,
+		cvssv3.RemediationLevelWorkaround:   cvssv2.RemediationLevelWorkaround,
+		cvssv3.RemediationLevelUnavailable:  cvssv2.RemediationLevelUnavailable,
+	}[s.RemediationLevel]
+	out.ReportConfidence = map[cvssv3.ReportConfidence]cvssv2.ReportConfidence{
+		cvssv3.ReportConfidenceNotDefined: cvssv2.ReportConfidenceNotDefined,
+		cvssv3.ReportConfidenceUnknown:    cvssv2.ReportConfidenceUnconfirmed,
+		cvssv3.ReportConfidenceReasonable: cvssv2.ReportConfidenceUncorroborated,
+		cvssv3.ReportConfidenceConfirmed:  cvssv2.ReportConfidenceConfirmed,
+	}[s.ReportConfidence]
+
+	// environmental metrics
+	var requirement = func(v cvssv3.SecurityRequirement) cvssv2.SecurityRequirement {
+		if v == cvssv3.SecurityRequirementNotDefined {
+			return cvssv2.SecurityRequirementNotDefined
+		}
+		return cvssv2.SecurityRequirement(v)
+	}
+	out.ConfidentialityRequirement = requirement(s.ConfidentialityRequirement)
+	out.IntegrityRequirement = requirement(s.IntegrityRequirement)
+	out.AvailabilityRequirement = requirement(s.AvailabilityRequirement)
+	for _, m := range [][2]string{
+		{"MAV", string(s.ModifiedAttackVector)},
+		{"MAC", string(s.ModifiedAttackComplexity)},
+		{"MPR", string(s.ModifiedPrivilegesRequired)},
+		{"MUI", string(s.ModifiedUserInteraction)},
+		{"MS", string(s.ModifiedScope)},
+		{"MC", string(s.ModifiedConfidentiality)},
+		{"MI", string(s.ModifiedIntegrity)},
+		{"MA", string(s.ModifiedAvailability)},
+	} {
+		if m[1] != "X" {
+			f.add(FidelityDropped, m[0]+":"+m[1], "")
+		}
+	}
+	return out
+}
+
+func v3ToV4(in cvssv3.Vector, f *Fidelity) cvssv4.Vector {
+	var s = cvssv3.DefaultVector().Override(in)
+	var out = cvssv4.DefaultVector()
+
+	// base metrics
+	out.AttackVector = cvssv4.AttackVector(s.AttackVector)
+	out.AttackComplexity = cvssv4.AttackComplexity(s.AttackComplexity)
+	out.AttackRequirements = cvssv4.AttackRequirementsNone
+	f.add(FidelityDefaulted, "", "AT:N")
+	out.PrivilegesRequired = cvssv4.PrivilegesRequired(s.PrivilegesRequired)
+	out.UserInteraction = cvssv4.UserInteraction(userInteractionV3ToV4(string(s.UserInteraction), "UI", f))
+	out.VulnerableSystemConfidentiality = cvssv4.VulnerableSystemImpact(s.ConfidentialityImpact)
+	out.VulnerableSystemIntegrity = cvssv4.VulnerableSystemImpact(s.IntegrityImpact)
+	out.VulnerableSystemAvailability = cvssv4.VulnerableSystemImpact(s.AvailabilityImpact)
+	// NB: the impacts of the changed scope are regarded as the subsequent system impacts.
+	out.SubsequentSystemConfidentiality = cvssv4.SubsequentSystemImpactNone
+	out.SubsequentSystemIntegrity = cvssv4.SubsequentSystemImpactNone
+	out.SubsequentSystemAvailability = cvssv4.SubsequentSystemImpactNone
+	if s.Scope == cvssv3.ScopeChanged {
+		out.SubsequentSystemConfidentiality = cvssv4.SubsequentSystemImpact(s.ConfidentialityImpact)
+		out.SubsequentSystemIntegrity = cvssv4.SubsequentSystemImpact(s.IntegrityImpact)
+		out.SubsequentSystemAvailability = cvssv4.SubsequentSystemImpact(s.AvailabilityImpact)
+		f.add(FidelityApproximated, "S:C", fmt.Sprintf("SC:%s/SI:%s/SA:%s",
+			out.SubsequentSystemConfidentiality, out.SubsequentSystemIntegrity, out.SubsequentSystemAvailability))
+	}
+
+	// threat metrics
+	switch s.ExploitCodeMaturity {
+	case cvssv3.ExploitCodeMaturityNotDefined:
+		out.ExploitMaturity = cvssv4.ExploitMaturityNotDefined
+	case cvssv3.ExploitCodeMaturityProofOfConcept:
+		out.ExploitMaturity = cvssv4.ExploitMaturityProofOfConcept
+	case cvssv3.ExploitCodeMaturityUnproven:
+		out.ExploitMaturity = cvssv4.ExploitMaturityUnreported
+		f.addNote(FidelityApproximated, "E:U", "E:U", "Unproven is mapped to Unreported")
+	default:
+		out.ExploitMaturity = cvssv4.ExploitMaturityAttacked
+		f.add(FidelityApproximated, "E:"+string(s.ExploitCodeMaturity), "E:A")
+	}
+	if s.RemediationLevel != cvssv3.RemediationLevelNotDefined {
+		f.add(FidelityDropped, "RL:"+string(s.RemediationLevel), "")
+	}
+	if s.ReportConfidence != cvssv3.ReportConfidenceNotDefined {
+		f.add(FidelityDropped, "RC:"+string(s.ReportConfidence), "")
+	}
+
+	// environmental metrics
+	out.ConfidentialityRequirement = cvssv4.SecurityRequirement(s.ConfidentialityRequirement)
+	out.IntegrityRequirement = cvssv4.SecurityRequirement(s.IntegrityRequirement)
+	out.AvailabilityRequirement = cvssv4.SecurityRequirement(s.AvailabilityRequirement)
+	out.ModifiedAttackVector = cvssv4.AttackVector(s.ModifiedAttackVector)
+	out.ModifiedAttackComplexity = cvssv4.AttackComplexity(s.ModifiedAttackComplexity)
+	out.ModifiedPrivilegesRequired = cvssv4.PrivilegesRequired(s.ModifiedPrivilegesRequired)
+	out.ModifiedUserInteraction = cvssv4.UserInteraction(userInteractionV3ToV4(string(s.ModifiedUserInteraction), "MUI", f))
+	if s.ModifiedScope != cvssv3.ScopeNotDefined {
+		f.add(FidelityDropped, "MS:"+string(s.ModifiedScope), "")
+	}
+	out.ModifiedVulnerableSystemConfidentiality = cvssv4.VulnerableSystemImpact(s.ModifiedConfidentiality)
+	out.ModifiedVulnerableSystemIntegrity = cvssv4.VulnerableSystemImpact(s.ModifiedIntegrity)
+	out.ModifiedVulnerableSystemAvailability = cvssv4.VulnerableSystemImpact(s.ModifiedAvailability)
+	return out
+}
+
+// userInteractionV3ToV4 maps the required user interaction to the passive user interaction,
+// which is the more severe one of passive and active.
+func userInteractionV3ToV4(v, mn string, f *Fidelity) string {
+	if v != "R" {
+		return v
+	}
+	f.add(FidelityApproximated, mn+":R", mn+":P")
+	return "P"
+}
+
+func v4ToV3(in cvssv4.Vector, f *Fidelity) cvssv3.Vector {
+	var s = cvssv4.DefaultVector().Override(in)
+	var out = cvssv3.DefaultVector()
+
+	// base metrics
+	out.AttackVector = cvssv3.AttackVector(s.AttackVector)
+	out.AttackComplexity = cvssv3.AttackComplexity(s.AttackComplexity)
+	if s.AttackRequirements == cvssv4.AttackRequirementsPresent {
+		out.AttackComplexity = cvssv3.AttackComplexityHigh
+		f.add(FidelityApproximated, "AC:"+string(s.AttackComplexity)+"/AT:P", "AC:H")
+	}
+	out.PrivilegesRequired = cvssv3.PrivilegesRequired(s.PrivilegesRequired)
+	out.UserInteraction = cvssv3.UserInteraction(userInteractionV4ToV3(string(s.UserInteraction), "UI", f))
+	// NB: the scope is changed if the subsequent system is impacted,
+	// and the impacts are the worst of the vulnerable system and the subsequent system.
+	var vs = [3]string{
+		string(s.VulnerableSystemConfidentiality),
+		string(s.VulnerableSystemIntegrity),
+		string(s.VulnerableSystemAvailability),
+	}
+	var ss = [3]string{
+		string(s.SubsequentSystemConfidentiality),
+		string(s.SubsequentSystemIntegrity),
+		string(s.SubsequentSystemAvailability),
+	}
+	var is = vs
+	out.Scope = cvssv3.ScopeUnchanged
+	if ss != [3]string{"N", "N", "N"} {
+		out.Scope = cvssv3.ScopeChanged
+		for i := range is {
+			if impactLevel(ss[i]) > impactLevel(is[i]) {
+				is[i] = ss[i]
+			}
+		}
+		f.add(FidelityApproximated, fmt.Sprintf("SC:%s/SI:%s/SA:%s", ss[0], ss[1], ss[2]),
+			fmt.Sprintf("S:C/C:%s/I:%s/A:%s", is[0], is[1], is[2]))
+	}
+	out.ConfidentialityImpact = cvssv3.ConfidentialityImpact(is[0])
+	out.IntegrityImpact = cvssv3.IntegrityImpact(is[1])
+	out.AvailabilityImpact = cvssv3.AvailabilityImpact(is[2])
+
+	// temporal metrics
+	switch s.ExploitMaturity {
+	case cvssv4.ExploitMaturityNotDefined:
+		out.ExploitCodeMaturity = cvssv3.ExploitCodeMaturityNotDefined
+	case cvssv4.ExploitMaturityProofOfConcept:
+		out.ExploitCodeMaturity = cvssv3.ExploitCodeMaturityProofOfConcept
+	case cvssv4.ExploitMaturityAttacked:
+		out.ExploitCodeMaturity = cvssv3.ExploitCodeMaturityHigh
+		f.add(FidelityApproximated, "E:A", "E:H")
+	case cvssv4.ExploitMaturityUnreported:
+		out.ExploitCodeMaturity = cvssv3.ExploitCodeMaturityUnproven
+		f.addNote(FidelityApproximated, "E:U", "E:U", "Unreported is mapped to Unproven")
+	}
+
+	// environmental metrics
+	out.ConfidentialityRequirement = cvssv3.SecurityRequirement(s.ConfidentialityRequirement)
+	out.IntegrityRequirement = cvssv3.SecurityRequirement(s.IntegrityRequirement)
+	out.AvailabilityRequirement = cvssv3.SecurityRequirement(s.AvailabilityRequirement)
+	out.ModifiedAttackVector = cvssv3.AttackVector(s.ModifiedAttackVector)
+	out.ModifiedAttackComplexity = cvssv3.AttackComplexity(s.ModifiedAttackComplexity)
+	if s.ModifiedAttackRequirements == cvssv4.AttackRequirementsPresent {
+		out.ModifiedAttackComplexity = cvssv3.AttackComplexityHigh
+		f.add(FidelityApproximated, "MAC:"+string(s.ModifiedAttackComplexity)+"/MAT:P", "MAC:H")
+	} else if s.ModifiedAttackRequirements != cvssv4.AttackRequirementsNotDefined {
+		f.add(FidelityDropped, "MAT:"+string(s.ModifiedAttackRequirements), "")
+	}
+	out.ModifiedPrivilegesRequired = cvssv3.PrivilegesRequired(s.ModifiedPrivilegesRequired)
+	out.ModifiedUserInteraction = cvssv3.UserInteraction(userInteractionV4ToV3(string(s.ModifiedUserInteraction), "MUI", f))
+	out.ModifiedConfidentiality = cvssv3.ConfidentialityImpact(s.ModifiedVulnerableSystemConfidentiality)
+	out.ModifiedIntegrity = cvssv3.IntegrityImpact(s.ModifiedVulnerableSystemIntegrity)
+	out.ModifiedAvailability = cvssv3.AvailabilityImpact(s.ModifiedVulnerableSystemAvailability)
+	for _, m := range [][2]string{
+		{"MSC", string(s.ModifiedSubsequentSystemConfidentiality)},
+		{"MSI", string(s.ModifiedSubsequentSystemIntegrity)},
+		{"MSA", string(s.ModifiedSubsequentSystemAvailability)},
+		// supplemental metrics
+		{"S", string(s.Safety)},
+		{"AU", string(s.Automatable)},
+		{"R", string(s.Recovery)},
+		{"V", string(s.ValueDensity)},
+		{"RE", string(s.VulnerabilityResponseEffort)},
+		{"U", string(s.ProviderUrgency)},
+	} {
+		if m[1] != "X" {
+			f.add(FidelityDropped, m[0]+":"+m[1], "")
+		}
+	}
+	return out
+}
+
+// userInteractionV4ToV3 maps the passive or active user interaction to the required user interaction.
+func userInteractionV4ToV3(v, mn string, f *Fidelity) string {
+	if v != "P" && v != "A" {
+		return v
+	}
+	f.add(FidelityApproximated, mn+":"+v, mn+":R")
+	return "R"
+}
+
+// impactLevel returns the comparable level of the given impact value.
+func impactLevel(v string) int {
+	switch v {
+	case "S", "H":
+		return 2
+	case "L":
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,166 @@
+package cvss
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	type input struct {
+		vector  string
+		version string
+	}
+	type output struct {
+		vector  string
+		entries string
+	}
+	var testCases = []struct {
+		given    input
+		expected output
+	}{
+		{
+			given: input{
+				vector:  "AV:N/AC:L/Au:N/C:P/I:P/A:P",
+				version: "3.1",
+			},
+			expected: output{
+				vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+				entries: "[defaulted - -> S:U " +
+					"approximated C:P -> C:H approximated I:P -> I:H approximated A:P -> A:H]",
+			},
+		},
+		{
+			given: input{
+				vector:  "AV:N/AC:M/Au:S/C:P/I:N/A:C/CDP:H",
+				version: "4.0",
+			},
+			expected: output{
+				vector: "CVSS:4.0/AV:N/AC:L/AT:N/PR:L/UI:P/VC:L/VI:N/VA:H/SC:N/SI:N/SA:N",
+				entries: "[approximated AC:M -> AC:L/UI:R approximated Au:S -> PR:L defaulted - -> S:U " +
+					"approximated C:P -> C:L dropped CDP:H -> - " +
+					"defaulted - -> AT:N approximated UI:R -> UI:P]",
+			},
+		},
+		{
+			given: input{
+				vector:  "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+				version: "2.0",
+			},
+			expected: output{
+				vector:  "AV:N/AC:L/Au:N/C:C/I:C/A:C",
+				entries: "[]",
+			},
+		},
+		{
+			given: input{
+				vector:  "CVSS:3.1/AV:P/AC:L/PR:L/UI:R/S:C/C:H/I:L/A:N/E:F/RL:O/MS:U/MAV:N",
+				version: "2.0",
+			},
+			expected: output{
+				vector: "AV:L/AC:M/Au:S/C:C/I:P/A:N/E:F/RL:OF",
+				entries: "[approximated AV:P -> AV:L approximated AC:L/UI:R -> AC:M approximated PR:L -> Au:S " +
+					"dropped S:C -> - dropped MAV:N -> - dropped MS:U -> -]",
+			},
+		},
+		{
+			given: input{
+				vector:  "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H/E:F/RC:C",
+				version: "4.0",
+			},
+			expected: output{
+				vector: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:H/SI:H/SA:H/E:A",
+				entries: "[defaulted - -> AT:N approximated S:C -> SC:H/SI:H/SA:H " +
+					"approximated E:F -> E:A dropped RC:C -> -]",
+			},
+		},
+		{
+			given: input{
+				vector:  "CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+				version: "3.1",
+			},
+			expected: output{
+				vector:  "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+				entries: "[]",
+			},
+		},
+		{
+			given: input{
+				vector:  "CVSS:4.0/AV:N/AC:L/AT:P/PR:N/UI:A/VC:H/VI:L/VA:N/SC:L/SI:H/SA:N/E:A/MSI:S/U:Red",
+				version: "3.0",
+			},
+			expected: output{
+				vector: "CVSS:3.0/AV:N/AC:H/PR:N/UI:R/S:C/C:H/I:H/A:N/E:H",
+				entries: "[approximated AC:L/AT:P -> AC:H approximated UI:A -> UI:R " +
+					"approximated SC:L/SI:H/SA:N -> S:C/C:H/I:H/A:N approximated E:A -> E:H " +
+					"dropped MSI:S -> - dropped U:Red -> -]",
+			},
+		},
+		{
+			given: input{
+				vector:  "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+				version: "2.0",
+			},
+			expected: output{
+				vector:  "AV:N/AC:L/Au:N/C:C/I:C/A:C",
+				entries: "[]",
+			},
+		},
+	}
+	for _, c := range testCases {
+		var v, f, err = Convert(ShouldParse(c.given.vector), c.given.version)
+		if err != nil {
+			t.Errorf("Convert(%s, %s) should not return error, but got %v", c.given.vector, c.given.version, err)
+			continue
+		}
+		var actual = output{
+			vector:  v.String(),
+			entries: fmt.Sprint(f.Entries),
+		}
+		if actual != c.expected {
+			t.Errorf("Convert(%s, %s) == %#v, but got %#v", c.given.vector, c.given.version, c.expected, actual)
+		}
+		if f.IsExact() != (len(f.Entries) == 0) || f.To != c.given.version {
+			t.Errorf("Convert(%s, %s) returns invalid fidelity %#v", c.given.vector, c.given.version, f)
+		}
+	}
+
+	if _, _, err := Convert(nil, "3.1"); err == nil {
+		t.Errorf("Convert(nil, 3.1) should return error")
+	}
+	if _, _, err := Convert(ShouldParse("AV:N/AC:L/Au:N/C:P/I:P/A:P"), "5.0"); err == nil {
+		t.Errorf("Convert(..., 5.0) should return error")
+	}
+}
+
+func TestConvert_fidelity(t *testing.T) {
+	var testCases = []struct {
+		given    string
+		expected []FidelityEntry
+	}{
+		{
+			// all metrics have the exact equivalents except the attack requirements.
+			given: "CVSS:3.1/AV:L/AC:H/PR:L/UI:N/S:U/C:L/I:N/A:H/E:P/CR:H/MAV:N/MPR:H/MC:L",
+			expected: []FidelityEntry{
+				{Kind: FidelityDefaulted, To: "AT:N"},
+			},
+		},
+		{
+			given: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/E:U",
+			expected: []FidelityEntry{
+				{Kind: FidelityDefaulted, To: "AT:N"},
+				{Kind: FidelityApproximated, From: "E:U", To: "E:U", Note: "Unproven is mapped to Unreported"},
+			},
+		},
+	}
+	for _, c := range testCases {
+		var _, f, err = Convert(ShouldParse(c.given), "4.0")
+		if err != nil {
+			t.Errorf("Convert(%s, 4.0) should not return error, but got %v", c.given, err)
+			continue
+		}
+		if !reflect.DeepEqual(f.Entries, c.expected) {
+			t.Errorf("Convert(%s, 4.0) fidelity entries == %v, but got %v", c.given, c.expected, f.Entries)
+		}
+	}
+}
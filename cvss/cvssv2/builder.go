@@ -0,0 +1,182 @@
+package cvssv2
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MetricError holds the error of an undefined or invalid metric value.
+type MetricError struct {
+	// Metric is the abbreviated metric name, e.g. AV.
+	Metric string
+	// Value is the rejected metric value, it is empty if the metric is not set.
+	Value string
+}
+
+// Error implements error.
+func (e MetricError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("undefined metric '%s' in CVSS(V2) vector", e.Metric)
+	}
+	return fmt.Sprintf("invalid value '%s' of metric '%s' in CVSS(V2) vector", e.Value, e.Metric)
+}
+
+// MetricErrors holds the errors of the metrics in order of the vector string.
+type MetricErrors []MetricError
+
+// Error implements error.
+func (e MetricErrors) Error() string {
+	var sb strings.Builder
+	for i := range e {
+		if i > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(e[i].Error())
+	}
+	return sb.String()
+}
+
+// Metrics returns the abbreviated names of the offending metrics.
+func (e MetricErrors) Metrics() []string {
+	var r = make([]string, 0, len(e))
+	for i := range e {
+		r = append(r, e[i].Metric)
+	}
+	return r
+}
+
+// Builder composes a CVSS(V2) vector metric by metric,
+// the mandatory base metrics must be set before building,
+// the optional metrics are not defined if not set.
+type Builder struct {
+	v Vector
+}
+
+// NewBuilder returns a Builder of CVSS(V2) vector.
+func NewBuilder() *Builder {
+	var v = DefaultVector()
+	v.BasicMetrics = BasicMetrics{}
+	return &Builder{v: v}
+}
+
+// AccessVector sets the AV metric.
+func (b *Builder) AccessVector(v AccessVector) *Builder {
+	b.v.AccessVector = v
+	return b
+}
+
+// AccessComplexity sets the AC metric.
+func (b *Builder) AccessComplexity(v AccessComplexity) *Builder {
+	b.v.AccessComplexity = v
+	return b
+}
+
+// Authentication sets the Au metric.
+func (b *Builder) Authentication(v Authentication) *Builder {
+	b.v.Authentication = v
+	return b
+}
+
+// ConfidentialityImpact sets the C metric.
+func (b *Builder) ConfidentialityImpact(v ConfidentialityImpact) *Builder {
+	b.v.ConfidentialityImpact = v
+	return b
+}
+
+// IntegrityImpact sets the I metric.
+func (b *Builder) IntegrityImpact(v IntegrityImpact) *Builder {
+	b.v.IntegrityImpact = v
+	return b
+}
+
+// AvailabilityImpact sets the A metric.
+func (b *Builder) AvailabilityImpact(v AvailabilityImpact) *Builder {
+	b.v.AvailabilityImpact = v
+	return b
+}
+
+// Exploitability sets the E metric.
+func (b *Builder) Exploitability(v Exploitability) *Builder {
+	b.v.Exploitability = v
+	return b
+}
+
+// RemediationLevel sets the RL metric.
+func (b *Builder) RemediationLevel(v RemediationLevel) *Builder {
+	b.v.RemediationLevel = v
+	return b
+}
+
+// ReportConfidence sets the RC metric.
+func (b *Builder) ReportConfidence(v ReportConfidence) *Builder {
+	b.v.ReportConfidence = v
+	return b
+}
+
+// CollateralDamagePotential sets the CDP metric.
+func (b *Builder) CollateralDamagePotential(v CollateralDamagePotential) *Builder {
+	b.v.CollateralDamagePotential = v
+	return b
+}
+
+// TargetDistribution sets the TD metric.
+func (b *Builder) TargetDistribution(v TargetDistribution) *Builder {
+	b.v.TargetDistribution = v
+	return b
+}
+
+// ConfidentialityRequirement sets the CR metric.
+func (b *Builder) ConfidentialityRequirement(v SecurityRequirement) *Builder {
+	b.v.ConfidentialityRequirement = v
+	return b
+}
+
+// IntegrityRequirement sets the IR metric.
+func (b *Builder) IntegrityRequirement(v SecurityRequirement) *Builder {
+	b.v.IntegrityRequirement = v
+	return b
+}
+
+// AvailabilityRequirement sets the AR metric.
+func (b *Builder) AvailabilityRequirement(v SecurityRequirement) *Builder {
+	b.v.AvailabilityRequirement = v
+	return b
+}
+
+// Build returns the composed Vector,
+// or returns MetricErrors if any metric is undefined or invalid.
+func (b *Builder) Build() (Vector, error) {
+	var v = b.v
+	var errs MetricErrors
+	for _, m := range []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		// base metrics
+		{"AV", string(v.AccessVector), v.AccessVector.isDefined()},
+		{"AC", string(v.AccessComplexity), v.AccessComplexity.isDefined()},
+		{"Au", string(v.Authentication), v.Authentication.isDefined()},
+		{"C", string(v.ConfidentialityImpact), v.ConfidentialityImpact.isDefined()},
+		{"I", string(v.IntegrityImpact), v.IntegrityImpact.isDefined()},
+		{"A", string(v.AvailabilityImpact), v.AvailabilityImpact.isDefined()},
+		// temporal metrics
+		{"E", string(v.Exploitability), v.Exploitability.isValid()},
+		{"RL", string(v.RemediationLevel), v.RemediationLevel.isValid()},
+		{"RC", string(v.ReportConfidence), v.ReportConfidence.isValid()},
+		// environmental metrics
+		{"CDP", string(v.CollateralDamagePotential), v.CollateralDamagePotential.isValid()},
+		{"TD", string(v.TargetDistribution), v.TargetDistribution.isValid()},
+		{"CR", string(v.ConfidentialityRequirement), v.ConfidentialityRequirement.isValid()},
+		{"IR", string(v.IntegrityRequirement), v.IntegrityRequirement.isValid()},
+		{"AR", string(v.AvailabilityRequirement), v.AvailabilityRequirement.isValid()},
+	} {
+		if !m.valid {
+			errs = append(errs, MetricError{Metric: m.name, Value: m.value})
+		}
+	}
+	if len(errs) != 0 {
+		return Vector{}, errs
+	}
+	return v, nil
+}
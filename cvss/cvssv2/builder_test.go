@@ -0,0 +1,85 @@
+package cvssv2
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBuilder_Build(t *testing.T) {
+	type output struct {
+		vector  string
+		metrics []string
+		err     string
+	}
+	var testCases = []struct {
+		name     string
+		given    *Builder
+		expected output
+	}{
+		{
+			name: "base metrics",
+			given: NewBuilder().
+				AccessVector(AccessVectorNetwork).
+				AccessComplexity(AccessComplexityLow).
+				Authentication(AuthenticationNone).
+				ConfidentialityImpact(ConfidentialityImpactPartial).
+				IntegrityImpact(IntegrityImpactPartial).
+				AvailabilityImpact(AvailabilityImpactPartial),
+			expected: output{
+				vector: "AV:N/AC:L/Au:N/C:P/I:P/A:P",
+			},
+		},
+		{
+			name: "optional metrics",
+			given: NewBuilder().
+				AccessVector(AccessVectorNetwork).
+				AccessComplexity(AccessComplexityLow).
+				Authentication(AuthenticationNone).
+				ConfidentialityImpact(ConfidentialityImpactNone).
+				IntegrityImpact(IntegrityImpactNone).
+				AvailabilityImpact(AvailabilityImpactComplete).
+				Exploitability(ExploitabilityFunctional).
+				RemediationLevel(RemediationLevelOfficialFix).
+				CollateralDamagePotential(CollateralDamagePotentialLowMedium).
+				AvailabilityRequirement(SecurityRequirementHigh),
+			expected: output{
+				vector: "AV:N/AC:L/Au:N/C:N/I:N/A:C/E:F/RL:OF/CDP:LM/AR:H",
+			},
+		},
+		{
+			name: "invalid metrics",
+			given: NewBuilder().
+				AccessVector(AccessVectorNetwork).
+				AccessComplexity(AccessComplexityLow).
+				Authentication("X").
+				ConfidentialityImpact(ConfidentialityImpactNone).
+				IntegrityImpact(IntegrityImpactNone).
+				TargetDistribution("X"),
+			expected: output{
+				metrics: []string{"Au", "A", "TD"},
+				err: "invalid value 'X' of metric 'Au' in CVSS(V2) vector; " +
+					"undefined metric 'A' in CVSS(V2) vector; " +
+					"invalid value 'X' of metric 'TD' in CVSS(V2) vector",
+			},
+		},
+	}
+	for _, c := range testCases {
+		var v, err = c.given.Build()
+		var actual output
+		if err != nil {
+			var me MetricErrors
+			if !errors.As(err, &me) {
+				t.Errorf("%s: error should be MetricErrors, but got %T", c.name, err)
+				continue
+			}
+			actual.metrics = me.Metrics()
+			actual.err = err.Error()
+		} else {
+			actual.vector = v.String()
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s: build == %#v, but got %#v", c.name, c.expected, actual)
+		}
+	}
+}
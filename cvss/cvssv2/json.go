@@ -0,0 +1,209 @@
+package cvssv2
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/seal-io/meta-api/cvss/compatible"
+)
+
+// JSONData holds the JSON data representation of CVSS(V2),
+// according to https://www.first.org/cvss/cvss-v2.0.json.
+type JSONData struct {
+	Version      string `json:"version"`
+	VectorString string `json:"vectorString"`
+	// base metrics
+	AccessVector          string  `json:"accessVector,omitempty"`
+	AccessComplexity      string  `json:"accessComplexity,omitempty"`
+	Authentication        string  `json:"authentication,omitempty"`
+	ConfidentialityImpact string  `json:"confidentialityImpact,omitempty"`
+	IntegrityImpact       string  `json:"integrityImpact,omitempty"`
+	AvailabilityImpact    string  `json:"availabilityImpact,omitempty"`
+	BaseScore             float64 `json:"baseScore"`
+	// temporal metrics
+	Exploitability   string   `json:"exploitability,omitempty"`
+	RemediationLevel string   `json:"remediationLevel,omitempty"`
+	ReportConfidence string   `json:"reportConfidence,omitempty"`
+	TemporalScore    *float64 `json:"temporalScore,omitempty"`
+	// environmental metrics
+	CollateralDamagePotential  string   `json:"collateralDamagePotential,omitempty"`
+	TargetDistribution         string   `json:"targetDistribution,omitempty"`
+	ConfidentialityRequirement string   `json:"confidentialityRequirement,omitempty"`
+	IntegrityRequirement       string   `json:"integrityRequirement,omitempty"`
+	AvailabilityRequirement    string   `json:"availabilityRequirement,omitempty"`
+	EnvironmentalScore         *float64 `json:"environmentalScore,omitempty"`
+}
+
+// ToJSONData converts this CVSS(V2) vector to the JSON data representation,
+// the not defined optional metrics are omitted,
+// the temporal or environmental score is omitted if none of the temporal or environmental metrics is defined.
+func (in Vector) ToJSONData() JSONData {
+	var c = DefaultVector().Override(in)
+	var d = JSONData{
+		Version:      c.GetVersion(),
+		VectorString: c.String(),
+	}
+	var temporal, environmental bool
+	var fs = d.fields()
+	for _, m := range c.Metrics() {
+		if m.Value == "ND" {
+			continue
+		}
+		var f = fs[m.Abbreviation]
+		*f.field = f.values[m.Value]
+		switch m.Group {
+		case compatible.MetricGroupTemporal:
+			temporal = true
+		case compatible.MetricGroupEnvironmental:
+			environmental = true
+		}
+	}
+	d.BaseScore = c.BaseScore()
+	if temporal {
+		var s = c.TemporalScore(d.BaseScore)
+		d.TemporalScore = &s
+	}
+	if environmental {
+		var s = c.EnvironmentalScore()
+		d.EnvironmentalScore = &s
+	}
+	return d
+}
+
+// FromJSONData converts the given JSON data representation to CVSS(V2) vector,
+// the vector is parsed from the vectorString, or composed by the metrics if the vectorString is blank,
+// the given metrics and version must be consistent with the vector,
+// the given scores are ignored, which are always calculated from the vector.
+func FromJSONData(d JSONData) (Vector, error) {
+	if d.Version != "" && d.Version != "2.0" {
+		return Vector{}, fmt.Errorf("invalid version '%s' of CVSS(V2) JSON data", d.Version)
+	}
+	var fs = d.fields()
+	var s = strings.TrimSpace(d.VectorString)
+	if s == "" {
+		var parts = make([]string, 0, len(metricOrder))
+		for _, mn := range metricOrder {
+			var f = fs[mn]
+			if *f.field == "" {
+				continue
+			}
+			var mv, ok = f.abbreviate(*f.field)
+			if !ok {
+				return Vector{}, fmt.Errorf("invalid value '%s' of field '%s' in CVSS(V2) JSON data", *f.field, f.name)
+			}
+			parts = append(parts, mn+":"+mv)
+		}
+		s = strings.Join(parts, "/")
+	}
+	var v, err = Parse(s)
+	if err != nil {
+		return Vector{}, err
+	}
+	for _, m := range v.Metrics() {
+		var f = fs[m.Abbreviation]
+		if *f.field == "" {
+			continue
+		}
+		if *f.field != f.values[m.Value] {
+			return Vector{}, fmt.Errorf("inconsistent value '%s' of field '%s' in CVSS(V2) JSON data: %s", *f.field, f.name, s)
+		}
+	}
+	return v, nil
+}
+
+// MarshalJSON implements json.Marshaler,
+// marshals this CVSS(V2) vector as the JSON data representation.
+func (in Vector) MarshalJSON() ([]byte, error) {
+	return json.Marshal(in.ToJSONData())
+}
+
+// UnmarshalJSON implements json.Unmarshaler,
+// unmarshals the JSON data representation or the vector string to this CVSS(V2) vector.
+func (in *Vector) UnmarshalJSON(bs []byte) error {
+	bs = bytes.TrimSpace(bs)
+	if len(bs) != 0 && bs[0] == '"' {
+		var s string
+		if err := json.Unmarshal(bs, &s); err != nil {
+			return err
+		}
+		var v, err = Parse(s)
+		if err != nil {
+			return err
+		}
+		*in = v
+		return nil
+	}
+	var d JSONData
+	if err := json.Unmarshal(bs, &d); err != nil {
+		return err
+	}
+	var v, err = FromJSONData(d)
+	if err != nil {
+		return err
+	}
+	*in = v
+	return nil
+}
+
+type jsonField struct {
+	name   string
+	field  *string
+	values map[string]string
+}
+
+// abbreviate returns the abbreviated metric value of the given JSON enumeration.
+func (in jsonField) abbreviate(e string) (string, bool) {
+	for mv, me := range in.values {
+		if me == e {
+			return mv, true
+		}
+	}
+	return "", false
+}
+
+// fields returns the metric fields of this JSONData indexed by the abbreviated metric names.
+func (in *JSONData) fields() map[string]jsonField {
+	return map[string]jsonField{
+		// base metrics
+		"AV": {"accessVector", &in.AccessVector, map[string]string{
+			"N": "NETWORK", "A": "ADJACENT_NETWORK", "L": "LOCAL",
+		}},
+		"AC": {"accessComplexity", &in.AccessComplexity, map[string]string{
+			"H": "HIGH", "M": "MEDIUM", "L": "LOW",
+		}},
+		"Au": {"authentication", &in.Authentication, map[string]string{
+			"M": "MULTIPLE", "S": "SINGLE", "N": "NONE",
+		}},
+		"C": {"confidentialityImpact", &in.ConfidentialityImpact, impactEnums},
+		"I": {"integrityImpact", &in.IntegrityImpact, impactEnums},
+		"A": {"availabilityImpact", &in.AvailabilityImpact, impactEnums},
+		// temporal metrics
+		"E": {"exploitability", &in.Exploitability, map[string]string{
+			"U": "UNPROVEN", "POC": "PROOF_OF_CONCEPT", "F": "FUNCTIONAL", "H": "HIGH", "ND": "NOT_DEFINED",
+		}},
+		"RL": {"remediationLevel", &in.RemediationLevel, map[string]string{
+			"OF": "OFFICIAL_FIX", "TF": "TEMPORARY_FIX", "W": "WORKAROUND", "U": "UNAVAILABLE", "ND": "NOT_DEFINED",
+		}},
+		"RC": {"reportConfidence", &in.ReportConfidence, map[string]string{
+			"UC": "UNCONFIRMED", "UR": "UNCORROBORATED", "C": "CONFIRMED", "ND": "NOT_DEFINED",
+		}},
+		// environmental metrics
+		"CDP": {"collateralDamagePotential", &in.CollateralDamagePotential, map[string]string{
+			"N": "NONE", "L": "LOW", "LM": "LOW_MEDIUM", "MH": "MEDIUM_HIGH", "H": "HIGH", "ND": "NOT_DEFINED",
+		}},
+		"TD": {"targetDistribution", &in.TargetDistribution, map[string]string{
+			"N": "NONE", "L": "LOW", "M": "MEDIUM", "H": "HIGH", "ND": "NOT_DEFINED",
+		}},
+		"CR": {"confidentialityRequirement", &in.ConfidentialityRequirement, requirementEnums},
+		"IR": {"integrityRequirement", &in.IntegrityRequirement, requirementEnums},
+		"AR": {"availabilityRequirement", &in.AvailabilityRequirement, requirementEnums},
+	}
+}
+
+// JSON enumerations shared by the metrics.
+var (
+	impactEnums      = map[string]string{"N": "NONE", "P": "PARTIAL", "C": "COMPLETE"}
+	requirementEnums = map[string]string{"L": "LOW", "M": "MEDIUM", "H": "HIGH", "ND": "NOT_DEFINED"}
+)
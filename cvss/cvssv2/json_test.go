@@ -0,0 +1,109 @@
+package cvssv2
+
+import (
+	_ "embed"
+	"encoding/json"
+	"testing"
+
+	"github.com/seal-io/meta-api/cvss/internal/jsonschema"
+)
+
+//go:embed testdata/cvss-v2.0.json
+var schemaV20 []byte
+
+func TestVector_MarshalJSON(t *testing.T) {
+	var testCases = []struct {
+		given    string
+		expected string
+	}{
+		{
+			given:    "AV:N/AC:L/Au:N/C:P/I:P/A:P",
+			expected: `{"version":"2.0","vectorString":"AV:N/AC:L/Au:N/C:P/I:P/A:P","accessVector":"NETWORK","accessComplexity":"LOW","authentication":"NONE","confidentialityImpact":"PARTIAL","integrityImpact":"PARTIAL","availabilityImpact":"PARTIAL","baseScore":7.5}`,
+		},
+		{
+			given:    "AV:N/AC:L/Au:N/C:P/I:P/A:P/E:POC/RL:OF",
+			expected: `{"version":"2.0","vectorString":"AV:N/AC:L/Au:N/C:P/I:P/A:P/E:POC/RL:OF","accessVector":"NETWORK","accessComplexity":"LOW","authentication":"NONE","confidentialityImpact":"PARTIAL","integrityImpact":"PARTIAL","availabilityImpact":"PARTIAL","baseScore":7.5,"exploitability":"PROOF_OF_CONCEPT","remediationLevel":"OFFICIAL_FIX","temporalScore":5.9}`,
+		},
+		{
+			given:    "AV:L/AC:L/Au:N/C:N/I:N/A:C/CDP:LM/TD:H/CR:L/IR:M/AR:H",
+			expected: `{"version":"2.0","vectorString":"AV:L/AC:L/Au:N/C:N/I:N/A:C/CDP:LM/TD:H/CR:L/IR:M/AR:H","accessVector":"LOCAL","accessComplexity":"LOW","authentication":"NONE","confidentialityImpact":"NONE","integrityImpact":"NONE","availabilityImpact":"COMPLETE","baseScore":4.9,"collateralDamagePotential":"LOW_MEDIUM","targetDistribution":"HIGH","confidentialityRequirement":"LOW","integrityRequirement":"MEDIUM","availabilityRequirement":"HIGH","environmentalScore":8}`,
+		},
+	}
+	for _, c := range testCases {
+		var v = ShouldParse(c.given)
+		var actual, err = json.Marshal(v)
+		if err != nil {
+			t.Errorf("error marshalling %s: %v", c.given, err)
+			continue
+		}
+		if string(actual) != c.expected {
+			t.Errorf("marshal %s == \n%s\n, but got \n%s", c.given, c.expected, actual)
+		}
+
+		if err = jsonschema.Validate(schemaV20, actual); err != nil {
+			t.Errorf("error validating %s: %v", actual, err)
+		}
+
+		var r Vector
+		if err = json.Unmarshal(actual, &r); err != nil {
+			t.Errorf("error unmarshalling %s: %v", actual, err)
+			continue
+		}
+		if r != v {
+			t.Errorf("unmarshal %s == %v, but got %v", actual, v, r)
+		}
+	}
+}
+
+func TestVector_UnmarshalJSON(t *testing.T) {
+	type output struct {
+		vector string
+		err    bool
+	}
+	var testCases = []struct {
+		given    string
+		expected output
+	}{
+		{
+			given: `"AV:N/AC:L/Au:N/C:P/I:P/A:P"`,
+			expected: output{
+				vector: "AV:N/AC:L/Au:N/C:P/I:P/A:P",
+			},
+		},
+		{
+			given: `{"version":"2.0","accessVector":"NETWORK","accessComplexity":"MEDIUM","authentication":"SINGLE","confidentialityImpact":"NONE","integrityImpact":"PARTIAL","availabilityImpact":"NONE","exploitability":"PROOF_OF_CONCEPT","targetDistribution":"NOT_DEFINED"}`,
+			expected: output{
+				vector: "AV:N/AC:M/Au:S/C:N/I:P/A:N/E:POC",
+			},
+		},
+		{
+			given: `{"version":"3.0","vectorString":"AV:N/AC:L/Au:N/C:P/I:P/A:P","baseScore":7.5}`,
+			expected: output{
+				err: true,
+			},
+		},
+		{
+			given: `{"version":"2.0","vectorString":"AV:N/AC:L/Au:N/C:P/I:P/A:P","authentication":"SINGLE","baseScore":7.5}`,
+			expected: output{
+				err: true,
+			},
+		},
+		{
+			given: `{"version":"2.0","accessVector":"NETWORK","accessComplexity":"MEDIUM","authentication":"SINGLE","confidentialityImpact":"NONE","integrityImpact":"PARTIAL","availabilityImpact":"HIGH"}`,
+			expected: output{
+				err: true,
+			},
+		},
+	}
+	for _, c := range testCases {
+		var v Vector
+		var err = json.Unmarshal([]byte(c.given), &v)
+		var actual = output{err: err != nil}
+		if err == nil {
+			actual.vector = v.String()
+		}
+		if actual != c.expected {
+			t.Errorf("unmarshal %s == %v, but got %v", c.given, c.expected, actual)
+		}
+	}
+}
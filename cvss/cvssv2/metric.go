@@ -0,0 +1,166 @@
+package cvssv2
+
+import (
+	"fmt"
+
+	"github.com/seal-io/meta-api/cvss/compatible"
+)
+
+// Metrics returns the metrics of this CVSS(V2) vector in order of the vector string,
+// including the not defined optional metrics.
+func (in Vector) Metrics() []compatible.Metric {
+	var c = DefaultVector().Override(in)
+	return []compatible.Metric{
+		// base metrics
+		newMetric(compatible.MetricGroupBase, "AV", string(c.AccessVector), c.getAccessVector()),
+		newMetric(compatible.MetricGroupBase, "AC", string(c.AccessComplexity), c.getAccessComplexity()),
+		newMetric(compatible.MetricGroupBase, "Au", string(c.Authentication), c.getAuthentication()),
+		newMetric(compatible.MetricGroupBase, "C", string(c.ConfidentialityImpact), c.getConfidentialityImpact()),
+		newMetric(compatible.MetricGroupBase, "I", string(c.IntegrityImpact), c.getIntegrityImpact()),
+		newMetric(compatible.MetricGroupBase, "A", string(c.AvailabilityImpact), c.getAvailabilityImpact()),
+		// temporal metrics
+		newMetric(compatible.MetricGroupTemporal, "E", string(c.Exploitability), c.getExploitability()),
+		newMetric(compatible.MetricGroupTemporal, "RL", string(c.RemediationLevel), c.getRemediationLevel()),
+		newMetric(compatible.MetricGroupTemporal, "RC", string(c.ReportConfidence), c.getReportConfidence()),
+		// environmental metrics
+		newMetric(compatible.MetricGroupEnvironmental, "CDP", string(c.CollateralDamagePotential), c.getCollateralDamagePotential()),
+		newMetric(compatible.MetricGroupEnvironmental, "TD", string(c.TargetDistribution), c.getTargetDistribution()),
+		newMetric(compatible.MetricGroupEnvironmental, "CR", string(c.ConfidentialityRequirement), c.getConfidentialityRequirement()),
+		newMetric(compatible.MetricGroupEnvironmental, "IR", string(c.IntegrityRequirement), c.getIntegrityRequirement()),
+		newMetric(compatible.MetricGroupEnvironmental, "AR", string(c.AvailabilityRequirement), c.getAvailabilityRequirement()),
+	}
+}
+
+// Explain returns the derivation of the scores of this CVSS(V2) vector,
+// according to https://www.first.org/cvss/v2/guide#3-2-Equations.
+func (in Vector) Explain() compatible.Explanation {
+	var c = DefaultVector().Override(in)
+	var e = compatible.Explanation{Version: c.GetVersion()}
+	var step = func(g compatible.MetricGroup, n, f, x string, v float64) {
+		e.Steps = append(e.Steps, compatible.Step{Group: g, Name: n, Formula: f, Expression: x, Value: v})
+	}
+
+	// base equation
+	var i = c.RawImpactScore(false)
+	step(compatible.MetricGroupBase, "Impact",
+		"10.41 × (1 - (1 - C) × (1 - I) × (1 - A))",
+		fmt.Sprintf("10.41 × (1 - (1 - %s) × (1 - %s) × (1 - %s))",
+			ftoa(c.getConfidentialityImpact()), ftoa(c.getIntegrityImpact()), ftoa(c.getAvailabilityImpact())),
+		i)
+	var x = c.RawExploitabilityScore(false)
+	step(compatible.MetricGroupBase, "Exploitability",
+		"20 × AV × AC × Au",
+		fmt.Sprintf("20 × %s × %s × %s",
+			ftoa(c.getAccessVector()), ftoa(c.getAccessComplexity()), ftoa(c.getAuthentication())),
+		x)
+	var fi = fImpact(i)
+	step(compatible.MetricGroupBase, "f(Impact)",
+		"0 if Impact = 0, 1.176 otherwise",
+		fmt.Sprintf("f(%s)", ftoa(i)),
+		fi)
+	var bs = c.BaseScore()
+	step(compatible.MetricGroupBase, "Base Score",
+		"Round(((0.6 × Impact) + (0.4 × Exploitability) - 1.5) × f(Impact))",
+		fmt.Sprintf("Round(((0.6 × %s) + (0.4 × %s) - 1.5) × %s)", ftoa(i), ftoa(x), ftoa(fi)),
+		bs)
+
+	// temporal equation
+	step(compatible.MetricGroupTemporal, "Temporal Score",
+		"Round(BaseScore × E × RL × RC)",
+		fmt.Sprintf("Round(%s × %s × %s × %s)",
+			ftoa(bs), ftoa(c.getExploitability()), ftoa(c.getRemediationLevel()), ftoa(c.getReportConfidence())),
+		c.TemporalScore(bs))
+
+	// environmental equation
+	var ai = c.RawImpactScore(true)
+	step(compatible.MetricGroupEnvironmental, "Adjusted Impact",
+		"Min(10, 10.41 × (1 - (1 - C × CR) × (1 - I × IR) × (1 - A × AR)))",
+		fmt.Sprintf("Min(10, 10.41 × (1 - (1 - %s × %s) × (1 - %s × %s) × (1 - %s × %s)))",
+			ftoa(c.getConfidentialityImpact()), ftoa(c.getConfidentialityRequirement()),
+			ftoa(c.getIntegrityImpact()), ftoa(c.getIntegrityRequirement()),
+			ftoa(c.getAvailabilityImpact()), ftoa(c.getAvailabilityRequirement())),
+		ai)
+	var afi = fImpact(ai)
+	var abs = round(((0.6 * ai) + (0.4 * x) - 1.5) * afi)
+	step(compatible.MetricGroupEnvironmental, "Adjusted Base Score",
+		"Round(((0.6 × AdjustedImpact) + (0.4 × Exploitability) - 1.5) × f(AdjustedImpact))",
+		fmt.Sprintf("Round(((0.6 × %s) + (0.4 × %s) - 1.5) × %s)", ftoa(ai), ftoa(x), ftoa(afi)),
+		abs)
+	var at = abs * c.getExploitability() * c.getRemediationLevel() * c.getReportConfidence()
+	step(compatible.MetricGroupEnvironmental, "Adjusted Temporal",
+		"AdjustedBaseScore × E × RL × RC",
+		fmt.Sprintf("%s × %s × %s × %s",
+			ftoa(abs), ftoa(c.getExploitability()), ftoa(c.getRemediationLevel()), ftoa(c.getReportConfidence())),
+		at)
+	if ai <= 0 {
+		step(compatible.MetricGroupEnvironmental, "Environmental Score",
+			"0 if AdjustedImpact = 0",
+			"",
+			c.EnvironmentalScore())
+	} else {
+		step(compatible.MetricGroupEnvironmental, "Environmental Score",
+			"Round((AdjustedTemporal + (10 - AdjustedTemporal) × CDP) × TD)",
+			fmt.Sprintf("Round((%s + (10 - %s) × %s) × %s)",
+				ftoa(at), ftoa(at), ftoa(c.getCollateralDamagePotential()), ftoa(c.getTargetDistribution())),
+			c.EnvironmentalScore())
+	}
+
+	return e
+}
+
+func fImpact(i float64) float64 {
+	if i <= 0 {
+		return 0
+	}
+	return 1.176
+}
+
+func ftoa(f float64) string {
+	return compatible.FormatFloat(f)
+}
+
+func newMetric(g compatible.MetricGroup, mn, mv string, w float64) compatible.Metric {
+	var n = metricNames[mn]
+	return compatible.Metric{
+		Group:        g,
+		Abbreviation: mn,
+		Name:         n.name,
+		Value:        mv,
+		ValueName:    n.values[mv],
+		Weight:       w,
+	}
+}
+
+// metricNames holds the full names of the metrics and the metric values.
+var metricNames = map[string]struct {
+	name   string
+	values map[string]string
+}{
+	// base metrics
+	"AV": {"Access Vector", map[string]string{"L": "Local", "A": "Adjacent Network", "N": "Network"}},
+	"AC": {"Access Complexity", map[string]string{"H": "High", "M": "Medium", "L": "Low"}},
+	"Au": {"Authentication", map[string]string{"M": "Multiple", "S": "Single", "N": "None"}},
+	"C":  {"Confidentiality Impact", map[string]string{"N": "None", "P": "Partial", "C": "Complete"}},
+	"I":  {"Integrity Impact", map[string]string{"N": "None", "P": "Partial", "C": "Complete"}},
+	"A":  {"Availability Impact", map[string]string{"N": "None", "P": "Partial", "C": "Complete"}},
+	// temporal metrics
+	"E": {"Exploitability", map[string]string{
+		"U": "Unproven", "POC": "Proof-of-Concept", "F": "Functional", "H": "High", "ND": "Not Defined",
+	}},
+	"RL": {"Remediation Level", map[string]string{
+		"OF": "Official Fix", "TF": "Temporary Fix", "W": "Workaround", "U": "Unavailable", "ND": "Not Defined",
+	}},
+	"RC": {"Report Confidence", map[string]string{
+		"UC": "Unconfirmed", "UR": "Uncorroborated", "C": "Confirmed", "ND": "Not Defined",
+	}},
+	// environmental metrics
+	"CDP": {"Collateral Damage Potential", map[string]string{
+		"N": "None", "L": "Low", "LM": "Low-Medium", "MH": "Medium-High", "H": "High", "ND": "Not Defined",
+	}},
+	"TD": {"Target Distribution", map[string]string{
+		"N": "None", "L": "Low", "M": "Medium", "H": "High", "ND": "Not Defined",
+	}},
+	"CR": {"Confidentiality Requirement", map[string]string{"L": "Low", "M": "Medium", "H": "High", "ND": "Not Defined"}},
+	"IR": {"Integrity Requirement", map[string]string{"L": "Low", "M": "Medium", "H": "High", "ND": "Not Defined"}},
+	"AR": {"Availability Requirement", map[string]string{"L": "Low", "M": "Medium", "H": "High", "ND": "Not Defined"}},
+}
@@ -0,0 +1,123 @@
+package cvssv2
+
+import (
+	"testing"
+
+	"github.com/seal-io/meta-api/cvss/compatible"
+)
+
+func TestVector_Metrics(t *testing.T) {
+	type output struct {
+		value     string
+		valueName string
+		weight    float64
+	}
+	var testCases = []struct {
+		given    string
+		metric   string
+		expected output
+	}{
+		{
+			given:  "AV:N/AC:L/Au:N/C:P/I:P/A:C",
+			metric: "AV",
+			expected: output{
+				value:     "N",
+				valueName: "Network",
+				weight:    1,
+			},
+		},
+		{
+			given:  "AV:N/AC:L/Au:N/C:P/I:P/A:C",
+			metric: "A",
+			expected: output{
+				value:     "C",
+				valueName: "Complete",
+				weight:    0.66,
+			},
+		},
+		{
+			given:  "AV:N/AC:L/Au:N/C:P/I:P/A:C",
+			metric: "E",
+			expected: output{
+				value:     "ND",
+				valueName: "Not Defined",
+				weight:    1,
+			},
+		},
+		{
+			given:  "AV:N/AC:L/Au:N/C:P/I:P/A:C/E:POC/CDP:LM/CR:H",
+			metric: "CDP",
+			expected: output{
+				value:     "LM",
+				valueName: "Low-Medium",
+				weight:    0.3,
+			},
+		},
+		{
+			given:  "AV:N/AC:L/Au:N/C:P/I:P/A:C/E:POC/CDP:LM/CR:H",
+			metric: "CR",
+			expected: output{
+				value:     "H",
+				valueName: "High",
+				weight:    1.51,
+			},
+		},
+	}
+	for _, c := range testCases {
+		var v = ShouldParse(c.given)
+		if len(v.Metrics()) != 14 {
+			t.Errorf("metrics of %s should be 14, but got %d", c.given, len(v.Metrics()))
+		}
+		var m, ok = compatible.GetMetric(v, c.metric)
+		if !ok {
+			t.Errorf("metric %s of %s is not found", c.metric, c.given)
+			continue
+		}
+		var actual = output{
+			value:     m.Value,
+			valueName: m.ValueName,
+			weight:    m.Weight,
+		}
+		if actual != c.expected {
+			t.Errorf("metric %s of %s == %#v, but got %#v", c.metric, c.given, c.expected, actual)
+		}
+	}
+}
+
+func TestVector_Explain(t *testing.T) {
+	var testCases = []struct {
+		given    string
+		expected string
+	}{
+		{
+			given: "AV:N/AC:L/Au:N/C:P/I:P/A:P/E:F/CDP:LM/TD:H",
+			expected: `Impact = 10.41 × (1 - (1 - C) × (1 - I) × (1 - A)) = 10.41 × (1 - (1 - 0.275) × (1 - 0.275) × (1 - 0.275)) = 6.442977
+Exploitability = 20 × AV × AC × Au = 20 × 1 × 0.71 × 0.704 = 9.9968
+f(Impact) = 0 if Impact = 0, 1.176 otherwise = f(6.442977) = 1.176
+Base Score = Round(((0.6 × Impact) + (0.4 × Exploitability) - 1.5) × f(Impact)) = Round(((0.6 × 6.442977) + (0.4 × 9.9968) - 1.5) × 1.176) = 7.5
+Temporal Score = Round(BaseScore × E × RL × RC) = Round(7.5 × 0.95 × 1 × 1) = 7.1
+Adjusted Impact = Min(10, 10.41 × (1 - (1 - C × CR) × (1 - I × IR) × (1 - A × AR))) = Min(10, 10.41 × (1 - (1 - 0.275 × 1) × (1 - 0.275 × 1) × (1 - 0.275 × 1))) = 6.442977
+Adjusted Base Score = Round(((0.6 × AdjustedImpact) + (0.4 × Exploitability) - 1.5) × f(AdjustedImpact)) = Round(((0.6 × 6.442977) + (0.4 × 9.9968) - 1.5) × 1.176) = 7.5
+Adjusted Temporal = AdjustedBaseScore × E × RL × RC = 7.5 × 0.95 × 1 × 1 = 7.125
+Environmental Score = Round((AdjustedTemporal + (10 - AdjustedTemporal) × CDP) × TD) = Round((7.125 + (10 - 7.125) × 0.3) × 1) = 8`,
+		},
+		{
+			given: "AV:N/AC:L/Au:N/C:N/I:N/A:N/CDP:H",
+			expected: `Impact = 10.41 × (1 - (1 - C) × (1 - I) × (1 - A)) = 10.41 × (1 - (1 - 0) × (1 - 0) × (1 - 0)) = 0
+Exploitability = 20 × AV × AC × Au = 20 × 1 × 0.71 × 0.704 = 9.9968
+f(Impact) = 0 if Impact = 0, 1.176 otherwise = f(0) = 0
+Base Score = Round(((0.6 × Impact) + (0.4 × Exploitability) - 1.5) × f(Impact)) = Round(((0.6 × 0) + (0.4 × 9.9968) - 1.5) × 0) = 0
+Temporal Score = Round(BaseScore × E × RL × RC) = Round(0 × 1 × 1 × 1) = 0
+Adjusted Impact = Min(10, 10.41 × (1 - (1 - C × CR) × (1 - I × IR) × (1 - A × AR))) = Min(10, 10.41 × (1 - (1 - 0 × 1) × (1 - 0 × 1) × (1 - 0 × 1))) = 0
+Adjusted Base Score = Round(((0.6 × AdjustedImpact) + (0.4 × Exploitability) - 1.5) × f(AdjustedImpact)) = Round(((0.6 × 0) + (0.4 × 9.9968) - 1.5) × 0) = 0
+Adjusted Temporal = AdjustedBaseScore × E × RL × RC = 0 × 1 × 1 × 1 = 0
+Environmental Score = 0 if AdjustedImpact = 0 = 0`,
+		},
+	}
+	for _, c := range testCases {
+		var actual = ShouldParse(c.given).Explain().String()
+		if actual != c.expected {
+			t.Errorf("explanation of %s == \n%s\n, but got \n%s", c.given, c.expected, actual)
+		}
+	}
+}
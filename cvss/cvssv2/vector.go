@@ -126,6 +126,33 @@ func Parse(s string) (Vector, error) {
 	return v, nil
 }
 
+// ParseLenient likes Parse but normalizes the vector string before parsing,
+// e.g. the vector enclosed in parentheses or with a trailing slash,
+// the lowercase metrics, the duplicated metrics or the unordered metrics,
+// and returns a warning for each fix-up.
+func ParseLenient(s string) (Vector, []compatible.ParseWarning, error) {
+	var parts, ws, err = compatible.NormalizeMetrics(s, metricOrder)
+	if err != nil {
+		return Vector{}, nil, fmt.Errorf("error normalizing CVSS(V2) vector: %s: %w", s, err)
+	}
+	var v Vector
+	v, err = Parse(strings.Join(parts, "/"))
+	if err != nil {
+		return Vector{}, nil, err
+	}
+	return v, ws, nil
+}
+
+// metricOrder holds the abbreviated metric names in order of the vector string.
+var metricOrder = []string{
+	// base metrics
+	"AV", "AC", "Au", "C", "I", "A",
+	// temporal metrics
+	"E", "RL", "RC",
+	// environmental metrics
+	"CDP", "TD", "CR", "IR", "AR",
+}
+
 // Vector holds the metrics vector of CVSS(V2).
 type Vector struct {
 	BasicMetrics
@@ -778,6 +805,44 @@ const (
 	ReportConfidenceConfirmed      ReportConfidence = "C"
 )
 
+func (in Exploitability) isValid() bool {
+	switch in {
+	default:
+		return false
+	case ExploitabilityNotDefined:
+	case ExploitabilityUnproven:
+	case ExploitabilityProofOfConcept:
+	case ExploitabilityFunctional:
+	case ExploitabilityHigh:
+	}
+	return true
+}
+
+func (in RemediationLevel) isValid() bool {
+	switch in {
+	default:
+		return false
+	case RemediationLevelNotDefined:
+	case RemediationLevelOfficialFix:
+	case RemediationLevelTemporaryFix:
+	case RemediationLevelWorkaround:
+	case RemediationLevelUnavailable:
+	}
+	return true
+}
+
+func (in ReportConfidence) isValid() bool {
+	switch in {
+	default:
+		return false
+	case ReportConfidenceNotDefined:
+	case ReportConfidenceUnconfirmed:
+	case ReportConfidenceUncorroborated:
+	case ReportConfidenceConfirmed:
+	}
+	return true
+}
+
 func (in TemporalMetrics) getExploitability() float64 {
 	switch in.Exploitability {
 	default:
@@ -863,6 +928,45 @@ const (
 	SecurityRequirementHigh       SecurityRequirement = "H"
 )
 
+func (in CollateralDamagePotential) isValid() bool {
+	switch in {
+	default:
+		return false
+	case CollateralDamagePotentialNotDefined:
+	case CollateralDamagePotentialNone:
+	case CollateralDamagePotentialLow:
+	case CollateralDamagePotentialLowMedium:
+	case CollateralDamagePotentialMediumHigh:
+	case CollateralDamagePotentialHigh:
+	}
+	return true
+}
+
+func (in TargetDistribution) isValid() bool {
+	switch in {
+	default:
+		return false
+	case TargetDistributionNotDefined:
+	case TargetDistributionNone:
+	case TargetDistributionLow:
+	case TargetDistributionMedium:
+	case TargetDistributionHigh:
+	}
+	return true
+}
+
+func (in SecurityRequirement) isValid() bool {
+	switch in {
+	default:
+		return false
+	case SecurityRequirementNotDefined:
+	case SecurityRequirementLow:
+	case SecurityRequirementMedium:
+	case SecurityRequirementHigh:
+	}
+	return true
+}
+
 func (in EnvironmentalMetrics) getCollateralDamagePotential() float64 {
 	switch in.CollateralDamagePotential {
 	default:
@@ -164,6 +164,65 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParseLenient(t *testing.T) {
+	type output struct {
+		vector   string
+		warnings []string
+		err      bool
+	}
+	var testCases = []struct {
+		given    string
+		expected output
+	}{
+		{
+			given: "AV:N/AC:L/Au:N/C:P/I:P/A:P",
+			expected: output{
+				vector: "AV:N/AC:L/Au:N/C:P/I:P/A:P",
+			},
+		},
+		{
+			given: "(AV:N/AC:L/Au:N/C:P/I:P/A:P/)",
+			expected: output{
+				vector:   "AV:N/AC:L/Au:N/C:P/I:P/A:P",
+				warnings: []string{"parenthesized", "trailing-slash"},
+			},
+		},
+		{
+			given: "AV:N/AC:L/AU:N/C:P/I:P/A:P/e:poc",
+			expected: output{
+				vector:   "AV:N/AC:L/Au:N/C:P/I:P/A:P/E:POC",
+				warnings: []string{"lowercase", "lowercase"},
+			},
+		},
+		{
+			given: "AV:N/AC:L/E:F/Au:N/C:P/I:P/A:P/AC:L",
+			expected: output{
+				vector:   "AV:N/AC:L/Au:N/C:P/I:P/A:P/E:F",
+				warnings: []string{"duplicated", "unordered"},
+			},
+		},
+		{
+			given: "AV:N/AC:L/Au:N/C:P/I:P/A:P/PR:N",
+			expected: output{
+				err: true,
+			},
+		},
+	}
+	for _, c := range testCases {
+		var v, ws, err = ParseLenient(c.given)
+		var actual = output{err: err != nil}
+		if err == nil {
+			actual.vector = v.String()
+		}
+		for i := range ws {
+			actual.warnings = append(actual.warnings, string(ws[i].Kind))
+		}
+		if fmt.Sprint(actual) != fmt.Sprint(c.expected) {
+			t.Errorf("lenient parse %s == %v, but got %v", c.given, c.expected, actual)
+		}
+	}
+}
+
 func TestScore(t *testing.T) {
 	type output struct {
 		impactScore           float64
@@ -0,0 +1,245 @@
+package cvssv3
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MetricError holds the error of an undefined or invalid metric value.
+type MetricError struct {
+	// Metric is the abbreviated metric name, e.g. AV.
+	Metric string
+	// Value is the rejected metric value, it is empty if the metric is not set.
+	Value string
+}
+
+// Error implements error.
+func (e MetricError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("undefined metric '%s' in CVSS(V3) vector", e.Metric)
+	}
+	return fmt.Sprintf("invalid value '%s' of metric '%s' in CVSS(V3) vector", e.Value, e.Metric)
+}
+
+// MetricErrors holds the errors of the metrics in order of the vector string.
+type MetricErrors []MetricError
+
+// Error implements error.
+func (e MetricErrors) Error() string {
+	var sb strings.Builder
+	for i := range e {
+		if i > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(e[i].Error())
+	}
+	return sb.String()
+}
+
+// Metrics returns the abbreviated names of the offending metrics.
+func (e MetricErrors) Metrics() []string {
+	var r = make([]string, 0, len(e))
+	for i := range e {
+		r = append(r, e[i].Metric)
+	}
+	return r
+}
+
+// Builder composes a CVSS(V3) vector metric by metric,
+// the mandatory base metrics must be set before building,
+// the optional metrics are not defined if not set.
+type Builder struct {
+	v Vector
+}
+
+// NewBuilder returns a Builder of CVSS(V3.1) vector.
+func NewBuilder() *Builder {
+	var v = DefaultVector()
+	v.BasicMetrics = BasicMetrics{}
+	return &Builder{v: v}
+}
+
+// Version sets the version.
+func (b *Builder) Version(v Version) *Builder {
+	b.v.Version = v
+	return b
+}
+
+// AttackVector sets the AV metric.
+func (b *Builder) AttackVector(v AttackVector) *Builder {
+	b.v.AttackVector = v
+	return b
+}
+
+// AttackComplexity sets the AC metric.
+func (b *Builder) AttackComplexity(v AttackComplexity) *Builder {
+	b.v.AttackComplexity = v
+	return b
+}
+
+// PrivilegesRequired sets the PR metric.
+func (b *Builder) PrivilegesRequired(v PrivilegesRequired) *Builder {
+	b.v.PrivilegesRequired = v
+	return b
+}
+
+// UserInteraction sets the UI metric.
+func (b *Builder) UserInteraction(v UserInteraction) *Builder {
+	b.v.UserInteraction = v
+	return b
+}
+
+// Scope sets the S metric.
+func (b *Builder) Scope(v Scope) *Builder {
+	b.v.Scope = v
+	return b
+}
+
+// ConfidentialityImpact sets the C metric.
+func (b *Builder) ConfidentialityImpact(v ConfidentialityImpact) *Builder {
+	b.v.ConfidentialityImpact = v
+	return b
+}
+
+// IntegrityImpact sets the I metric.
+func (b *Builder) IntegrityImpact(v IntegrityImpact) *Builder {
+	b.v.IntegrityImpact = v
+	return b
+}
+
+// AvailabilityImpact sets the A metric.
+func (b *Builder) AvailabilityImpact(v AvailabilityImpact) *Builder {
+	b.v.AvailabilityImpact = v
+	return b
+}
+
+// ExploitCodeMaturity sets the E metric.
+func (b *Builder) ExploitCodeMaturity(v ExploitCodeMaturity) *Builder {
+	b.v.ExploitCodeMaturity = v
+	return b
+}
+
+// RemediationLevel sets the RL metric.
+func (b *Builder) RemediationLevel(v RemediationLevel) *Builder {
+	b.v.RemediationLevel = v
+	return b
+}
+
+// ReportConfidence sets the RC metric.
+func (b *Builder) ReportConfidence(v ReportConfidence) *Builder {
+	b.v.ReportConfidence = v
+	return b
+}
+
+// ConfidentialityRequirement sets the CR metric.
+func (b *Builder) ConfidentialityRequirement(v SecurityRequirement) *Builder {
+	b.v.ConfidentialityRequirement = v
+	return b
+}
+
+// IntegrityRequirement sets the IR metric.
+func (b *Builder) IntegrityRequirement(v SecurityRequirement) *Builder {
+	b.v.IntegrityRequirement = v
+	return b
+}
+
+// AvailabilityRequirement sets the AR metric.
+func (b *Builder) AvailabilityRequirement(v SecurityRequirement) *Builder {
+	b.v.AvailabilityRequirement = v
+	return b
+}
+
+// ModifiedAttackVector sets the MAV metric.
+func (b *Builder) ModifiedAttackVector(v AttackVector) *Builder {
+	b.v.ModifiedAttackVector = v
+	return b
+}
+
+// ModifiedAttackComplexity sets the MAC metric.
+func (b *Builder) ModifiedAttackComplexity(v AttackComplexity) *Builder {
+	b.v.ModifiedAttackComplexity = v
+	return b
+}
+
+// ModifiedPrivilegesRequired sets the MPR metric.
+func (b *Builder) ModifiedPrivilegesRequired(v PrivilegesRequired) *Builder {
+	b.v.ModifiedPrivilegesRequired = v
+	return b
+}
+
+// ModifiedUserInteraction sets the MUI metric.
+func (b *Builder) ModifiedUserInteraction(v UserInteraction) *Builder {
+	b.v.ModifiedUserInteraction = v
+	return b
+}
+
+// ModifiedScope sets the MS metric.
+func (b *Builder) ModifiedScope(v Scope) *Builder {
+	b.v.ModifiedScope = v
+	return b
+}
+
+// ModifiedConfidentiality sets the MC metric.
+func (b *Builder) ModifiedConfidentiality(v ConfidentialityImpact) *Builder {
+	b.v.ModifiedConfidentiality = v
+	return b
+}
+
+// ModifiedIntegrity sets the MI metric.
+func (b *Builder) ModifiedIntegrity(v IntegrityImpact) *Builder {
+	b.v.ModifiedIntegrity = v
+	return b
+}
+
+// ModifiedAvailability sets the MA metric.
+func (b *Builder) ModifiedAvailability(v AvailabilityImpact) *Builder {
+	b.v.ModifiedAvailability = v
+	return b
+}
+
+// Build returns the composed Vector,
+// or returns MetricErrors if any metric is undefined or invalid.
+func (b *Builder) Build() (Vector, error) {
+	var v = b.v
+	var errs MetricErrors
+	for _, m := range []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{"CVSS", string(v.Version), v.Version.isDefined()},
+		// base metrics
+		{"AV", string(v.AttackVector), v.AttackVector.isDefined()},
+		{"AC", string(v.AttackComplexity), v.AttackComplexity.isDefined()},
+		{"PR", string(v.PrivilegesRequired), v.PrivilegesRequired.isDefined()},
+		{"UI", string(v.UserInteraction), v.UserInteraction.isDefined()},
+		{"S", string(v.Scope), v.Scope.isDefined()},
+		{"C", string(v.ConfidentialityImpact), v.ConfidentialityImpact.isDefined()},
+		{"I", string(v.IntegrityImpact), v.IntegrityImpact.isDefined()},
+		{"A", string(v.AvailabilityImpact), v.AvailabilityImpact.isDefined()},
+		// temporal metrics
+		{"E", string(v.ExploitCodeMaturity), v.ExploitCodeMaturity.isValid()},
+		{"RL", string(v.RemediationLevel), v.RemediationLevel.isValid()},
+		{"RC", string(v.ReportConfidence), v.ReportConfidence.isValid()},
+		// environmental metrics
+		{"CR", string(v.ConfidentialityRequirement), v.ConfidentialityRequirement.isValid()},
+		{"IR", string(v.IntegrityRequirement), v.IntegrityRequirement.isValid()},
+		{"AR", string(v.AvailabilityRequirement), v.AvailabilityRequirement.isValid()},
+		{"MAV", string(v.ModifiedAttackVector), v.ModifiedAttackVector.isValid()},
+		{"MAC", string(v.ModifiedAttackComplexity), v.ModifiedAttackComplexity.isValid()},
+		{"MPR", string(v.ModifiedPrivilegesRequired), v.ModifiedPrivilegesRequired.isValid()},
+		{"MUI", string(v.ModifiedUserInteraction), v.ModifiedUserInteraction.isValid()},
+		{"MS", string(v.ModifiedScope), v.ModifiedScope.isValid()},
+		{"MC", string(v.ModifiedConfidentiality), v.ModifiedConfidentiality.isValid()},
+		{"MI", string(v.ModifiedIntegrity), v.ModifiedIntegrity.isValid()},
+		{"MA", string(v.ModifiedAvailability), v.ModifiedAvailability.isValid()},
+	} {
+		if !m.valid {
+			errs = append(errs, MetricError{Metric: m.name, Value: m.value})
+		}
+	}
+	if len(errs) != 0 {
+		return Vector{}, errs
+	}
+	return v, nil
+}
@@ -0,0 +1,125 @@
+package cvssv3
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBuilder_Build(t *testing.T) {
+	type output struct {
+		vector  string
+		metrics []string
+		err     string
+	}
+	var testCases = []struct {
+		name     string
+		given    *Builder
+		expected output
+	}{
+		{
+			name: "base metrics",
+			given: NewBuilder().
+				AttackVector(AttackVectorNetwork).
+				AttackComplexity(AttackComplexityLow).
+				PrivilegesRequired(PrivilegesRequiredLow).
+				UserInteraction(UserInteractionNone).
+				Scope(ScopeChanged).
+				ConfidentialityImpact(ConfidentialityImpactHigh).
+				IntegrityImpact(IntegrityImpactHigh).
+				AvailabilityImpact(AvailabilityImpactHigh),
+			expected: output{
+				vector: "CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:H/I:H/A:H",
+			},
+		},
+		{
+			name: "optional metrics",
+			given: NewBuilder().
+				Version(Version30).
+				AttackVector(AttackVectorNetwork).
+				AttackComplexity(AttackComplexityLow).
+				PrivilegesRequired(PrivilegesRequiredLow).
+				UserInteraction(UserInteractionNone).
+				Scope(ScopeChanged).
+				ConfidentialityImpact(ConfidentialityImpactHigh).
+				IntegrityImpact(IntegrityImpactHigh).
+				AvailabilityImpact(AvailabilityImpactHigh).
+				ExploitCodeMaturity(ExploitCodeMaturityProofOfConcept).
+				ConfidentialityRequirement(SecurityRequirementLow).
+				ModifiedScope(ScopeUnchanged),
+			expected: output{
+				vector: "CVSS:3.0/AV:N/AC:L/PR:L/UI:N/S:C/C:H/I:H/A:H/E:P/CR:L/MS:U",
+			},
+		},
+		{
+			name: "overridden metric",
+			given: NewBuilder().
+				AttackVector("Z").
+				AttackVector(AttackVectorLocal).
+				AttackComplexity(AttackComplexityHigh).
+				PrivilegesRequired(PrivilegesRequiredNone).
+				UserInteraction(UserInteractionRequired).
+				Scope(ScopeUnchanged).
+				ConfidentialityImpact(ConfidentialityImpactLow).
+				IntegrityImpact(IntegrityImpactNone).
+				AvailabilityImpact(AvailabilityImpactNone),
+			expected: output{
+				vector: "CVSS:3.1/AV:L/AC:H/PR:N/UI:R/S:U/C:L/I:N/A:N",
+			},
+		},
+		{
+			name: "unset mandatory metrics",
+			given: NewBuilder().
+				AttackVector(AttackVectorNetwork).
+				AttackComplexity(AttackComplexityLow).
+				PrivilegesRequired(PrivilegesRequiredLow).
+				UserInteraction(UserInteractionNone).
+				ConfidentialityImpact(ConfidentialityImpactHigh).
+				IntegrityImpact(IntegrityImpactHigh),
+			expected: output{
+				metrics: []string{"S", "A"},
+				err:     "undefined metric 'S' in CVSS(V3) vector; undefined metric 'A' in CVSS(V3) vector",
+			},
+		},
+		{
+			name: "invalid metrics",
+			given: NewBuilder().
+				Version("3.2").
+				AttackVector(AttackVectorNotDefined).
+				AttackComplexity(AttackComplexityLow).
+				PrivilegesRequired(PrivilegesRequiredLow).
+				UserInteraction(UserInteractionNone).
+				Scope(ScopeChanged).
+				ConfidentialityImpact(ConfidentialityImpactHigh).
+				IntegrityImpact(IntegrityImpactHigh).
+				AvailabilityImpact(AvailabilityImpactHigh).
+				RemediationLevel("ND").
+				ModifiedPrivilegesRequired("M"),
+			expected: output{
+				metrics: []string{"CVSS", "AV", "RL", "MPR"},
+				err: "invalid value '3.2' of metric 'CVSS' in CVSS(V3) vector; " +
+					"invalid value 'X' of metric 'AV' in CVSS(V3) vector; " +
+					"invalid value 'ND' of metric 'RL' in CVSS(V3) vector; " +
+					"invalid value 'M' of metric 'MPR' in CVSS(V3) vector",
+			},
+		},
+	}
+	for _, c := range testCases {
+		var v, err = c.given.Build()
+		var actual output
+		if err != nil {
+			var me MetricErrors
+			if !errors.As(err, &me) {
+				t.Errorf("%s: error should be MetricErrors, but got %T", c.name, err)
+				continue
+			}
+			actual.metrics = me.Metrics()
+			actual.err = err.Error()
+		} else {
+			actual.vector = v.String()
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s: build == %#v, but got %#v", c.name, c.expected, actual)
+		}
+	}
+}
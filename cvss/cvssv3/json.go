@@ -0,0 +1,229 @@
+package cvssv3
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/seal-io/meta-api/cvss/compatible"
+)
+
+// JSONData holds the JSON data representation of CVSS(V3),
+// according to https://www.first.org/cvss/cvss-v3.1.json and https://www.first.org/cvss/cvss-v3.0.json.
+type JSONData struct {
+	Version      string `json:"version"`
+	VectorString string `json:"vectorString"`
+	// base metrics
+	AttackVector          string   `json:"attackVector,omitempty"`
+	AttackComplexity      string   `json:"attackComplexity,omitempty"`
+	PrivilegesRequired    string   `json:"privilegesRequired,omitempty"`
+	UserInteraction       string   `json:"userInteraction,omitempty"`
+	Scope                 string   `json:"scope,omitempty"`
+	ConfidentialityImpact string   `json:"confidentialityImpact,omitempty"`
+	IntegrityImpact       string   `json:"integrityImpact,omitempty"`
+	AvailabilityImpact    string   `json:"availabilityImpact,omitempty"`
+	BaseScore             float64  `json:"baseScore"`
+	BaseSeverity          Severity `json:"baseSeverity"`
+	// temporal metrics
+	ExploitCodeMaturity string   `json:"exploitCodeMaturity,omitempty"`
+	RemediationLevel    string   `json:"remediationLevel,omitempty"`
+	ReportConfidence    string   `json:"reportConfidence,omitempty"`
+	TemporalScore       *float64 `json:"temporalScore,omitempty"`
+	TemporalSeverity    Severity `json:"temporalSeverity,omitempty"`
+	// environmental metrics
+	ConfidentialityRequirement    string   `json:"confidentialityRequirement,omitempty"`
+	IntegrityRequirement          string   `json:"integrityRequirement,omitempty"`
+	AvailabilityRequirement       string   `json:"availabilityRequirement,omitempty"`
+	ModifiedAttackVector          string   `json:"modifiedAttackVector,omitempty"`
+	ModifiedAttackComplexity      string   `json:"modifiedAttackComplexity,omitempty"`
+	ModifiedPrivilegesRequired    string   `json:"modifiedPrivilegesRequired,omitempty"`
+	ModifiedUserInteraction       string   `json:"modifiedUserInteraction,omitempty"`
+	ModifiedScope                 string   `json:"modifiedScope,omitempty"`
+	ModifiedConfidentialityImpact string   `json:"modifiedConfidentialityImpact,omitempty"`
+	ModifiedIntegrityImpact       string   `json:"modifiedIntegrityImpact,omitempty"`
+	ModifiedAvailabilityImpact    string   `json:"modifiedAvailabilityImpact,omitempty"`
+	EnvironmentalScore            *float64 `json:"environmentalScore,omitempty"`
+	EnvironmentalSeverity         Severity `json:"environmentalSeverity,omitempty"`
+}
+
+// ToJSONData converts this CVSS(V3) vector to the JSON data representation,
+// the not defined optional metrics are omitted,
+// the temporal or environmental score is omitted if none of the temporal or environmental metrics is defined.
+func (in Vector) ToJSONData() JSONData {
+	var c = DefaultVector().Override(in)
+	var d = JSONData{
+		Version:      c.GetVersion(),
+		VectorString: c.String(),
+	}
+	var temporal, environmental bool
+	var fs = d.fields()
+	for _, m := range c.Metrics() {
+		if m.Value == "X" {
+			continue
+		}
+		var f = fs[m.Abbreviation]
+		*f.field = f.values[m.Value]
+		switch m.Group {
+		case compatible.MetricGroupTemporal:
+			temporal = true
+		case compatible.MetricGroupEnvironmental:
+			environmental = true
+		}
+	}
+	d.BaseScore, d.BaseSeverity = c.BaseScoreAndSeverity()
+	if temporal {
+		var s, sv = c.TemporalScoreAndSeverity(d.BaseScore)
+		d.TemporalScore, d.TemporalSeverity = &s, sv
+	}
+	if environmental {
+		var s, sv = c.EnvironmentalScoreAndSeverity()
+		d.EnvironmentalScore, d.EnvironmentalSeverity = &s, sv
+	}
+	return d
+}
+
+// FromJSONData converts the given JSON data representation to CVSS(V3) vector,
+// the vector is parsed from the vectorString, or composed by the metrics if the vectorString is blank,
+// the given metrics and version must be consistent with the vector,
+// the given scores and severities are ignored, which are always calculated from the vector.
+func FromJSONData(d JSONData) (Vector, error) {
+	var fs = d.fields()
+	var s = strings.TrimSpace(d.VectorString)
+	if s == "" {
+		var version = d.Version
+		if version == "" {
+			version = string(Version31)
+		}
+		var parts = []string{"CVSS:" + version}
+		for _, mn := range metricOrder[1:] {
+			var f = fs[mn]
+			if *f.field == "" {
+				continue
+			}
+			var mv, ok = f.abbreviate(*f.field)
+			if !ok {
+				return Vector{}, fmt.Errorf("invalid value '%s' of field '%s' in CVSS(V3) JSON data", *f.field, f.name)
+			}
+			parts = append(parts, mn+":"+mv)
+		}
+		s = strings.Join(parts, "/")
+	}
+	var v, err = Parse(s)
+	if err != nil {
+		return Vector{}, err
+	}
+	if d.Version != "" && d.Version != string(v.Version) {
+		return Vector{}, fmt.Errorf("inconsistent version '%s' of CVSS(V3) JSON data: %s", d.Version, s)
+	}
+	for _, m := range v.Metrics() {
+		var f = fs[m.Abbreviation]
+		if *f.field == "" {
+			continue
+		}
+		if *f.field != f.values[m.Value] {
+			return Vector{}, fmt.Errorf("inconsistent value '%s' of field '%s' in CVSS(V3) JSON data: %s", *f.field, f.name, s)
+		}
+	}
+	return v, nil
+}
+
+// MarshalJSON implements json.Marshaler,
+// marshals this CVSS(V3) vector as the JSON data representation.
+func (in Vector) MarshalJSON() ([]byte, error) {
+	return json.Marshal(in.ToJSONData())
+}
+
+// UnmarshalJSON implements json.Unmarshaler,
+// unmarshals the JSON data representation or the vector string to this CVSS(V3) vector.
+func (in *Vector) UnmarshalJSON(bs []byte) error {
+	bs = bytes.TrimSpace(bs)
+	if len(bs) != 0 && bs[0] == '"' {
+		var s string
+		if err := json.Unmarshal(bs, &s); err != nil {
+			return err
+		}
+		var v, err = Parse(s)
+		if err != nil {
+			return err
+		}
+		*in = v
+		return nil
+	}
+	var d JSONData
+	if err := json.Unmarshal(bs, &d); err != nil {
+		return err
+	}
+	var v, err = FromJSONData(d)
+	if err != nil {
+		return err
+	}
+	*in = v
+	return nil
+}
+
+type jsonField struct {
+	name   string
+	field  *string
+	values map[string]string
+}
+
+// abbreviate returns the abbreviated metric value of the given JSON enumeration.
+func (in jsonField) abbreviate(e string) (string, bool) {
+	for mv, me := range in.values {
+		if me == e {
+			return mv, true
+		}
+	}
+	return "", false
+}
+
+// fields returns the metric fields of this JSONData indexed by the abbreviated metric names.
+func (in *JSONData) fields() map[string]jsonField {
+	return map[string]jsonField{
+		// base metrics
+		"AV": {"attackVector", &in.AttackVector, attackVectorEnums},
+		"AC": {"attackComplexity", &in.AttackComplexity, attackComplexityEnums},
+		"PR": {"privilegesRequired", &in.PrivilegesRequired, privilegesRequiredEnums},
+		"UI": {"userInteraction", &in.UserInteraction, userInteractionEnums},
+		"S":  {"scope", &in.Scope, scopeEnums},
+		"C":  {"confidentialityImpact", &in.ConfidentialityImpact, impactEnums},
+		"I":  {"integrityImpact", &in.IntegrityImpact, impactEnums},
+		"A":  {"availabilityImpact", &in.AvailabilityImpact, impactEnums},
+		// temporal metrics
+		"E": {"exploitCodeMaturity", &in.ExploitCodeMaturity, map[string]string{
+			"U": "UNPROVEN", "P": "PROOF_OF_CONCEPT", "F": "FUNCTIONAL", "H": "HIGH", "X": "NOT_DEFINED",
+		}},
+		"RL": {"remediationLevel", &in.RemediationLevel, map[string]string{
+			"O": "OFFICIAL_FIX", "T": "TEMPORARY_FIX", "W": "WORKAROUND", "U": "UNAVAILABLE", "X": "NOT_DEFINED",
+		}},
+		"RC": {"reportConfidence", &in.ReportConfidence, map[string]string{
+			"U": "UNKNOWN", "R": "REASONABLE", "C": "CONFIRMED", "X": "NOT_DEFINED",
+		}},
+		// environmental metrics
+		"CR":  {"confidentialityRequirement", &in.ConfidentialityRequirement, requirementEnums},
+		"IR":  {"integrityRequirement", &in.IntegrityRequirement, requirementEnums},
+		"AR":  {"availabilityRequirement", &in.AvailabilityRequirement, requirementEnums},
+		"MAV": {"modifiedAttackVector", &in.ModifiedAttackVector, attackVectorEnums},
+		"MAC": {"modifiedAttackComplexity", &in.ModifiedAttackComplexity, attackComplexityEnums},
+		"MPR": {"modifiedPrivilegesRequired", &in.ModifiedPrivilegesRequired, privilegesRequiredEnums},
+		"MUI": {"modifiedUserInteraction", &in.ModifiedUserInteraction, userInteractionEnums},
+		"MS":  {"modifiedScope", &in.ModifiedScope, scopeEnums},
+		"MC":  {"modifiedConfidentialityImpact", &in.ModifiedConfidentialityImpact, impactEnums},
+		"MI":  {"modifiedIntegrityImpact", &in.ModifiedIntegrityImpact, impactEnums},
+		"MA":  {"modifiedAvailabilityImpact", &in.ModifiedAvailabilityImpact, impactEnums},
+	}
+}
+
+// JSON enumerations shared by the base metrics and the modified base metrics.
+var (
+	attackVectorEnums = map[string]string{
+		"N": "NETWORK", "A": "ADJACENT_NETWORK", "L": "LOCAL", "P": "PHYSICAL", "X": "NOT_DEFINED",
+	}
+	attackComplexityEnums   = map[string]string{"L": "LOW", "H": "HIGH", "X": "NOT_DEFINED"}
+	privilegesRequiredEnums = map[string]string{"N": "NONE", "L": "LOW", "H": "HIGH", "X": "NOT_DEFINED"}
+	userInteractionEnums    = map[string]string{"N": "NONE", "R": "REQUIRED", "X": "NOT_DEFINED"}
+	scopeEnums              = map[string]string{"U": "UNCHANGED", "C": "CHANGED", "X": "NOT_DEFINED"}
+	impactEnums             = map[string]string{"N": "NONE", "L": "LOW", "H": "HIGH", "X": "NOT_DEFINED"}
+	requirementEnums        = map[string]string{"L": "LOW", "M": "MEDIUM", "H": "HIGH", "X": "NOT_DEFINED"}
+)
@@ -0,0 +1,160 @@
+package cvssv3
+
+import (
+	_ "embed"
+	"encoding/json"
+	"testing"
+
+	"github.com/seal-io/meta-api/cvss/internal/jsonschema"
+)
+
+var (
+	//go:embed testdata/cvss-v3.0.json
+	schemaV30 []byte
+	//go:embed testdata/cvss-v3.1.json
+	schemaV31 []byte
+)
+
+func TestVector_MarshalJSON(t *testing.T) {
+	var testCases = []struct {
+		given    string
+		expected string
+	}{
+		{
+			given:    "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+			expected: `{"version":"3.1","vectorString":"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H","attackVector":"NETWORK","attackComplexity":"LOW","privilegesRequired":"NONE","userInteraction":"NONE","scope":"UNCHANGED","confidentialityImpact":"HIGH","integrityImpact":"HIGH","availabilityImpact":"HIGH","baseScore":9.8,"baseSeverity":"CRITICAL"}`,
+		},
+		{
+			given:    "CVSS:3.0/AV:A/AC:H/PR:L/UI:R/S:C/C:L/I:N/A:N/E:P/RL:O",
+			expected: `{"version":"3.0","vectorString":"CVSS:3.0/AV:A/AC:H/PR:L/UI:R/S:C/C:L/I:N/A:N/E:P/RL:O","attackVector":"ADJACENT_NETWORK","attackComplexity":"HIGH","privilegesRequired":"LOW","userInteraction":"REQUIRED","scope":"CHANGED","confidentialityImpact":"LOW","integrityImpact":"NONE","availabilityImpact":"NONE","baseScore":2.6,"baseSeverity":"LOW","exploitCodeMaturity":"PROOF_OF_CONCEPT","remediationLevel":"OFFICIAL_FIX","temporalScore":2.4,"temporalSeverity":"LOW"}`,
+		},
+		{
+			given:    "CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:H/I:H/A:H/CR:L/MAV:P/MC:L",
+			expected: `{"version":"3.1","vectorString":"CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:H/I:H/A:H/CR:L/MAV:P/MC:L","attackVector":"NETWORK","attackComplexity":"LOW","privilegesRequired":"LOW","userInteraction":"NONE","scope":"CHANGED","confidentialityImpact":"HIGH","integrityImpact":"HIGH","availabilityImpact":"HIGH","baseScore":9.9,"baseSeverity":"CRITICAL","confidentialityRequirement":"LOW","modifiedAttackVector":"PHYSICAL","modifiedConfidentialityImpact":"LOW","environmentalScore":7.2,"environmentalSeverity":"HIGH"}`,
+		},
+	}
+	for _, c := range testCases {
+		var v = ShouldParse(c.given)
+		var actual, err = json.Marshal(v)
+		if err != nil {
+			t.Errorf("error marshalling %s: %v", c.given, err)
+			continue
+		}
+		if string(actual) != c.expected {
+			t.Errorf("marshal %s == \n%s\n, but got \n%s", c.given, c.expected, actual)
+		}
+
+		var schema = schemaV31
+		if v.Version == Version30 {
+			schema = schemaV30
+		}
+		if err = jsonschema.Validate(schema, actual); err != nil {
+			t.Errorf("error validating %s: %v", actual, err)
+		}
+
+		var r Vector
+		if err = json.Unmarshal(actual, &r); err != nil {
+			t.Errorf("error unmarshalling %s: %v", actual, err)
+			continue
+		}
+		if r != v {
+			t.Errorf("unmarshal %s == %v, but got %v", actual, v, r)
+		}
+	}
+}
+
+func TestVector_UnmarshalJSON(t *testing.T) {
+	type output struct {
+		vector string
+		err    bool
+	}
+	var testCases = []struct {
+		given    string
+		expected output
+	}{
+		{
+			given: `"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"`,
+			expected: output{
+				vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+			},
+		},
+		{
+			given: `{"version":"3.1","vectorString":"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H","baseScore":9.8,"baseSeverity":"CRITICAL"}`,
+			expected: output{
+				vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+			},
+		},
+		{
+			given: `{"version":"3.0","attackVector":"LOCAL","attackComplexity":"LOW","privilegesRequired":"LOW","userInteraction":"NONE","scope":"UNCHANGED","confidentialityImpact":"HIGH","integrityImpact":"NONE","availabilityImpact":"NONE","reportConfidence":"NOT_DEFINED","modifiedScope":"CHANGED"}`,
+			expected: output{
+				vector: "CVSS:3.0/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:N/A:N/MS:C",
+			},
+		},
+		{
+			given: `{"version":"3.0","vectorString":"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H","baseScore":9.8,"baseSeverity":"CRITICAL"}`,
+			expected: output{
+				err: true,
+			},
+		},
+		{
+			given: `{"version":"3.1","vectorString":"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H","attackVector":"LOCAL","baseScore":9.8,"baseSeverity":"CRITICAL"}`,
+			expected: output{
+				err: true,
+			},
+		},
+		{
+			given: `{"version":"3.1","attackVector":"REMOTE","attackComplexity":"LOW","privilegesRequired":"LOW","userInteraction":"NONE","scope":"UNCHANGED","confidentialityImpact":"HIGH","integrityImpact":"NONE","availabilityImpact":"NONE"}`,
+			expected: output{
+				err: true,
+			},
+		},
+	}
+	for _, c := range testCases {
+		var v Vector
+		var err = json.Unmarshal([]byte(c.given), &v)
+		var actual = output{err: err != nil}
+		if err == nil {
+			actual.vector = v.String()
+		}
+		if actual != c.expected {
+			t.Errorf("unmarshal %s == %v, but got %v", c.given, c.expected, actual)
+		}
+	}
+}
+
+func TestJSONData_schema(t *testing.T) {
+	var testCases = []struct {
+		given    JSONData
+		expected bool
+	}{
+		{
+			given:    ShouldParse("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/E:X/MUI:X").ToJSONData(),
+			expected: true,
+		},
+		{
+			given: JSONData{
+				Version:      "3.1",
+				VectorString: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+				BaseScore:    11,
+				BaseSeverity: "SEVERE",
+			},
+			expected: false,
+		},
+		{
+			given: JSONData{
+				Version:      "3.0",
+				VectorString: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+				BaseScore:    9.8,
+				BaseSeverity: SeverityCritical,
+			},
+			expected: false,
+		},
+	}
+	for _, c := range testCases {
+		var bs, _ = json.Marshal(c.given)
+		var err = jsonschema.Validate(schemaV31, bs)
+		if actual := err == nil; actual != c.expected {
+			t.Errorf("validate %s == %v, but got %v: %v", bs, c.expected, actual, err)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package cvssv3
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/seal-io/meta-api/cvss/compatible"
+)
+
+// Metrics returns the metrics of this CVSS(V3) vector in order of the vector string,
+// including the not defined optional metrics.
+func (in Vector) Metrics() []compatible.Metric {
+	var c = DefaultVector().Override(in)
+	var ms = c.Scope
+	if c.ModifiedScope.isDefined() {
+		ms = c.ModifiedScope
+	}
+	return []compatible.Metric{
+		// base metrics
+		newMetric(compatible.MetricGroupBase, "AV", string(c.AttackVector), c.getAttackVector()),
+		newMetric(compatible.MetricGroupBase, "AC", string(c.AttackComplexity), c.getAttackComplexity()),
+		newMetric(compatible.MetricGroupBase, "PR", string(c.PrivilegesRequired), c.getPrivilegesRequired()),
+		newMetric(compatible.MetricGroupBase, "UI", string(c.UserInteraction), c.getUserInteraction()),
+		newMetric(compatible.MetricGroupBase, "S", string(c.Scope), getScope(c.Scope)),
+		newMetric(compatible.MetricGroupBase, "C", string(c.ConfidentialityImpact), c.getConfidentialityImpact()),
+		newMetric(compatible.MetricGroupBase, "I", string(c.IntegrityImpact), c.getIntegrityImpact()),
+		newMetric(compatible.MetricGroupBase, "A", string(c.AvailabilityImpact), c.getAvailabilityImpact()),
+		// temporal metrics
+		newMetric(compatible.MetricGroupTemporal, "E", string(c.ExploitCodeMaturity), c.getExploitCodeMaturity()),
+		newMetric(compatible.MetricGroupTemporal, "RL", string(c.RemediationLevel), c.getRemediationLevel()),
+		newMetric(compatible.MetricGroupTemporal, "RC", string(c.ReportConfidence), c.getReportConfidence()),
+		// environmental metrics
+		newMetric(compatible.MetricGroupEnvironmental, "CR", string(c.ConfidentialityRequirement), c.getConfidentialityRequirement()),
+		newMetric(compatible.MetricGroupEnvironmental, "IR", string(c.IntegrityRequirement), c.getIntegrityRequirement()),
+		newMetric(compatible.MetricGroupEnvironmental, "AR", string(c.AvailabilityRequirement), c.getAvailabilityRequirement()),
+		newMetric(compatible.MetricGroupEnvironmental, "MAV", string(c.ModifiedAttackVector),
+			modified(c.getModifiedAttackVector(), c.getAttackVector())),
+		newMetric(compatible.MetricGroupEnvironmental, "MAC", string(c.ModifiedAttackComplexity),
+			modified(c.getModifiedAttackComplexity(), c.getAttackComplexity())),
+		newMetric(compatible.MetricGroupEnvironmental, "MPR", string(c.ModifiedPrivilegesRequired),
+			modified(c.getModifiedPrivilegesRequired(), c.getPrivilegesRequired())),
+		newMetric(compatible.MetricGroupEnvironmental, "MUI", string(c.ModifiedUserInteraction),
+			modified(c.getModifiedUserInteraction(), c.getUserInteraction())),
+		newMetric(compatible.MetricGroupEnvironmental, "MS", string(c.ModifiedScope), getScope(ms)),
+		newMetric(compatible.MetricGroupEnvironmental, "MC", string(c.ModifiedConfidentiality),
+			modified(c.getModifiedConfidentiality(), c.getConfidentialityImpact())),
+		newMetric(compatible.MetricGroupEnvironmental, "MI", string(c.ModifiedIntegrity),
+			modified(c.getModifiedIntegrity(), c.getIntegrityImpact())),
+		newMetric(compatible.MetricGroupEnvironmental, "MA", string(c.ModifiedAvailability),
+			modified(c.getModifiedAvailability(), c.getAvailabilityImpact())),
+	}
+}
+
+// Explain returns the derivation of the scores of this CVSS(V3) vector,
+// according to https://www.first.org/cvss/v3.1/specification-document#7-Environmental-Metrics-Equations.
+func (in Vector) Explain() compatible.Explanation {
+	var c = DefaultVector().Override(in)
+	var e = compatible.Explanation{Version: c.GetVersion()}
+	var step = func(g compatible.MetricGroup, n, f, x string, v float64) {
+		e.Steps = append(e.Steps, compatible.Step{Group: g, Name: n, Formula: f, Expression: x, Value: v})
+	}
+
+	// base equation
+	var iss = 1 - (1-c.getConfidentialityImpact())*(1-c.getIntegrityImpact())*(1-c.getAvailabilityImpact())
+	step(compatible.MetricGroupBase, "ISS",
+		"1 - [(1 - C) × (1 - I) × (1 - A)]",
+		fmt.Sprintf("1 - [(1 - %s) × (1 - %s) × (1 - %s)]",
+			ftoa(c.getConfidentialityImpact()), ftoa(c.getIntegrityImpact()), ftoa(c.getAvailabilityImpact())),
+		iss)
+	var i = c.RawImpactScore(false)
+	if c.Scope == ScopeUnchanged {
+		step(compatible.MetricGroupBase, "Impact",
+			"6.42 × ISS",
+			fmt.Sprintf("6.42 × %s", ftoa(iss)),
+			i)
+	} else {
+		step(compatible.MetricGroupBase, "Impact",
+			"7.52 × (ISS - 0.029) - 3.25 × (ISS - 0.02)^15",
+			fmt.Sprintf("7.52 × (%s - 0.029) - 3.25 × (%s - 0.02)^15", ftoa(iss), ftoa(iss)),
+			i)
+	}
+	var x = c.RawExploitabilityScore(false)
+	step(compatible.MetricGroupBase, "Exploitability",
+		"8.22 × AV × AC × PR × UI",
+		fmt.Sprintf("8.22 × %s × %s × %s × %s",
+			ftoa(c.getAttackVector()), ftoa(c.getAttackComplexity()),
+			ftoa(c.getPrivilegesRequired()), ftoa(c.getUserInteraction())),
+		x)
+	var bs = c.BaseScore()
+	switch {
+	case i <= 0:
+		step(compatible.MetricGroupBase, "Base Score",
+			"0 if Impact <= 0",
+			"",
+			bs)
+	case c.Scope == ScopeUnchanged:
+		step(compatible.MetricGroupBase, "Base Score",
+			"Roundup(Minimum(Impact + Exploitability, 10))",
+			fmt.Sprintf("Roundup(Minimum(%s + %s, 10))", ftoa(i), ftoa(x)),
+			bs)
+	default:
+		step(compatible.MetricGroupBase, "Base Score",
+			"Roundup(Minimum(1.08 × (Impact + Exploitability), 10))",
+			fmt.Sprintf("Roundup(Minimum(1.08 × (%s + %s), 10))", ftoa(i), ftoa(x)),
+			bs)
+	}
+
+	// temporal equation
+	step(compatible.MetricGroupTemporal, "Temporal Score",
+		"Roundup(BaseScore × E × RL × RC)",
+		fmt.Sprintf("Roundup(%s × %s × %s × %s)",
+			ftoa(bs), ftoa(c.getExploitCodeMaturity()), ftoa(c.getRemediationLevel()), ftoa(c.getReportConfidence())),
+		c.TemporalScore(bs))
+
+	// environmental equation
+	var (
+		mc   = modified(c.getModifiedConfidentiality(), c.getConfidentialityImpact())
+		mi   = modified(c.getModifiedIntegrity(), c.getIntegrityImpact())
+		ma   = modified(c.getModifiedAvailability(), c.getAvailabilityImpact())
+		cr   = c.getConfidentialityRequirement()
+		ir   = c.getIntegrityRequirement()
+		ar   = c.getAvailabilityRequirement()
+		miss = math.Min(0.915, 1-(1-mc*cr)*(1-mi*ir)*(1-ma*ar))
+	)
+	step(compatible.MetricGroupEnvironmental, "MISS",
+		"Minimum(1 - [(1 - CR × MC) × (1 - IR × MI) × (1 - AR × MA)], 0.915)",
+		fmt.Sprintf("Minimum(1 - [(1 - %s × %s) × (1 - %s × %s) × (1 - %s × %s)], 0.915)",
+			ftoa(cr), ftoa(mc), ftoa(ir), ftoa(mi), ftoa(ar), ftoa(ma)),
+		miss)
+	var s = c.Scope
+	if c.ModifiedScope.isDefined() {
+		s = c.ModifiedScope
+	}
+	var mImpact = c.RawImpactScore(true)
+	switch {
+	case s == ScopeUnchanged:
+		step(compatible.MetricGroupEnvironmental, "Modified Impact",
+			"6.42 × MISS",
+			fmt.Sprintf("6.42 × %s", ftoa(miss)),
+			mImpact)
+	case c.ModifiedScope.isDefined() && c.Version == Version31:
+		step(compatible.MetricGroupEnvironmental, "Modified Impact",
+			"7.52 × (MISS - 0.029) - 3.25 × (MISS × 0.9731 - 0.02)^13",
+			fmt.Sprintf("7.52 × (%s - 0.029) - 3.25 × (%s × 0.9731 - 0.02)^13", ftoa(miss), ftoa(miss)),
+			mImpact)
+	default:
+		step(compatible.MetricGroupEnvironmental, "Modified Impact",
+			"7.52 × (MISS - 0.029) - 3.25 × (MISS - 0.02)^15",
+			fmt.Sprintf("7.52 × (%s - 0.029) - 3.25 × (%s - 0.02)^15", ftoa(miss), ftoa(miss)),
+			mImpact)
+	}
+	var mx = c.RawExploitabilityScore(true)
+	step(compatible.MetricGroupEnvironmental, "Modified Exploitability",
+		"8.22 × MAV × MAC × MPR × MUI",
+		fmt.Sprintf("8.22 × %s × %s × %s × %s",
+			ftoa(modified(c.getModifiedAttackVector(), c.getAttackVector())),
+			ftoa(modified(c.getModifiedAttackComplexity(), c.getAttackComplexity())),
+			ftoa(modified(c.getModifiedPrivilegesRequired(), c.getPrivilegesRequired())),
+			ftoa(modified(c.getModifiedUserInteraction(), c.getUserInteraction()))),
+		mx)
+	var es = c.EnvironmentalScore()
+	var t = fmt.Sprintf("%s × %s × %s",
+		ftoa(c.getExploitCodeMaturity()), ftoa(c.getRemediationLevel()), ftoa(c.getReportConfidence()))
+	switch {
+	case mImpact <= 0:
+		step(compatible.MetricGroupEnvironmental, "Environmental Score",
+			"0 if ModifiedImpact <= 0",
+			"",
+			es)
+	case s == ScopeUnchanged:
+		step(compatible.MetricGroupEnvironmental, "Environmental Score",
+			"Roundup(Roundup(Minimum(ModifiedImpact + ModifiedExploitability, 10)) × E × RL × RC)",
+			fmt.Sprintf("Roundup(Roundup(Minimum(%s + %s, 10)) × %s)", ftoa(mImpact), ftoa(mx), t),
+			es)
+	default:
+		step(compatible.MetricGroupEnvironmental, "Environmental Score",
+			"Roundup(Roundup(Minimum(1.08 × (ModifiedImpact + ModifiedExploitability), 10)) × E × RL × RC)",
+			fmt.Sprintf("Roundup(Roundup(Minimum(1.08 × (%s + %s), 10)) × %s)", ftoa(mImpact), ftoa(mx), t),
+			es)
+	}
+
+	return e
+}
+
+// getScope returns the coefficient of the given Scope used in the score calculating.
+func getScope(s Scope) float64 {
+	if s == ScopeChanged {
+		return 1.08
+	}
+	return 1
+}
+
+// modified returns the base weight if the modified weight is not defined.
+func modified(m, b float64) float64 {
+	if m != 1 {
+		return m
+	}
+	return b
+}
+
+func ftoa(f float64) string {
+	return compatible.FormatFloat(f)
+}
+
+func newMetric(g compatible.MetricGroup, mn, mv string, w float64) compatible.Metric {
+	var n = metricNames[mn]
+	return compatible.Metric{
+		Group:        g,
+		Abbreviation: mn,
+		Name:         n.name,
+		Value:        mv,
+		ValueName:    n.values[mv],
+		Weight:       w,
+	}
+}
+
+// metric value names shared by the base metrics and the modified base metrics.
+var (
+	attackVectorNames = map[string]string{
+		"N": "Network", "A": "Adjacent Network", "L": "Local", "P": "Physical", "X": "Not Defined",
+	}
+	attackComplexityNames   = map[string]string{"L": "Low", "H": "High", "X": "Not Defined"}
+	privilegesRequiredNames = map[string]string{"N": "None", "L": "Low", "H": "High", "X": "Not Defined"}
+	userInteractionNames    = map[string]string{"N": "None", "R": "Required", "X": "Not Defined"}
+	scopeNames              = map[string]string{"U": "Unchanged", "C": "Changed", "X": "Not Defined"}
+	impactNames             = map[string]string{"H": "High", "L": "Low", "N": "None", "X": "Not Defined"}
+	requirementNames        = map[string]string{"H": "High", "M": "Medium", "L": "Low", "X": "Not Defined"}
+)
+
+// metricNames holds the full names of the metrics and the metric values.
+var metricNames = map[string]struct {
+	name   string
+	values map[string]string
+}{
+	// base metrics
+	"AV": {"Attack Vector", attackVectorNames},
+	"AC": {"Attack Complexity", attackComplexityNames},
+	"PR": {"Privileges Required", privilegesRequiredNames},
+	"UI": {"User Interaction", userInteractionNames},
+	"S":  {"Scope", scopeNames},
+	"C":  {"Confidentiality", impactNames},
+	"I":  {"Integrity", impactNames},
+	"A":  {"Availability", impactNames},
+	// temporal metrics
+	"E": {"Exploit Code Maturity", map[string]string{
+		"H": "High", "F": "Functional", "P": "Proof-of-Concept", "U": "Unproven", "X": "Not Defined",
+	}},
+	"RL": {"Remediation Level", map[string]string{
+		"U": "Unavailable", "W": "Workaround", "T": "Temporary Fix", "O": "Official Fix", "X": "Not Defined",
+	}},
+	"RC": {"Report Confidence", map[string]string{
+		"C": "Confirmed", "R": "Reasonable", "U": "Unknown", "X": "Not Defined",
+	}},
+	// environmental metrics
+	"CR":  {"Confidentiality Requirement", requirementNames},
+	"IR":  {"Integrity Requirement", requirementNames},
+	"AR":  {"Availability Requirement", requirementNames},
+	"MAV": {"Modified Attack Vector", attackVectorNames},
+	"MAC": {"Modified Attack Complexity", attackComplexityNames},
+	"MPR": {"Modified Privileges Required", privilegesRequiredNames},
+	"MUI": {"Modified User Interaction", userInteractionNames},
+	"MS":  {"Modified Scope", scopeNames},
+	"MC":  {"Modified Confidentiality", impactNames},
+	"MI":  {"Modified Integrity", impactNames},
+	"MA":  {"Modified Availability", impactNames},
+}
@@ -0,0 +1,140 @@
+package cvssv3
+
+import (
+	"testing"
+
+	"github.com/seal-io/meta-api/cvss/compatible"
+)
+
+func TestVector_Metrics(t *testing.T) {
+	type output struct {
+		value     string
+		valueName string
+		weight    float64
+	}
+	var testCases = []struct {
+		given    string
+		metric   string
+		expected output
+	}{
+		{
+			given:  "CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:H/I:H/A:H",
+			metric: "PR",
+			expected: output{
+				value:     "L",
+				valueName: "Low",
+				weight:    0.68,
+			},
+		},
+		{
+			given:  "CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:H/I:H/A:H",
+			metric: "S",
+			expected: output{
+				value:     "C",
+				valueName: "Changed",
+				weight:    1.08,
+			},
+		},
+		{
+			given:  "CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:H/I:H/A:H",
+			metric: "RC",
+			expected: output{
+				value:     "X",
+				valueName: "Not Defined",
+				weight:    1,
+			},
+		},
+		{
+			given:  "CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:H/I:H/A:H/E:P/MAV:L",
+			metric: "E",
+			expected: output{
+				value:     "P",
+				valueName: "Proof-of-Concept",
+				weight:    0.94,
+			},
+		},
+		{
+			given:  "CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:H/I:H/A:H/E:P/MAV:L",
+			metric: "MAV",
+			expected: output{
+				value:     "L",
+				valueName: "Local",
+				weight:    0.55,
+			},
+		},
+		{
+			given:  "CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:H/I:H/A:H/E:P/MAV:L",
+			metric: "MC",
+			expected: output{
+				value:     "X",
+				valueName: "Not Defined",
+				weight:    0.56,
+			},
+		},
+	}
+	for _, c := range testCases {
+		var v = ShouldParse(c.given)
+		if len(v.Metrics()) != 22 {
+			t.Errorf("metrics of %s should be 22, but got %d", c.given, len(v.Metrics()))
+		}
+		var m, ok = compatible.GetMetric(v, c.metric)
+		if !ok {
+			t.Errorf("metric %s of %s is not found", c.metric, c.given)
+			continue
+		}
+		var actual = output{
+			value:     m.Value,
+			valueName: m.ValueName,
+			weight:    m.Weight,
+		}
+		if actual != c.expected {
+			t.Errorf("metric %s of %s == %#v, but got %#v", c.metric, c.given, c.expected, actual)
+		}
+	}
+}
+
+func TestVector_Explain(t *testing.T) {
+	var testCases = []struct {
+		given    string
+		expected string
+	}{
+		{
+			given: "CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:H/I:H/A:H/E:P/CR:L/MS:U",
+			expected: `ISS = 1 - [(1 - C) × (1 - I) × (1 - A)] = 1 - [(1 - 0.56) × (1 - 0.56) × (1 - 0.56)] = 0.914816
+Impact = 7.52 × (ISS - 0.029) - 3.25 × (ISS - 0.02)^15 = 7.52 × (0.914816 - 0.029) - 3.25 × (0.914816 - 0.02)^15 = 6.04773
+Exploitability = 8.22 × AV × AC × PR × UI = 8.22 × 0.85 × 0.77 × 0.68 × 0.85 = 3.109634
+Base Score = Roundup(Minimum(1.08 × (Impact + Exploitability), 10)) = Roundup(Minimum(1.08 × (6.04773 + 3.109634), 10)) = 9.9
+Temporal Score = Roundup(BaseScore × E × RL × RC) = Roundup(9.9 × 0.94 × 1 × 1) = 9.4
+MISS = Minimum(1 - [(1 - CR × MC) × (1 - IR × MI) × (1 - AR × MA)], 0.915) = Minimum(1 - [(1 - 0.5 × 0.56) × (1 - 1 × 0.56) × (1 - 1 × 0.56)], 0.915) = 0.860608
+Modified Impact = 6.42 × MISS = 6.42 × 0.860608 = 5.525103
+Modified Exploitability = 8.22 × MAV × MAC × MPR × MUI = 8.22 × 0.85 × 0.77 × 0.68 × 0.85 = 3.109634
+Environmental Score = Roundup(Roundup(Minimum(ModifiedImpact + ModifiedExploitability, 10)) × E × RL × RC) = Roundup(Roundup(Minimum(5.525103 + 3.109634, 10)) × 0.94 × 1 × 1) = 8.2`,
+		},
+	}
+	for _, c := range testCases {
+		var actual = ShouldParse(c.given).Explain().String()
+		if actual != c.expected {
+			t.Errorf("explanation of %s == \n%s\n, but got \n%s", c.given, c.expected, actual)
+		}
+	}
+
+	// the explained scores must be the same as the calculated scores.
+	for _, s := range []string{
+		"CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H",
+		"CVSS:3.0/AV:N/AC:L/PR:L/UI:R/S:C/C:L/I:L/A:N/E:F/RL:O/RC:C/MS:C/MC:H",
+		"CVSS:3.1/AV:L/AC:H/PR:H/UI:R/S:U/C:N/I:N/A:N/MC:L",
+		"CVSS:3.1/AV:P/AC:H/PR:L/UI:N/S:C/C:H/I:L/A:L/E:U/RL:W/RC:R/CR:H/IR:M/AR:L/MAV:A/MAC:L/MPR:H/MUI:R/MS:C/MC:H/MI:H/MA:H",
+	} {
+		var v = ShouldParse(s)
+		var expected = map[string]float64{
+			"Base Score":          v.BaseScore(),
+			"Temporal Score":      v.TemporalScore(),
+			"Environmental Score": v.EnvironmentalScore(),
+		}
+		for _, st := range v.Explain().Steps {
+			if e, ok := expected[st.Name]; ok && e != st.Value {
+				t.Errorf("explained %s of %s == %v, but got %v", st.Name, s, e, st.Value)
+			}
+		}
+	}
+}
@@ -162,6 +162,42 @@ func Parse(s string) (Vector, error) {
 	return v, nil
 }
 
+// ParseLenient likes Parse but normalizes the vector string before parsing,
+// e.g. the vector enclosed in parentheses, the lowercase metrics, the duplicated metrics,
+// the unordered metrics or the missing CVSS:3.x prefix, which is regarded as CVSS:3.1,
+// and returns a warning for each fix-up.
+func ParseLenient(s string) (Vector, []compatible.ParseWarning, error) {
+	var parts, ws, err = compatible.NormalizeMetrics(s, metricOrder)
+	if err != nil {
+		return Vector{}, nil, fmt.Errorf("error normalizing CVSS(V3) vector: %s: %w", s, err)
+	}
+	if len(parts) != 0 && !strings.HasPrefix(parts[0], "CVSS:") {
+		parts = append([]string{"CVSS:" + string(Version31)}, parts...)
+		ws = append(ws, compatible.ParseWarning{
+			Kind:    compatible.ParseWarningMissingPrefix,
+			Metric:  "CVSS",
+			Message: "added the missing prefix 'CVSS:" + string(Version31) + "'",
+		})
+	}
+	var v Vector
+	v, err = Parse(strings.Join(parts, "/"))
+	if err != nil {
+		return Vector{}, nil, err
+	}
+	return v, ws, nil
+}
+
+// metricOrder holds the abbreviated metric names in order of the vector string.
+var metricOrder = []string{
+	"CVSS",
+	// base metrics
+	"AV", "AC", "PR", "UI", "S", "C", "I", "A",
+	// temporal metrics
+	"E", "RL", "RC",
+	// environmental metrics
+	"CR", "IR", "AR", "MAV", "MAC", "MPR", "MUI", "MS", "MC", "MI", "MA",
+}
+
 // Vector holds the metrics vector of CVSS(V3).
 type Vector struct {
 	Version
@@ -668,6 +704,10 @@ func (in AttackVector) isDefined() bool {
 	return true
 }
 
+func (in AttackVector) isValid() bool {
+	return in == AttackVectorNotDefined || in.isDefined()
+}
+
 func (in AttackComplexity) isDefined() bool {
 	switch in {
 	default:
@@ -678,6 +718,10 @@ func (in AttackComplexity) isDefined() bool {
 	return true
 }
 
+func (in AttackComplexity) isValid() bool {
+	return in == AttackComplexityNotDefined || in.isDefined()
+}
+
 func (in PrivilegesRequired) isDefined() bool {
 	switch in {
 	default:
@@ -689,6 +733,10 @@ func (in PrivilegesRequired) isDefined() bool {
 	return true
 }
 
+func (in PrivilegesRequired) isValid() bool {
+	return in == PrivilegesRequiredNotDefined || in.isDefined()
+}
+
 func (in UserInteraction) isDefined() bool {
 	switch in {
 	default:
@@ -699,6 +747,10 @@ func (in UserInteraction) isDefined() bool {
 	return true
 }
 
+func (in UserInteraction) isValid() bool {
+	return in == UserInteractionNotDefined || in.isDefined()
+}
+
 func (in Scope) isDefined() bool {
 	switch in {
 	default:
@@ -709,6 +761,10 @@ func (in Scope) isDefined() bool {
 	return true
 }
 
+func (in Scope) isValid() bool {
+	return in == ScopeNotDefined || in.isDefined()
+}
+
 func (in ConfidentialityImpact) isDefined() bool {
 	switch in {
 	default:
@@ -720,6 +776,10 @@ func (in ConfidentialityImpact) isDefined() bool {
 	return true
 }
 
+func (in ConfidentialityImpact) isValid() bool {
+	return in == ConfidentialityImpactNotDefined || in.isDefined()
+}
+
 func (in IntegrityImpact) isDefined() bool {
 	switch in {
 	default:
@@ -731,6 +791,10 @@ func (in IntegrityImpact) isDefined() bool {
 	return true
 }
 
+func (in IntegrityImpact) isValid() bool {
+	return in == IntegrityImpactNotDefined || in.isDefined()
+}
+
 func (in AvailabilityImpact) isDefined() bool {
 	switch in {
 	default:
@@ -742,6 +806,10 @@ func (in AvailabilityImpact) isDefined() bool {
 	return true
 }
 
+func (in AvailabilityImpact) isValid() bool {
+	return in == AvailabilityImpactNotDefined || in.isDefined()
+}
+
 func (in BasicMetrics) getAttackVector() float64 {
 	switch in.AttackVector {
 	default:
@@ -870,6 +938,44 @@ const (
 	ReportConfidenceConfirmed  ReportConfidence = "C"
 )
 
+func (in ExploitCodeMaturity) isValid() bool {
+	switch in {
+	default:
+		return false
+	case ExploitCodeMaturityNotDefined:
+	case ExploitCodeMaturityUnproven:
+	case ExploitCodeMaturityProofOfConcept:
+	case ExploitCodeMaturityFunctional:
+	case ExploitCodeMaturityHigh:
+	}
+	return true
+}
+
+func (in RemediationLevel) isValid() bool {
+	switch in {
+	default:
+		return false
+	case RemediationLevelNotDefined:
+	case RemediationLevelOfficialFix:
+	case RemediationLevelTemporaryFix:
+	case RemediationLevelWorkaround:
+	case RemediationLevelUnavailable:
+	}
+	return true
+}
+
+func (in ReportConfidence) isValid() bool {
+	switch in {
+	default:
+		return false
+	case ReportConfidenceNotDefined:
+	case ReportConfidenceUnknown:
+	case ReportConfidenceReasonable:
+	case ReportConfidenceConfirmed:
+	}
+	return true
+}
+
 func (in TemporalMetrics) getExploitCodeMaturity() float64 {
 	switch in.ExploitCodeMaturity {
 	default:
@@ -948,6 +1054,18 @@ const (
 	SecurityRequirementHigh       SecurityRequirement = "H"
 )
 
+func (in SecurityRequirement) isValid() bool {
+	switch in {
+	default:
+		return false
+	case SecurityRequirementNotDefined:
+	case SecurityRequirementLow:
+	case SecurityRequirementMedium:
+	case SecurityRequirementHigh:
+	}
+	return true
+}
+
 func (in EnvironmentalMetrics) getConfidentialityRequirement() float64 {
 	switch in.ConfidentialityRequirement {
 	default:
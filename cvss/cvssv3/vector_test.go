@@ -195,6 +195,80 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParseLenient(t *testing.T) {
+	type output struct {
+		vector   string
+		warnings []string
+		err      bool
+	}
+	var testCases = []struct {
+		given    string
+		expected output
+	}{
+		{
+			given: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+			expected: output{
+				vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+			},
+		},
+		{
+			given: "(cvss:3.0/av:n/ac:l/pr:n/ui:n/s:u/c:h/i:h/a:h)",
+			expected: output{
+				vector: "CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+				warnings: []string{
+					"parenthesized", "lowercase", "lowercase", "lowercase", "lowercase", "lowercase",
+					"lowercase", "lowercase", "lowercase", "lowercase",
+				},
+			},
+		},
+		{
+			given: "AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/E:P/AV:N",
+			expected: output{
+				vector:   "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/E:P",
+				warnings: []string{"duplicated", "missing-prefix"},
+			},
+		},
+		{
+			given: "CVSS:3.1/E:P/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/",
+			expected: output{
+				vector:   "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/E:P",
+				warnings: []string{"trailing-slash", "unordered"},
+			},
+		},
+		{
+			given: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/AV:L",
+			expected: output{
+				err: true,
+			},
+		},
+		{
+			given: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/Au:N",
+			expected: output{
+				err: true,
+			},
+		},
+		{
+			given: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H",
+			expected: output{
+				err: true,
+			},
+		},
+	}
+	for _, c := range testCases {
+		var v, ws, err = ParseLenient(c.given)
+		var actual = output{err: err != nil}
+		if err == nil {
+			actual.vector = v.String()
+		}
+		for i := range ws {
+			actual.warnings = append(actual.warnings, string(ws[i].Kind))
+		}
+		if fmt.Sprint(actual) != fmt.Sprint(c.expected) {
+			t.Errorf("lenient parse %s == %v, but got %v", c.given, c.expected, actual)
+		}
+	}
+}
+
 func TestScore(t *testing.T) {
 	type output struct {
 		impactScore           float64
@@ -0,0 +1,311 @@
+package cvssv4
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MetricError holds the error of an undefined or invalid metric value.
+type MetricError struct {
+	// Metric is the abbreviated metric name, e.g. AV.
+	Metric string
+	// Value is the rejected metric value, it is empty if the metric is not set.
+	Value string
+}
+
+// Error implements error.
+func (e MetricError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("undefined metric '%s' in CVSS(V4) vector", e.Metric)
+	}
+	return fmt.Sprintf("invalid value '%s' of metric '%s' in CVSS(V4) vector", e.Value, e.Metric)
+}
+
+// MetricErrors holds the errors of the metrics in order of the vector string.
+type MetricErrors []MetricError
+
+// Error implements error.
+func (e MetricErrors) Error() string {
+	var sb strings.Builder
+	for i := range e {
+		if i > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(e[i].Error())
+	}
+	return sb.String()
+}
+
+// Metrics returns the abbreviated names of the offending metrics.
+func (e MetricErrors) Metrics() []string {
+	var r = make([]string, 0, len(e))
+	for i := range e {
+		r = append(r, e[i].Metric)
+	}
+	return r
+}
+
+// Builder composes a CVSS(V4) vector metric by metric,
+// the mandatory base metrics must be set before building,
+// the optional metrics are not defined if not set.
+type Builder struct {
+	v Vector
+}
+
+// NewBuilder returns a Builder of CVSS(V4.0) vector.
+func NewBuilder() *Builder {
+	var v = DefaultVector()
+	v.BasicMetrics = BasicMetrics{}
+	return &Builder{v: v}
+}
+
+// AttackVector sets the AV metric.
+func (b *Builder) AttackVector(v AttackVector) *Builder {
+	b.v.AttackVector = v
+	return b
+}
+
+// AttackComplexity sets the AC metric.
+func (b *Builder) AttackComplexity(v AttackComplexity) *Builder {
+	b.v.AttackComplexity = v
+	return b
+}
+
+// AttackRequirements sets the AT metric.
+func (b *Builder) AttackRequirements(v AttackRequirements) *Builder {
+	b.v.AttackRequirements = v
+	return b
+}
+
+// PrivilegesRequired sets the PR metric.
+func (b *Builder) PrivilegesRequired(v PrivilegesRequired) *Builder {
+	b.v.PrivilegesRequired = v
+	return b
+}
+
+// UserInteraction sets the UI metric.
+func (b *Builder) UserInteraction(v UserInteraction) *Builder {
+	b.v.UserInteraction = v
+	return b
+}
+
+// VulnerableSystemConfidentiality sets the VC metric.
+func (b *Builder) VulnerableSystemConfidentiality(v VulnerableSystemImpact) *Builder {
+	b.v.VulnerableSystemConfidentiality = v
+	return b
+}
+
+// VulnerableSystemIntegrity sets the VI metric.
+func (b *Builder) VulnerableSystemIntegrity(v VulnerableSystemImpact) *Builder {
+	b.v.VulnerableSystemIntegrity = v
+	return b
+}
+
+// VulnerableSystemAvailability sets the VA metric.
+func (b *Builder) VulnerableSystemAvailability(v VulnerableSystemImpact) *Builder {
+	b.v.VulnerableSystemAvailability = v
+	return b
+}
+
+// SubsequentSystemConfidentiality sets the SC metric.
+func (b *Builder) SubsequentSystemConfidentiality(v SubsequentSystemImpact) *Builder {
+	b.v.SubsequentSystemConfidentiality = v
+	return b
+}
+
+// SubsequentSystemIntegrity sets the SI metric.
+func (b *Builder) SubsequentSystemIntegrity(v SubsequentSystemImpact) *Builder {
+	b.v.SubsequentSystemIntegrity = v
+	return b
+}
+
+// SubsequentSystemAvailability sets the SA metric.
+func (b *Builder) SubsequentSystemAvailability(v SubsequentSystemImpact) *Builder {
+	b.v.SubsequentSystemAvailability = v
+	return b
+}
+
+// ExploitMaturity sets the E metric.
+func (b *Builder) ExploitMaturity(v ExploitMaturity) *Builder {
+	b.v.ExploitMaturity = v
+	return b
+}
+
+// ConfidentialityRequirement sets the CR metric.
+func (b *Builder) ConfidentialityRequirement(v SecurityRequirement) *Builder {
+	b.v.ConfidentialityRequirement = v
+	return b
+}
+
+// IntegrityRequirement sets the IR metric.
+func (b *Builder) IntegrityRequirement(v SecurityRequirement) *Builder {
+	b.v.IntegrityRequirement = v
+	return b
+}
+
+// AvailabilityRequirement sets the AR metric.
+func (b *Builder) AvailabilityRequirement(v SecurityRequirement) *Builder {
+	b.v.AvailabilityRequirement = v
+	return b
+}
+
+// ModifiedAttackVector sets the MAV metric.
+func (b *Builder) ModifiedAttackVector(v AttackVector) *Builder {
+	b.v.ModifiedAttackVector = v
+	return b
+}
+
+// ModifiedAttackComplexity sets the MAC metric.
+func (b *Builder) ModifiedAttackComplexity(v AttackComplexity) *Builder {
+	b.v.ModifiedAttackComplexity = v
+	return b
+}
+
+// ModifiedAttackRequirements sets the MAT metric.
+func (b *Builder) ModifiedAttackRequirements(v AttackRequirements) *Builder {
+	b.v.ModifiedAttackRequirements = v
+	return b
+}
+
+// ModifiedPrivilegesRequired sets the MPR metric.
+func (b *Builder) ModifiedPrivilegesRequired(v PrivilegesRequired) *Builder {
+	b.v.ModifiedPrivilegesRequired = v
+	return b
+}
+
+// ModifiedUserInteraction sets the MUI metric.
+func (b *Builder) ModifiedUserInteraction(v UserInteraction) *Builder {
+	b.v.ModifiedUserInteraction = v
+	return b
+}
+
+// ModifiedVulnerableSystemConfidentiality sets the MVC metric.
+func (b *Builder) ModifiedVulnerableSystemConfidentiality(v VulnerableSystemImpact) *Builder {
+	b.v.ModifiedVulnerableSystemConfidentiality = v
+	return b
+}
+
+// ModifiedVulnerableSystemIntegrity sets the MVI metric.
+func (b *Builder) ModifiedVulnerableSystemIntegrity(v VulnerableSystemImpact) *Builder {
+	b.v.ModifiedVulnerableSystemIntegrity = v
+	return b
+}
+
+// ModifiedVulnerableSystemAvailability sets the MVA metric.
+func (b *Builder) ModifiedVulnerableSystemAvailability(v VulnerableSystemImpact) *Builder {
+	b.v.ModifiedVulnerableSystemAvailability = v
+	return b
+}
+
+// ModifiedSubsequentSystemConfidentiality sets the MSC metric.
+func (b *Builder) ModifiedSubsequentSystemConfidentiality(v SubsequentSystemImpact) *Builder {
+	b.v.ModifiedSubsequentSystemConfidentiality = v
+	return b
+}
+
+// ModifiedSubsequentSystemIntegrity sets the MSI metric.
+func (b *Builder) ModifiedSubsequentSystemIntegrity(v SubsequentSystemImpact) *Builder {
+	b.v.ModifiedSubsequentSystemIntegrity = v
+	return b
+}
+
+// ModifiedSubsequentSystemAvailability sets the MSA metric.
+func (b *Builder) ModifiedSubsequentSystemAvailability(v SubsequentSystemImpact) *Builder {
+	b.v.ModifiedSubsequentSystemAvailability = v
+	return b
+}
+
+// Safety sets the S metric.
+func (b *Builder) Safety(v Safety) *Builder {
+	b.v.Safety = v
+	return b
+}
+
+// Automatable sets the AU metric.
+func (b *Builder) Automatable(v Automatable) *Builder {
+	b.v.Automatable = v
+	return b
+}
+
+// Recovery sets the R metric.
+func (b *Builder) Recovery(v Recovery) *Builder {
+	b.v.Recovery = v
+	return b
+}
+
+// ValueDensity sets the V metric.
+func (b *Builder) ValueDensity(v ValueDensity) *Builder {
+	b.v.ValueDensity = v
+	return b
+}
+
+// VulnerabilityResponseEffort sets the RE metric.
+func (b *Builder) VulnerabilityResponseEffort(v VulnerabilityResponseEffort) *Builder {
+	b.v.VulnerabilityResponseEffort = v
+	return b
+}
+
+// ProviderUrgency sets the U metric.
+func (b *Builder) ProviderUrgency(v ProviderUrgency) *Builder {
+	b.v.ProviderUrgency = v
+	return b
+}
+
+// Build returns the composed Vector,
+// or returns MetricErrors if any metric is undefined or invalid.
+func (b *Builder) Build() (Vector, error) {
+	var v = b.v
+	var errs MetricErrors
+	for _, m := range []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		// base metrics
+		{"AV", string(v.AttackVector), v.AttackVector.isDefined()},
+		{"AC", string(v.AttackComplexity), v.AttackComplexity.isDefined()},
+		{"AT", string(v.AttackRequirements), v.AttackRequirements.isDefined()},
+		{"PR", string(v.PrivilegesRequired), v.PrivilegesRequired.isDefined()},
+		{"UI", string(v.UserInteraction), v.UserInteraction.isDefined()},
+		{"VC", string(v.VulnerableSystemConfidentiality), v.VulnerableSystemConfidentiality.isDefined()},
+		{"VI", string(v.VulnerableSystemIntegrity), v.VulnerableSystemIntegrity.isDefined()},
+		{"VA", string(v.VulnerableSystemAvailability), v.VulnerableSystemAvailability.isDefined()},
+		{"SC", string(v.SubsequentSystemConfidentiality), v.SubsequentSystemConfidentiality.isDefined()},
+		{"SI", string(v.SubsequentSystemIntegrity), v.SubsequentSystemIntegrity.isDefined()},
+		{"SA", string(v.SubsequentSystemAvailability), v.SubsequentSystemAvailability.isDefined()},
+		// threat metrics
+		{"E", string(v.ExploitMaturity), v.ExploitMaturity.isValid()},
+		// environmental metrics
+		{"CR", string(v.ConfidentialityRequirement), v.ConfidentialityRequirement.isValid()},
+		{"IR", string(v.IntegrityRequirement), v.IntegrityRequirement.isValid()},
+		{"AR", string(v.AvailabilityRequirement), v.AvailabilityRequirement.isValid()},
+		{"MAV", string(v.ModifiedAttackVector), v.ModifiedAttackVector.isValid()},
+		{"MAC", string(v.ModifiedAttackComplexity), v.ModifiedAttackComplexity.isValid()},
+		{"MAT", string(v.ModifiedAttackRequirements), v.ModifiedAttackRequirements.isValid()},
+		{"MPR", string(v.ModifiedPrivilegesRequired), v.ModifiedPrivilegesRequired.isValid()},
+		{"MUI", string(v.ModifiedUserInteraction), v.ModifiedUserInteraction.isValid()},
+		{"MVC", string(v.ModifiedVulnerableSystemConfidentiality), v.ModifiedVulnerableSystemConfidentiality.isValid()},
+		{"MVI", string(v.ModifiedVulnerableSystemIntegrity), v.ModifiedVulnerableSystemIntegrity.isValid()},
+		{"MVA", string(v.ModifiedVulnerableSystemAvailability), v.ModifiedVulnerableSystemAvailability.isValid()},
+		{"MSC", string(v.ModifiedSubsequentSystemConfidentiality), v.ModifiedSubsequentSystemConfidentiality.isValid()},
+		{"MSI", string(v.ModifiedSubsequentSystemIntegrity), v.ModifiedSubsequentSystemIntegrity.isValid() ||
+			v.ModifiedSubsequentSystemIntegrity == SubsequentSystemImpactSafety},
+		{"MSA", string(v.ModifiedSubsequentSystemAvailability), v.ModifiedSubsequentSystemAvailability.isValid() ||
+			v.ModifiedSubsequentSystemAvailability == SubsequentSystemImpactSafety},
+		// supplemental metrics
+		{"S", string(v.Safety), v.Safety.isValid()},
+		{"AU", string(v.Automatable), v.Automatable.isValid()},
+		{"R", string(v.Recovery), v.Recovery.isValid()},
+		{"V", string(v.ValueDensity), v.ValueDensity.isValid()},
+		{"RE", string(v.VulnerabilityResponseEffort), v.VulnerabilityResponseEffort.isValid()},
+		{"U", string(v.ProviderUrgency), v.ProviderUrgency.isValid()},
+	} {
+		if !m.valid {
+			errs = append(errs, MetricError{Metric: m.name, Value: m.value})
+		}
+	}
+	if len(errs) != 0 {
+		return Vector{}, errs
+	}
+	return v, nil
+}
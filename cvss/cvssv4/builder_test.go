@@ -0,0 +1,100 @@
+package cvssv4
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBuilder_Build(t *testing.T) {
+	type output struct {
+		vector  string
+		metrics []string
+		err     string
+	}
+	var base = func() *Builder {
+		return NewBuilder().
+			AttackVector(AttackVectorNetwork).
+			AttackComplexity(AttackComplexityLow).
+			AttackRequirements(AttackRequirementsNone).
+			PrivilegesRequired(PrivilegesRequiredNone).
+			UserInteraction(UserInteractionNone).
+			VulnerableSystemConfidentiality(VulnerableSystemImpactHigh).
+			VulnerableSystemIntegrity(VulnerableSystemImpactHigh).
+			VulnerableSystemAvailability(VulnerableSystemImpactHigh).
+			SubsequentSystemConfidentiality(SubsequentSystemImpactNone).
+			SubsequentSystemIntegrity(SubsequentSystemImpactNone).
+			SubsequentSystemAvailability(SubsequentSystemImpactNone)
+	}
+	var testCases = []struct {
+		name     string
+		given    *Builder
+		expected output
+	}{
+		{
+			name:  "base metrics",
+			given: base(),
+			expected: output{
+				vector: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+			},
+		},
+		{
+			name: "optional metrics",
+			given: base().
+				ExploitMaturity(ExploitMaturityProofOfConcept).
+				ModifiedSubsequentSystemIntegrity(SubsequentSystemImpactSafety).
+				Automatable(AutomatableYes),
+			expected: output{
+				vector: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/E:P/MSI:S/AU:Y",
+			},
+		},
+		{
+			name: "invalid metrics",
+			given: base().
+				SubsequentSystemConfidentiality(SubsequentSystemImpactSafety).
+				ModifiedSubsequentSystemConfidentiality(SubsequentSystemImpactSafety).
+				ModifiedSubsequentSystemAvailability(SubsequentSystemImpactSafety),
+			expected: output{
+				metrics: []string{"SC", "MSC"},
+				err: "invalid value 'S' of metric 'SC' in CVSS(V4) vector; " +
+					"invalid value 'S' of metric 'MSC' in CVSS(V4) vector",
+			},
+		},
+		{
+			name: "unset mandatory metrics",
+			given: NewBuilder().
+				AttackVector(AttackVectorNetwork),
+			expected: output{
+				metrics: []string{"AC", "AT", "PR", "UI", "VC", "VI", "VA", "SC", "SI", "SA"},
+				err: "undefined metric 'AC' in CVSS(V4) vector; " +
+					"undefined metric 'AT' in CVSS(V4) vector; " +
+					"undefined metric 'PR' in CVSS(V4) vector; " +
+					"undefined metric 'UI' in CVSS(V4) vector; " +
+					"undefined metric 'VC' in CVSS(V4) vector; " +
+					"undefined metric 'VI' in CVSS(V4) vector; " +
+					"undefined metric 'VA' in CVSS(V4) vector; " +
+					"undefined metric 'SC' in CVSS(V4) vector; " +
+					"undefined metric 'SI' in CVSS(V4) vector; " +
+					"undefined metric 'SA' in CVSS(V4) vector",
+			},
+		},
+	}
+	for _, c := range testCases {
+		var v, err = c.given.Build()
+		var actual output
+		if err != nil {
+			var me MetricErrors
+			if !errors.As(err, &me) {
+				t.Errorf("%s: error should be MetricErrors, but got %T", c.name, err)
+				continue
+			}
+			actual.metrics = me.Metrics()
+			actual.err = err.Error()
+		} else {
+			actual.vector = v.String()
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s: build == %#v, but got %#v", c.name, c.expected, actual)
+		}
+	}
+}
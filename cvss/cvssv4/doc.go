@@ -0,0 +1,3 @@
+// Package cvssv4 provides a toolbox of the CVSS(V4) vector calculating, according to
+// https://www.first.org/cvss/v4.0/specification-document.
+package cvssv4
@@ -0,0 +1,223 @@
+package cvssv4
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/seal-io/meta-api/cvss/compatible"
+)
+
+// Metrics returns the metrics of this CVSS(V4) vector in order of the vector string,
+// including the not defined optional metrics,
+// CVSS(V4) metrics are not weighted, so the weight of each metric is always 0.
+func (in Vector) Metrics() []compatible.Metric {
+	var c = DefaultVector().Override(in)
+	return []compatible.Metric{
+		// base metrics
+		newMetric(compatible.MetricGroupBase, "AV", string(c.AttackVector)),
+		newMetric(compatible.MetricGroupBase, "AC", string(c.AttackComplexity)),
+		newMetric(compatible.MetricGroupBase, "AT", string(c.AttackRequirements)),
+		newMetric(compatible.MetricGroupBase, "PR", string(c.PrivilegesRequired)),
+		newMetric(compatible.MetricGroupBase, "UI", string(c.UserInteraction)),
+		newMetric(compatible.MetricGroupBase, "VC", string(c.VulnerableSystemConfidentiality)),
+		newMetric(compatible.MetricGroupBase, "VI", string(c.VulnerableSystemIntegrity)),
+		newMetric(compatible.MetricGroupBase, "VA", string(c.VulnerableSystemAvailability)),
+		newMetric(compatible.MetricGroupBase, "SC", string(c.SubsequentSystemConfidentiality)),
+		newMetric(compatible.MetricGroupBase, "SI", string(c.SubsequentSystemIntegrity)),
+		newMetric(compatible.MetricGroupBase, "SA", string(c.SubsequentSystemAvailability)),
+		// threat metrics
+		newMetric(compatible.MetricGroupThreat, "E", string(c.ExploitMaturity)),
+		// environmental metrics
+		newMetric(compatible.MetricGroupEnvironmental, "CR", string(c.ConfidentialityRequirement)),
+		newMetric(compatible.MetricGroupEnvironmental, "IR", string(c.IntegrityRequirement)),
+		newMetric(compatible.MetricGroupEnvironmental, "AR", string(c.AvailabilityRequirement)),
+		newMetric(compatible.MetricGroupEnvironmental, "MAV", string(c.ModifiedAttackVector)),
+		newMetric(compatible.MetricGroupEnvironmental, "MAC", string(c.ModifiedAttackComplexity)),
+		newMetric(compatible.MetricGroupEnvironmental, "MAT", string(c.ModifiedAttackRequirements)),
+		newMetric(compatible.MetricGroupEnvironmental, "MPR", string(c.ModifiedPrivilegesRequired)),
+		newMetric(compatible.MetricGroupEnvironmental, "MUI", string(c.ModifiedUserInteraction)),
+		newMetric(compatible.MetricGroupEnvironmental, "MVC", string(c.ModifiedVulnerableSystemConfidentiality)),
+		newMetric(compatible.MetricGroupEnvironmental, "MVI", string(c.ModifiedVulnerableSystemIntegrity)),
+		newMetric(compatible.MetricGroupEnvironmental, "MVA", string(c.ModifiedVulnerableSystemAvailability)),
+		newMetric(compatible.MetricGroupEnvironmental, "MSC", string(c.ModifiedSubsequentSystemConfidentiality)),
+		newMetric(compatible.MetricGroupEnvironmental, "MSI", string(c.ModifiedSubsequentSystemIntegrity)),
+		newMetric(compatible.MetricGroupEnvironmental, "MSA", string(c.ModifiedSubsequentSystemAvailability)),
+		// supplemental metrics
+		newMetric(compatible.MetricGroupSupplemental, "S", string(c.Safety)),
+		newMetric(compatible.MetricGroupSupplemental, "AU", string(c.Automatable)),
+		newMetric(compatible.MetricGroupSupplemental, "R", string(c.Recovery)),
+		newMetric(compatible.MetricGroupSupplemental, "V", string(c.ValueDensity)),
+		newMetric(compatible.MetricGroupSupplemental, "RE", string(c.VulnerabilityResponseEffort)),
+		newMetric(compatible.MetricGroupSupplemental, "U", string(c.ProviderUrgency)),
+	}
+}
+
+// Explain returns the derivation of the CVSS-BTE score of this CVSS(V4) vector,
+// the CVSS-B score and the CVSS-BT score are appended as the last steps,
+// according to https://www.first.org/cvss/v4.0/specification-document#CVSS-v4-0-Scoring-using-MacroVectors-and-Interpolation.
+func (in Vector) Explain() compatible.Explanation {
+	var c = DefaultVector().Override(in)
+	var e = compatible.Explanation{Version: c.GetVersion()}
+	var step = func(g compatible.MetricGroup, n, f, x string, v float64) {
+		e.Steps = append(e.Steps, compatible.Step{Group: g, Name: n, Formula: f, Expression: x, Value: v})
+	}
+
+	var r = c.scoring()
+
+	// equivalence classes
+	var eqs = []struct {
+		group   compatible.MetricGroup
+		metrics []string
+	}{
+		{compatible.MetricGroupBase, []string{"AV", "PR", "UI"}},
+		{compatible.MetricGroupBase, []string{"AC", "AT"}},
+		{compatible.MetricGroupBase, []string{"VC", "VI", "VA"}},
+		{compatible.MetricGroupBase, []string{"SC", "SI", "SA"}},
+		{compatible.MetricGroupThreat, []string{"E"}},
+		{compatible.MetricGroupEnvironmental, []string{"VC", "VI", "VA", "CR", "IR", "AR"}},
+	}
+	for i := range eqs {
+		var xs = make([]string, 0, len(eqs[i].metrics))
+		for _, mn := range eqs[i].metrics {
+			xs = append(xs, mn+":"+r.metrics[mn])
+		}
+		step(eqs[i].group, fmt.Sprintf("EQ%d", i+1),
+			strings.Join(eqs[i].metrics, ", "),
+			strings.Join(xs, "/"),
+			float64(r.macroVector[i]-'0'))
+	}
+
+	// interpolation
+	if r.value == 0 {
+		step(compatible.MetricGroupEnvironmental, "Environmental Score",
+			"0 if VC, VI, VA, SC, SI and SA are None",
+			"",
+			r.score)
+	} else {
+		step(compatible.MetricGroupEnvironmental, "MacroVector Score",
+			"Lookup(MacroVector)",
+			fmt.Sprintf("Lookup(%s)", r.macroVector),
+			r.value)
+		var names = [5]string{"EQ1", "EQ2", "EQ3 and EQ6", "EQ4", "EQ5"}
+		var existing int
+		for i := range r.lowers {
+			if !r.lowers[i].ok {
+				continue
+			}
+			existing++
+			var n = names[i] + " Proportional Distance"
+			if i == 4 {
+				step(compatible.MetricGroupEnvironmental, n,
+					"0",
+					"",
+					r.proportions[i])
+				continue
+			}
+			// NB: the severity distances and the depths are ten times of the reference implementation.
+			step(compatible.MetricGroupEnvironmental, n,
+				"(MacroVectorScore - LowerMacroVectorScore) × SeverityDistance / Depth",
+				fmt.Sprintf("(%s - %s) × %s / %s",
+					ftoa(r.value), ftoa(r.lowers[i].score),
+					ftoa(float64(r.distances[i])/10), ftoa(float64(r.depths[i])/10)),
+				r.proportions[i])
+		}
+		step(compatible.MetricGroupEnvironmental, "Mean Distance",
+			"Sum(ProportionalDistances) / Count(LowerMacroVectors)",
+			fmt.Sprintf("Sum(ProportionalDistances) / %d", existing),
+			r.mean)
+		step(compatible.MetricGroupEnvironmental, "Environmental Score",
+			"Round(MacroVectorScore - MeanDistance)",
+			fmt.Sprintf("Round(%s - %s)", ftoa(r.value), ftoa(r.mean)),
+			r.score)
+	}
+
+	// nomenclatures
+	step(compatible.MetricGroupBase, "Base Score",
+		"CVSS-B",
+		"",
+		c.BaseScore())
+	step(compatible.MetricGroupThreat, "Temporal Score",
+		"CVSS-BT",
+		"",
+		c.TemporalScore())
+
+	return e
+}
+
+func ftoa(f float64) string {
+	return compatible.FormatFloat(f)
+}
+
+func newMetric(g compatible.MetricGroup, mn, mv string) compatible.Metric {
+	var n = metricNames[mn]
+	return compatible.Metric{
+		Group:        g,
+		Abbreviation: mn,
+		Name:         n.name,
+		Value:        mv,
+		ValueName:    n.values[mv],
+	}
+}
+
+// metric value names shared by the base metrics and the modified base metrics.
+var (
+	attackVectorNames = map[string]string{
+		"N": "Network", "A": "Adjacent", "L": "Local", "P": "Physical", "X": "Not Defined",
+	}
+	attackComplexityNames   = map[string]string{"L": "Low", "H": "High", "X": "Not Defined"}
+	attackRequirementsNames = map[string]string{"N": "None", "P": "Present", "X": "Not Defined"}
+	privilegesRequiredNames = map[string]string{"N": "None", "L": "Low", "H": "High", "X": "Not Defined"}
+	userInteractionNames    = map[string]string{"N": "None", "P": "Passive", "A": "Active", "X": "Not Defined"}
+	impactNames             = map[string]string{"H": "High", "L": "Low", "N": "None", "X": "Not Defined"}
+	safetyImpactNames       = map[string]string{"S": "Safety", "H": "High", "L": "Low", "N": "Negligible", "X": "Not Defined"}
+	requirementNames        = map[string]string{"H": "High", "M": "Medium", "L": "Low", "X": "Not Defined"}
+)
+
+// metricNames holds the full names of the metrics and the metric values.
+var metricNames = map[string]struct {
+	name   string
+	values map[string]string
+}{
+	// base metrics
+	"AV": {"Attack Vector", attackVectorNames},
+	"AC": {"Attack Complexity", attackComplexityNames},
+	"AT": {"Attack Requirements", attackRequirementsNames},
+	"PR": {"Privileges Required", privilegesRequiredNames},
+	"UI": {"User Interaction", userInteractionNames},
+	"VC": {"Vulnerable System Confidentiality", impactNames},
+	"VI": {"Vulnerable System Integrity", impactNames},
+	"VA": {"Vulnerable System Availability", impactNames},
+	"SC": {"Subsequent System Confidentiality", impactNames},
+	"SI": {"Subsequent System Integrity", impactNames},
+	"SA": {"Subsequent System Availability", impactNames},
+	// threat metrics
+	"E": {"Exploit Maturity", map[string]string{
+		"A": "Attacked", "P": "POC", "U": "Unreported", "X": "Not Defined",
+	}},
+	// environmental metrics
+	"CR":  {"Confidentiality Requirement", requirementNames},
+	"IR":  {"Integrity Requirement", requirementNames},
+	"AR":  {"Availability Requirement", requirementNames},
+	"MAV": {"Modified Attack Vector", attackVectorNames},
+	"MAC": {"Modified Attack Complexity", attackComplexityNames},
+	"MAT": {"Modified Attack Requirements", attackRequirementsNames},
+	"MPR": {"Modified Privileges Required", privilegesRequiredNames},
+	"MUI": {"Modified User Interaction", userInteractionNames},
+	"MVC": {"Modified Vulnerable System Confidentiality", impactNames},
+	"MVI": {"Modified Vulnerable System Integrity", impactNames},
+	"MVA": {"Modified Vulnerable System Availability", impactNames},
+	"MSC": {"Modified Subsequent System Confidentiality", impactNames},
+	"MSI": {"Modified Subsequent System Integrity", safetyImpactNames},
+	"MSA": {"Modified Subsequent System Availability", safetyImpactNames},
+	// supplemental metrics
+	"S":  {"Safety", map[string]string{"N": "Negligible", "P": "Present", "X": "Not Defined"}},
+	"AU": {"Automatable", map[string]string{"N": "No", "Y": "Yes", "X": "Not Defined"}},
+	"R": {"Recovery", map[string]string{
+		"A": "Automatic", "U": "User", "I": "Irrecoverable", "X": "Not Defined",
+	}},
+	"V":  {"Value Density", map[string]string{"D": "Diffuse", "C": "Concentrated", "X": "Not Defined"}},
+	"RE": {"Vulnerability Response Effort", map[string]string{"L": "Low", "M": "Moderate", "H": "High", "X": "Not Defined"}},
+	"U": {"Provider Urgency", map[string]string{
+		"Clear": "Clear", "Green": "Green", "Amber": "Amber", "Red": "Red", "X": "Not Defined",
+	}},
+}
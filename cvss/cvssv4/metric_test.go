@@ -0,0 +1,119 @@
+package cvssv4
+
+import (
+	"testing"
+
+	"github.com/seal-io/meta-api/cvss/compatible"
+)
+
+func TestVector_Metrics(t *testing.T) {
+	type output struct {
+		group     compatible.MetricGroup
+		value     string
+		valueName string
+	}
+	var testCases = []struct {
+		given    string
+		metric   string
+		expected output
+	}{
+		{
+			given:  "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:P/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+			metric: "UI",
+			expected: output{
+				group:     compatible.MetricGroupBase,
+				value:     "P",
+				valueName: "Passive",
+			},
+		},
+		{
+			given:  "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:P/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+			metric: "E",
+			expected: output{
+				group:     compatible.MetricGroupThreat,
+				value:     "X",
+				valueName: "Not Defined",
+			},
+		},
+		{
+			given:  "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:P/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/MSI:S/U:Amber",
+			metric: "MSI",
+			expected: output{
+				group:     compatible.MetricGroupEnvironmental,
+				value:     "S",
+				valueName: "Safety",
+			},
+		},
+		{
+			given:  "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:P/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/MSI:S/U:Amber",
+			metric: "U",
+			expected: output{
+				group:     compatible.MetricGroupSupplemental,
+				value:     "Amber",
+				valueName: "Amber",
+			},
+		},
+	}
+	for _, c := range testCases {
+		var v = ShouldParse(c.given)
+		if len(v.Metrics()) != 32 {
+			t.Errorf("metrics of %s should be 32, but got %d", c.given, len(v.Metrics()))
+		}
+		var m, ok = compatible.GetMetric(v, c.metric)
+		if !ok {
+			t.Errorf("metric %s of %s is not found", c.metric, c.given)
+			continue
+		}
+		var actual = output{
+			group:     m.Group,
+			value:     m.Value,
+			valueName: m.ValueName,
+		}
+		if actual != c.expected || m.Weight != 0 {
+			t.Errorf("metric %s of %s == %#v, but got %#v", c.metric, c.given, c.expected, actual)
+		}
+	}
+}
+
+func TestVector_Explain(t *testing.T) {
+	var testCases = []struct {
+		given    string
+		expected string
+	}{
+		{
+			given: "CVSS:4.0/AV:L/AC:L/AT:N/PR:L/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/E:U",
+			expected: `EQ1 = AV, PR, UI = AV:L/PR:L/UI:N = 1
+EQ2 = AC, AT = AC:L/AT:N = 0
+EQ3 = VC, VI, VA = VC:H/VI:H/VA:H = 0
+EQ4 = SC, SI, SA = SC:N/SI:N/SA:N = 2
+EQ5 = E = E:U = 2
+EQ6 = VC, VI, VA, CR, IR, AR = VC:H/VI:H/VA:H/CR:H/IR:H/AR:H = 0
+MacroVector Score = Lookup(MacroVector) = Lookup(100220) = 6.3
+EQ1 Proportional Distance = (MacroVectorScore - LowerMacroVectorScore) × SeverityDistance / Depth = (6.3 - 4) × 0.2 / 0.4 = 1.15
+EQ2 Proportional Distance = (MacroVectorScore - LowerMacroVectorScore) × SeverityDistance / Depth = (6.3 - 5.2) × 0 / 0.1 = 0
+EQ3 and EQ6 Proportional Distance = (MacroVectorScore - LowerMacroVectorScore) × SeverityDistance / Depth = (6.3 - 5.2) × 0 / 0.7 = 0
+Mean Distance = Sum(ProportionalDistances) / Count(LowerMacroVectors) = Sum(ProportionalDistances) / 3 = 0.383333
+Environmental Score = Round(MacroVectorScore - MeanDistance) = Round(6.3 - 0.383333) = 5.9
+Base Score = CVSS-B = 8.5
+Temporal Score = CVSS-BT = 5.9`,
+		},
+		{
+			given: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:N/VI:N/VA:N/SC:N/SI:N/SA:N",
+			expected: `EQ1 = AV, PR, UI = AV:N/PR:N/UI:N = 0
+EQ2 = AC, AT = AC:L/AT:N = 0
+EQ3 = VC, VI, VA = VC:N/VI:N/VA:N = 2
+EQ4 = SC, SI, SA = SC:N/SI:N/SA:N = 2
+EQ5 = E = E:A = 0
+EQ6 = VC, VI, VA, CR, IR, AR = VC:N/VI:N/VA:N/CR:H/IR:H/AR:H = 1
+Environmental Score = 0 if VC, VI, VA, SC, SI and SA are None = 0
+Base Score = CVSS-B = 0
+Temporal Score = CVSS-BT = 0`,
+		},
+	}
+	for _, c := range testCases {
+		var actual = ShouldParse(c.given).Explain().String()
+		if actual != c.expected {
+			t.Errorf("explanation of %s == \n%s\n, but got \n%s", c.given, c.expected, actual)
+		}
+	}
+}
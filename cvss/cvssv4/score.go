@@ -0,0 +1,400 @@
+package cvssv4
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// metrics holds the effective values of the scoring metrics,
+// the modified metrics are applied,
+// and the not defined threat metrics and security requirements are resolved to the worst case.
+type metrics map[string]string
+
+// effective returns the effective metrics of this CVSS(V4) vector,
+// according to https://www.first.org/cvss/v4.0/specification-document#Environmental-Metrics.
+func (in Vector) effective() metrics {
+	var m = metrics{
+		"AV": string(in.AttackVector),
+		"AC": string(in.AttackComplexity),
+		"AT": string(in.AttackRequirements),
+		"PR": string(in.PrivilegesRequired),
+		"UI": string(in.UserInteraction),
+		"VC": string(in.VulnerableSystemConfidentiality),
+		"VI": string(in.VulnerableSystemIntegrity),
+		"VA": string(in.VulnerableSystemAvailability),
+		"SC": string(in.SubsequentSystemConfidentiality),
+		"SI": string(in.SubsequentSystemIntegrity),
+		"SA": string(in.SubsequentSystemAvailability),
+		"E":  string(in.ExploitMaturity),
+		"CR": string(in.ConfidentialityRequirement),
+		"IR": string(in.IntegrityRequirement),
+		"AR": string(in.AvailabilityRequirement),
+	}
+	var modified = map[string]string{
+		"AV": string(in.ModifiedAttackVector),
+		"AC": string(in.ModifiedAttackComplexity),
+		"AT": string(in.ModifiedAttackRequirements),
+		"PR": string(in.ModifiedPrivilegesRequired),
+		"UI": string(in.ModifiedUserInteraction),
+		"VC": string(in.ModifiedVulnerableSystemConfidentiality),
+		"VI": string(in.ModifiedVulnerableSystemIntegrity),
+		"VA": string(in.ModifiedVulnerableSystemAvailability),
+		"SC": string(in.ModifiedSubsequentSystemConfidentiality),
+		"SI": string(in.ModifiedSubsequentSystemIntegrity),
+		"SA": string(in.ModifiedSubsequentSystemAvailability),
+	}
+	for mn, mv := range modified {
+		if mv != "" && mv != "X" {
+			m[mn] = mv
+		}
+	}
+	// NB: the not defined exploit maturity is assumed as attacked,
+	// and the not defined security requirements are assumed as high.
+	if m["E"] == "" || m["E"] == "X" {
+		m["E"] = "A"
+	}
+	for _, mn := range []string{"CR", "IR", "AR"} {
+		if m[mn] == "" || m[mn] == "X" {
+			m[mn] = "H"
+		}
+	}
+	return m
+}
+
+// macroVector returns the six equivalence classes of the metrics,
+// according to https://www.first.org/cvss/v4.0/specification-document#CVSS-v4-0-Scoring-using-MacroVectors-and-Interpolation.
+func (m metrics) macroVector() string {
+	var eq [6]int
+
+	// EQ1: 0-AV:N and PR:N and UI:N
+	//      1-(AV:N or PR:N or UI:N) and not (AV:N and PR:N and UI:N) and not AV:P
+	//      2-AV:P or not(AV:N or PR:N or UI:N)
+	switch {
+	case m["AV"] == "N" && m["PR"] == "N" && m["UI"] == "N":
+		eq[0] = 0
+	case (m["AV"] == "N" || m["PR"] == "N" || m["UI"] == "N") && m["AV"] != "P":
+		eq[0] = 1
+	default:
+		eq[0] = 2
+	}
+
+	// EQ2: 0-(AC:L and AT:N)
+	//      1-not(AC:L and AT:N)
+	if m["AC"] != "L" || m["AT"] != "N" {
+		eq[1] = 1
+	}
+
+	// EQ3: 0-(VC:H and VI:H)
+	//      1-not(VC:H and VI:H) and (VC:H or VI:H or VA:H)
+	//      2-not(VC:H or VI:H or VA:H)
+	switch {
+	case m["VC"] == "H" && m["VI"] == "H":
+		eq[2] = 0
+	case m["VC"] == "H" || m["VI"] == "H" || m["VA"] == "H":
+		eq[2] = 1
+	default:
+		eq[2] = 2
+	}
+
+	// EQ4: 0-(MSI:S or MSA:S)
+	//      1-not(MSI:S or MSA:S) and (SC:H or SI:H or SA:H)
+	//      2-not(MSI:S or MSA:S) and not(SC:H or SI:H or SA:H)
+	switch {
+	case m["SI"] == "S" || m["SA"] == "S":
+		eq[3] = 0
+	case m["SC"] == "H" || m["SI"] == "H" || m["SA"] == "H":
+		eq[3] = 1
+	default:
+		eq[3] = 2
+	}
+
+	// EQ5: 0-E:A
+	//      1-E:P
+	//      2-E:U
+	switch m["E"] {
+	case "A":
+		eq[4] = 0
+	case "P":
+		eq[4] = 1
+	default:
+		eq[4] = 2
+	}
+
+	// EQ6: 0-(CR:H and VC:H) or (IR:H and VI:H) or (AR:H and VA:H)
+	//      1-not[(CR:H and VC:H) or (IR:H and VI:H) or (AR:H and VA:H)]
+	if !(m["CR"] == "H" && m["VC"] == "H" ||
+		m["IR"] == "H" && m["VI"] == "H" ||
+		m["AR"] == "H" && m["VA"] == "H") {
+		eq[5] = 1
+	}
+
+	var sb strings.Builder
+	for i := range eq {
+		sb.WriteString(strconv.Itoa(eq[i]))
+	}
+	return sb.String()
+}
+
+// scoring holds the intermediate values of the score calculating.
+type scoring struct {
+	// metrics is the effective metrics.
+	metrics metrics
+	// macroVector is the MacroVector of the metrics.
+	macroVector string
+	// value is the score of the MacroVector.
+	value float64
+	// lowers is the score of the lower MacroVector in each EQ,
+	// EQ3 and EQ6 are joint.
+	lowers [5]struct {
+		score float64
+		ok    bool
+	}
+	// distances is the severity distance from the highest severity vector in each EQ.
+	distances [5]int
+	// depths is the depth of the MacroVector in each EQ.
+	depths [5]int
+	// proportions is the proportional distance in each EQ.
+	proportions [5]float64
+	// mean is the mean of the proportional distances.
+	mean float64
+	// score is the final score.
+	score float64
+}
+
+// score returns the score of this CVSS(V4) vector.
+func (in Vector) score() float64 {
+	return in.scoring().score
+}
+
+// scoring returns the score calculating of this CVSS(V4) vector,
+// which is interpolated by the severity distance from the highest severity vector of the same MacroVector,
+// according to https://github.com/FIRSTdotorg/cvss-v4-calculator/blob/main/cvss_score.js.
+func (in Vector) scoring() (r scoring) {
+	var m = in.effective()
+	r.metrics = m
+	r.macroVector = m.macroVector()
+
+	// NB: no impact on both the vulnerable system and the subsequent system.
+	if m["VC"] == "N" && m["VI"] == "N" && m["VA"] == "N" &&
+		m["SC"] == "N" && m["SI"] == "N" && m["SA"] == "N" {
+		return
+	}
+
+	var mv = r.macroVector
+	r.value = lookups[mv]
+	var eq [6]int
+	for i := range eq {
+		eq[i] = int(mv[i] - '0')
+	}
+
+	// 1. For each of the EQs:
+	//   a. The maximal scoring difference is determined as the difference
+	//      between the current MacroVector and the lower MacroVector,
+	//      if there is no lower MacroVector, the maximal scoring difference is ignored.
+	var lower = func(deltas ...int) (float64, bool) {
+		var n = eq
+		for i := 0; i < len(deltas); i += 2 {
+			n[deltas[i]] += deltas[i+1]
+		}
+		var sb strings.Builder
+		for i := range n {
+			sb.WriteString(strconv.Itoa(n[i]))
+		}
+		var s, ok = lookups[sb.String()]
+		return s, ok
+	}
+	r.lowers[0].score, r.lowers[0].ok = lower(0, 1)
+	r.lowers[1].score, r.lowers[1].ok = lower(1, 1)
+	switch {
+	case eq[2] == 0 && eq[5] == 0:
+		// NB: 00 can go to 01 or 10, takes the higher one.
+		var ls, lok = lower(5, 1)
+		var rs, rok = lower(2, 1)
+		switch {
+		case lok && (!rok || ls > rs):
+			r.lowers[2].score, r.lowers[2].ok = ls, lok
+		default:
+			r.lowers[2].score, r.lowers[2].ok = rs, rok
+		}
+	case eq[2] == 1 && eq[5] == 0:
+		// NB: 10 goes to 11.
+		r.lowers[2].score, r.lowers[2].ok = lower(5, 1)
+	default:
+		// NB: 01 goes to 11, 11 goes to 21, 21 goes to 32 which doesn't exist.
+		r.lowers[2].score, r.lowers[2].ok = lower(2, 1)
+	}
+	r.lowers[3].score, r.lowers[3].ok = lower(3, 1)
+	r.lowers[4].score, r.lowers[4].ok = lower(4, 1)
+
+	//   b. The severity distance of the to-be scored vector from a
+	//      highest severity vector in the same MacroVector is determined.
+	r.distances = m.severityDistances(eq)
+
+	//   c. The proportion of the distance is determined by dividing
+	//      the severity distance of the to-be-scored vector by the depth of the MacroVector.
+	//   d. The maximal scoring difference is multiplied by the proportion of distance.
+	r.depths = [5]int{
+		maxSeverities.eq1[eq[0]],
+		maxSeverities.eq2[eq[1]],
+		maxSeverities.eq3eq6[eq[2]][eq[5]],
+		maxSeverities.eq4[eq[3]],
+		maxSeverities.eq5[eq[4]],
+	}
+	var existing int
+	var normalized float64
+	for i := range r.lowers {
+		if !r.lowers[i].ok {
+			continue
+		}
+		existing++
+		// NB: the proportion of EQ5 is always 0.
+		if i == 4 {
+			continue
+		}
+		r.proportions[i] = (r.value - r.lowers[i].score) * float64(r.distances[i]) / float64(r.depths[i])
+		normalized += r.proportions[i]
+	}
+
+	// 2. The mean of the above computed proportional distances is computed.
+	// 3. The score of the vector is the score of the MacroVector minus the mean distance,
+	//    which is rounded to one decimal place.
+	var value = r.value
+	if existing != 0 {
+		r.mean = normalized / float64(existing)
+		value -= r.mean
+	}
+	r.score = round(math.Max(0, math.Min(10, value)))
+	return
+}
+
+// severityDistances returns the severity distances of EQ1, EQ2, EQ3 and EQ6, EQ4 and EQ5
+// from the first highest severity vector which is not lower than the metrics.
+func (m metrics) severityDistances(eq [6]int) (r [5]int) {
+	var distance = func(mx metrics, mns ...string) (d int, ok bool) {
+		for _, mn := range mns {
+			var md = levels[mn][m[mn]] - levels[mn][mx[mn]]
+			if md < 0 {
+				return 0, false
+			}
+			d += md
+		}
+		return d, true
+	}
+
+	for _, e1 := range maxComposed.eq1[eq[0]] {
+		for _, e2 := range maxComposed.eq2[eq[1]] {
+			for _, e36 := range maxComposed.eq3eq6[eq[2]][eq[5]] {
+				for _, e4 := range maxComposed.eq4[eq[3]] {
+					var d1, ok1 = distance(e1, "AV", "PR", "UI")
+					var d2, ok2 = distance(e2, "AC", "AT")
+					var d36, ok36 = distance(e36, "VC", "VI", "VA", "CR", "IR", "AR")
+					var d4, ok4 = distance(e4, "SC", "SI", "SA")
+					if ok1 && ok2 && ok36 && ok4 {
+						return [5]int{d1, d2, d36, d4, 0}
+					}
+				}
+			}
+		}
+	}
+	return
+}
+
+func round(f float64) float64 {
+	if f <= 0 {
+		return 0.0
+	}
+	// NB: avoid the floating error on rounding, e.g. 4.45 is represented as 4.4499999.
+	const epsilon = 1e-6
+	return math.Round((f+epsilon)*10) / 10
+}
+
+// levels holds the severity levels of the scoring metrics, the lower is more severe,
+// which are ten times of the levels of the reference implementation.
+var levels = map[string]map[string]int{
+	"AV": {"N": 0, "A": 1, "L": 2, "P": 3},
+	"PR": {"N": 0, "L": 1, "H": 2},
+	"UI": {"N": 0, "P": 1, "A": 2},
+	"AC": {"L": 0, "H": 1},
+	"AT": {"N": 0, "P": 1},
+	"VC": {"H": 0, "L": 1, "N": 2},
+	"VI": {"H": 0, "L": 1, "N": 2},
+	"VA": {"H": 0, "L": 1, "N": 2},
+	"SC": {"H": 1, "L": 2, "N": 3},
+	"SI": {"S": 0, "H": 1, "L": 2, "N": 3},
+	"SA": {"S": 0, "H": 1, "L": 2, "N": 3},
+	"CR": {"H": 0, "M": 1, "L": 2},
+	"IR": {"H": 0, "M": 1, "L": 2},
+	"AR": {"H": 0, "M": 1, "L": 2},
+}
+
+// maxSeverities holds the depths of the MacroVectors in each EQ,
+// which are ten times of the max severities of the reference implementation.
+var maxSeverities = struct {
+	eq1    map[int]int
+	eq2    map[int]int
+	eq3eq6 map[int]map[int]int
+	eq4    map[int]int
+	eq5    map[int]int
+}{
+	eq1: map[int]int{0: 1, 1: 4, 2: 5},
+	eq2: map[int]int{0: 1, 1: 2},
+	eq3eq6: map[int]map[int]int{
+		0: {0: 7, 1: 6},
+		1: {0: 8, 1: 8},
+		2: {1: 10},
+	},
+	eq4: map[int]int{0: 6, 1: 5, 2: 4},
+	eq5: map[int]int{0: 1, 1: 1, 2: 1},
+}
+
+// maxComposed holds the highest severity vectors of the MacroVectors in each EQ.
+var maxComposed = struct {
+	eq1    map[int][]metrics
+	eq2    map[int][]metrics
+	eq3eq6 map[int]map[int][]metrics
+	eq4    map[int][]metrics
+}{
+	eq1: map[int][]metrics{
+		0: composed("AV:N/PR:N/UI:N"),
+		1: composed("AV:A/PR:N/UI:N", "AV:N/PR:L/UI:N", "AV:N/PR:N/UI:P"),
+		2: composed("AV:P/PR:N/UI:N", "AV:A/PR:L/UI:P"),
+	},
+	eq2: map[int][]metrics{
+		0: composed("AC:L/AT:N"),
+		1: composed("AC:H/AT:N", "AC:L/AT:P"),
+	},
+	eq3eq6: map[int]map[int][]metrics{
+		0: {
+			0: composed("VC:H/VI:H/VA:H/CR:H/IR:H/AR:H"),
+			1: composed("VC:H/VI:H/VA:L/CR:M/IR:M/AR:H", "VC:H/VI:H/VA:H/CR:M/IR:M/AR:M"),
+		},
+		1: {
+			0: composed("VC:L/VI:H/VA:H/CR:H/IR:H/AR:H", "VC:H/VI:L/VA:H/CR:H/IR:H/AR:H"),
+			1: composed("VC:L/VI:H/VA:H/CR:H/IR:M/AR:M", "VC:L/VI:H/VA:L/CR:H/IR:M/AR:H",
+				"VC:H/VI:L/VA:H/CR:M/IR:H/AR:M", "VC:H/VI:L/VA:L/CR:M/IR:H/AR:H",
+				"VC:L/VI:L/VA:H/CR:H/IR:H/AR:M"),
+		},
+		2: {
+			1: composed("VC:L/VI:L/VA:L/CR:H/IR:H/AR:H"),
+		},
+	},
+	eq4: map[int][]metrics{
+		0: composed("SC:H/SI:S/SA:S"),
+		1: composed("SC:H/SI:H/SA:H"),
+		2: composed("SC:L/SI:L/SA:L"),
+	},
+}
+
+func composed(ss ...string) []metrics {
+	var r = make([]metrics, 0, len(ss))
+	for _, s := range ss {
+		var m = metrics{}
+		for _, p := range strings.Split(s, "/") {
+			var mn, mv, _ = strings.Cut(p, ":")
+			m[mn] = mv
+		}
+		r = append(r, m)
+	}
+	return r
+}
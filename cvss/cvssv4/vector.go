@@ -0,0 +1,920 @@
+package cvssv4
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/seal-io/meta-api/cvss/compatible"
+)
+
+// DefaultVector returns a default definition of CVSS(V4) vector.
+func DefaultVector() Vector {
+	return Vector{
+		Version: Version40,
+		BasicMetrics: BasicMetrics{
+			AttackVector:                    AttackVectorPhysical,
+			AttackComplexity:                AttackComplexityHigh,
+			AttackRequirements:              AttackRequirementsPresent,
+			PrivilegesRequired:              PrivilegesRequiredHigh,
+			UserInteraction:                 UserInteractionActive,
+			VulnerableSystemConfidentiality: VulnerableSystemImpactNone,
+			VulnerableSystemIntegrity:       VulnerableSystemImpactNone,
+			VulnerableSystemAvailability:    VulnerableSystemImpactNone,
+			SubsequentSystemConfidentiality: SubsequentSystemImpactNone,
+			SubsequentSystemIntegrity:       SubsequentSystemImpactNone,
+			SubsequentSystemAvailability:    SubsequentSystemImpactNone,
+		},
+		ThreatMetrics: ThreatMetrics{
+			ExploitMaturity: ExploitMaturityNotDefined,
+		},
+		EnvironmentalMetrics: EnvironmentalMetrics{
+			ConfidentialityRequirement:              SecurityRequirementNotDefined,
+			IntegrityRequirement:                    SecurityRequirementNotDefined,
+			AvailabilityRequirement:                 SecurityRequirementNotDefined,
+			ModifiedAttackVector:                    AttackVectorNotDefined,
+			ModifiedAttackComplexity:                AttackComplexityNotDefined,
+			ModifiedAttackRequirements:              AttackRequirementsNotDefined,
+			ModifiedPrivilegesRequired:              PrivilegesRequiredNotDefined,
+			ModifiedUserInteraction:                 UserInteractionNotDefined,
+			ModifiedVulnerableSystemConfidentiality: VulnerableSystemImpactNotDefined,
+			ModifiedVulnerableSystemIntegrity:       VulnerableSystemImpactNotDefined,
+			ModifiedVulnerableSystemAvailability:    VulnerableSystemImpactNotDefined,
+			ModifiedSubsequentSystemConfidentiality: SubsequentSystemImpactNotDefined,
+			ModifiedSubsequentSystemIntegrity:       SubsequentSystemImpactNotDefined,
+			ModifiedSubsequentSystemAvailability:    SubsequentSystemImpactNotDefined,
+		},
+		SupplementalMetrics: SupplementalMetrics{
+			Safety:                      SafetyNotDefined,
+			Automatable:                 AutomatableNotDefined,
+			Recovery:                    RecoveryNotDefined,
+			ValueDensity:                ValueDensityNotDefined,
+			VulnerabilityResponseEffort: VulnerabilityResponseEffortNotDefined,
+			ProviderUrgency:             ProviderUrgencyNotDefined,
+		},
+	}
+}
+
+// ShouldParse likes Parse but without error returning.
+func ShouldParse(s string) Vector {
+	var p, _ = Parse(s)
+	return p
+}
+
+// Parse parses Vector from CVSS(V4) vector string.
+func Parse(s string) (Vector, error) {
+	const mandatorySize = 12
+	s = strings.TrimSpace(s)
+	var v = DefaultVector()
+	var parts = strings.Split(s, "/")
+	if len(parts) < mandatorySize || parts[0] != "CVSS:"+string(Version40) {
+		return Vector{}, fmt.Errorf("illegal CVSS(V4) vector: %s", s)
+	}
+	for i, part := range parts {
+		var kv = strings.SplitN(part, ":", 2)
+		if len(kv) != 2 {
+			return Vector{}, fmt.Errorf("incomplete CVSS(V4) vector: %s", s)
+		}
+		var mn = strings.TrimSpace(kv[0])
+		if mn == "" {
+			return Vector{}, fmt.Errorf("incomplete CVSS(V4) vector: %s", s)
+		}
+		var mv = strings.TrimSpace(kv[1])
+		if mv == "" {
+			return Vector{}, fmt.Errorf("incomplete CVSS(V4) vector: %s", s)
+		}
+		var defined bool
+		switch mn {
+		// version
+		case "CVSS":
+			v.Version = Version(mv)
+			if !v.Version.isDefined() {
+				return Vector{}, fmt.Errorf("invalid version '%s' in CVSS(V4) vector: %s", mv, s)
+			}
+			continue
+		// base metrics
+		case "AV":
+			v.AttackVector = AttackVector(mv)
+			defined = v.AttackVector.isDefined()
+		case "AC":
+			v.AttackComplexity = AttackComplexity(mv)
+			defined = v.AttackComplexity.isDefined()
+		case "AT":
+			v.AttackRequirements = AttackRequirements(mv)
+			defined = v.AttackRequirements.isDefined()
+		case "PR":
+			v.PrivilegesRequired = PrivilegesRequired(mv)
+			defined = v.PrivilegesRequired.isDefined()
+		case "UI":
+			v.UserInteraction = UserInteraction(mv)
+			defined = v.UserInteraction.isDefined()
+		case "VC":
+			v.VulnerableSystemConfidentiality = VulnerableSystemImpact(mv)
+			defined = v.VulnerableSystemConfidentiality.isDefined()
+		case "VI":
+			v.VulnerableSystemIntegrity = VulnerableSystemImpact(mv)
+			defined = v.VulnerableSystemIntegrity.isDefined()
+		case "VA":
+			v.VulnerableSystemAvailability = VulnerableSystemImpact(mv)
+			defined = v.VulnerableSystemAvailability.isDefined()
+		case "SC":
+			v.SubsequentSystemConfidentiality = SubsequentSystemImpact(mv)
+			defined = v.SubsequentSystemConfidentiality.isDefined()
+		case "SI":
+			v.SubsequentSystemIntegrity = SubsequentSystemImpact(mv)
+			defined = v.SubsequentSystemIntegrity.isDefined()
+		case "SA":
+			v.SubsequentSystemAvailability = SubsequentSystemImpact(mv)
+			defined = v.SubsequentSystemAvailability.isDefined()
+		default:
+			if i < mandatorySize {
+				return Vector{}, fmt.Errorf("'%s' is not mandatory metric in CVSS(V4) vector: %s", mn, s)
+			}
+			var valid bool
+			switch mn {
+			default:
+				return Vector{}, fmt.Errorf("unknown metric '%s' in CVSS(V4) vector: %s", mn, s)
+			// threat metrics
+			case "E":
+				v.ExploitMaturity = ExploitMaturity(mv)
+				valid = v.ExploitMaturity.isValid()
+			// environmental metrics
+			case "CR":
+				v.ConfidentialityRequirement = SecurityRequirement(mv)
+				valid = v.ConfidentialityRequirement.isValid()
+			case "IR":
+				v.IntegrityRequirement = SecurityRequirement(mv)
+				valid = v.IntegrityRequirement.isValid()
+			case "AR":
+				v.AvailabilityRequirement = SecurityRequirement(mv)
+				valid = v.AvailabilityRequirement.isValid()
+			case "MAV":
+				v.ModifiedAttackVector = AttackVector(mv)
+				valid = v.ModifiedAttackVector.isValid()
+			case "MAC":
+				v.ModifiedAttackComplexity = AttackComplexity(mv)
+				valid = v.ModifiedAttackComplexity.isValid()
+			case "MAT":
+				v.ModifiedAttackRequirements = AttackRequirements(mv)
+				valid = v.ModifiedAttackRequirements.isValid()
+			case "MPR":
+				v.ModifiedPrivilegesRequired = PrivilegesRequired(mv)
+				valid = v.ModifiedPrivilegesRequired.isValid()
+			case "MUI":
+				v.ModifiedUserInteraction = UserInteraction(mv)
+				valid = v.ModifiedUserInteraction.isValid()
+			case "MVC":
+				v.ModifiedVulnerableSystemConfidentiality = VulnerableSystemImpact(mv)
+				valid = v.ModifiedVulnerableSystemConfidentiality.isValid()
+			case "MVI":
+				v.ModifiedVulnerableSystemIntegrity = VulnerableSystemImpact(mv)
+				valid = v.ModifiedVulnerableSystemIntegrity.isValid()
+			case "MVA":
+				v.ModifiedVulnerableSystemAvailability = VulnerableSystemImpact(mv)
+				valid = v.ModifiedVulnerableSystemAvailability.isValid()
+			case "MSC":
+				v.ModifiedSubsequentSystemConfidentiality = SubsequentSystemImpact(mv)
+				valid = v.ModifiedSubsequentSystemConfidentiality.isValid()
+			case "MSI":
+				v.ModifiedSubsequentSystemIntegrity = SubsequentSystemImpact(mv)
+				valid = v.ModifiedSubsequentSystemIntegrity.isValid() ||
+					v.ModifiedSubsequentSystemIntegrity == SubsequentSystemImpactSafety
+			case "MSA":
+				v.ModifiedSubsequentSystemAvailability = SubsequentSystemImpact(mv)
+				valid = v.ModifiedSubsequentSystemAvailability.isValid() ||
+					v.ModifiedSubsequentSystemAvailability == SubsequentSystemImpactSafety
+			// supplemental metrics
+			case "S":
+				v.Safety = Safety(mv)
+				valid = v.Safety.isValid()
+			case "AU":
+				v.Automatable = Automatable(mv)
+				valid = v.Automatable.isValid()
+			case "R":
+				v.Recovery = Recovery(mv)
+				valid = v.Recovery.isValid()
+			case "V":
+				v.ValueDensity = ValueDensity(mv)
+				valid = v.ValueDensity.isValid()
+			case "RE":
+				v.VulnerabilityResponseEffort = VulnerabilityResponseEffort(mv)
+				valid = v.VulnerabilityResponseEffort.isValid()
+			case "U":
+				v.ProviderUrgency = ProviderUrgency(mv)
+				valid = v.ProviderUrgency.isValid()
+			}
+			if !valid {
+				return Vector{}, fmt.Errorf("invalid value '%s' of metric '%s' in CVSS(V4) vector: %s", mv, mn, s)
+			}
+			continue
+		}
+		if !defined {
+			return Vector{}, fmt.Errorf("undefined mandatory metric '%s' in CVSS(V4) vector: %s", mn, s)
+		}
+	}
+	return v, nil
+}
+
+// Vector holds the metrics vector of CVSS(V4).
+type Vector struct {
+	Version
+	BasicMetrics
+	ThreatMetrics
+	EnvironmentalMetrics
+	SupplementalMetrics
+}
+
+// RawImpactScore return the raw impact score of this CVSS(V4) vector,
+// CVSS(V4) doesn't define the impact sub score, so it always returns 0.
+func (in Vector) RawImpactScore(modified bool) float64 {
+	return 0
+}
+
+// RawExploitabilityScore return the raw exploitability score of this CVSS(V4) vector,
+// CVSS(V4) doesn't define the exploitability sub score, so it always returns 0.
+func (in Vector) RawExploitabilityScore(modified bool) float64 {
+	return 0
+}
+
+// ImpactScore returns the impact score of this CVSS(V4) vector,
+// CVSS(V4) doesn't define the impact sub score, so it always returns 0.
+func (in Vector) ImpactScore() float64 {
+	return 0
+}
+
+// ExploitabilityScore returns the exploitability score of this CVSS(V4) vector,
+// CVSS(V4) doesn't define the exploitability sub score, so it always returns 0.
+func (in Vector) ExploitabilityScore() float64 {
+	return 0
+}
+
+// BaseScore returns the CVSS-B score of this CVSS(V4) vector,
+// which only takes the base metrics into account.
+func (in Vector) BaseScore() float64 {
+	var c = DefaultVector()
+	c.BasicMetrics = in.BasicMetrics
+	return c.score()
+}
+
+// BaseSeverity returns the base severity of this CVSS(V4) vector.
+func (in Vector) BaseSeverity() string {
+	return GetSeverityByScore(in.BaseScore())
+}
+
+// BaseScoreAndSeverity returns the base score and severity of this CVSS(V4) vector.
+func (in Vector) BaseScoreAndSeverity() (score float64, severity string) {
+	score = in.BaseScore()
+	severity = GetSeverityByScore(score)
+	return
+}
+
+// TemporalScore returns the CVSS-BT score of this CVSS(V4) vector,
+// which takes the base metrics and the threat metrics into account,
+// the given base score is ignored as the score is looked up by the MacroVector.
+func (in Vector) TemporalScore(baseScore ...float64) float64 {
+	var c = DefaultVector()
+	c.BasicMetrics = in.BasicMetrics
+	c.ThreatMetrics = in.ThreatMetrics
+	return c.score()
+}
+
+// TemporalSeverity returns the temporal severity of this CVSS(V4) vector.
+func (in Vector) TemporalSeverity(baseScore ...float64) string {
+	return GetSeverityByScore(in.TemporalScore(baseScore...))
+}
+
+// TemporalScoreAndSeverity returns the temporal score and severity of this CVSS(V4) vector.
+func (in Vector) TemporalScoreAndSeverity(baseScore ...float64) (score float64, severity string) {
+	score = in.TemporalScore(baseScore...)
+	severity = GetSeverityByScore(score)
+	return
+}
+
+// EnvironmentalScore returns the CVSS-BTE score of this CVSS(V4) vector,
+// which takes the base metrics, the threat metrics and the environmental metrics into account.
+func (in Vector) EnvironmentalScore() float64 {
+	var c = DefaultVector().Override(in)
+	return c.score()
+}
+
+// EnvironmentalSeverity returns the environmental severity of this CVSS(V4) vector.
+func (in Vector) EnvironmentalSeverity() string {
+	return GetSeverityByScore(in.EnvironmentalScore())
+}
+
+// EnvironmentalScoreAndSeverity returns the environmental score and severity of this CVSS(V4) vector.
+func (in Vector) EnvironmentalScoreAndSeverity() (score float64, severity string) {
+	score = in.EnvironmentalScore()
+	severity = GetSeverityByScore(score)
+	return
+}
+
+// ScoreAndSeverity returns the score and severity of this CVSS(V4) vector, including
+// - BaseScore and BaseSeverity
+// - TemporalScore and TemporalSeverity
+// - EnvironmentalScore and EnvironmentalSeverity
+func (in Vector) ScoreAndSeverity() (bs float64, bsv string, ts float64, tsv string, es float64, esv string) {
+	bs, bsv = in.BaseScoreAndSeverity()
+	ts, tsv = in.TemporalScoreAndSeverity(bs)
+	es, esv = in.EnvironmentalScoreAndSeverity()
+	return
+}
+
+// Nomenclature returns the nomenclature of this CVSS(V4) vector's score,
+// i.e. CVSS-B, CVSS-BT, CVSS-BE or CVSS-BTE,
+// according to https://www.first.org/cvss/v4.0/specification-document#Nomenclature.
+func (in Vector) Nomenclature() string {
+	var c = DefaultVector().Override(in)
+	var d = DefaultVector()
+	var r = "CVSS-B"
+	if c.ThreatMetrics != d.ThreatMetrics {
+		r += "T"
+	}
+	if c.EnvironmentalMetrics != d.EnvironmentalMetrics {
+		r += "E"
+	}
+	return r
+}
+
+// MacroVector returns the MacroVector of this CVSS(V4) vector,
+// which is the six digits of the equivalence classes, e.g. 000200.
+func (in Vector) MacroVector() string {
+	return DefaultVector().Override(in).effective().macroVector()
+}
+
+// GetVersion returns the cvss version of this CVSS(V4) vector.
+func (in Vector) GetVersion() string {
+	if in.Version.isDefined() {
+		return string(in.Version)
+	}
+	return string(Version40)
+}
+
+// String returns the string format of this CVSS(V4) vector.
+func (in Vector) String() string {
+	var c = DefaultVector().Override(in)
+	var sb strings.Builder
+
+	var write = func(mn, mv string, optional bool) {
+		if optional && mv == "X" {
+			return
+		}
+		sb.WriteString("/")
+		sb.WriteString(mn)
+		sb.WriteString(":")
+		sb.WriteString(mv)
+	}
+
+	// version
+	sb.WriteString("CVSS:")
+	sb.WriteString(string(c.Version))
+
+	// base metrics
+	write("AV", string(c.AttackVector), false)
+	write("AC", string(c.AttackComplexity), false)
+	write("AT", string(c.AttackRequirements), false)
+	write("PR", string(c.PrivilegesRequired), false)
+	write("UI", string(c.UserInteraction), false)
+	write("VC", string(c.VulnerableSystemConfidentiality), false)
+	write("VI", string(c.VulnerableSystemIntegrity), false)
+	write("VA", string(c.VulnerableSystemAvailability), false)
+	write("SC", string(c.SubsequentSystemConfidentiality), false)
+	write("SI", string(c.SubsequentSystemIntegrity), false)
+	write("SA", string(c.SubsequentSystemAvailability), false)
+	// threat metrics
+	write("E", string(c.ExploitMaturity), true)
+	// environmental metrics
+	write("CR", string(c.ConfidentialityRequirement), true)
+	write("IR", string(c.IntegrityRequirement), true)
+	write("AR", string(c.AvailabilityRequirement), true)
+	write("MAV", string(c.ModifiedAttackVector), true)
+	write("MAC", string(c.ModifiedAttackComplexity), true)
+	write("MAT", string(c.ModifiedAttackRequirements), true)
+	write("MPR", string(c.ModifiedPrivilegesRequired), true)
+	write("MUI", string(c.ModifiedUserInteraction), true)
+	write("MVC", string(c.ModifiedVulnerableSystemConfidentiality), true)
+	write("MVI", string(c.ModifiedVulnerableSystemIntegrity), true)
+	write("MVA", string(c.ModifiedVulnerableSystemAvailability), true)
+	write("MSC", string(c.ModifiedSubsequentSystemConfidentiality), true)
+	write("MSI", string(c.ModifiedSubsequentSystemIntegrity), true)
+	write("MSA", string(c.ModifiedSubsequentSystemAvailability), true)
+	// supplemental metrics
+	write("S", string(c.Safety), true)
+	write("AU", string(c.Automatable), true)
+	write("R", string(c.Recovery), true)
+	write("V", string(c.ValueDensity), true)
+	write("RE", string(c.VulnerabilityResponseEffort), true)
+	write("U", string(c.ProviderUrgency), true)
+
+	return sb.String()
+}
+
+// IsZero returns true if this CVSS(V4) vector is empty,
+// DefaultVector is also an empty vector.
+func (in Vector) IsZero() bool {
+	return in == DefaultVector() || in == Vector{}
+}
+
+// ToLatest converts this CVSS(V4) vector to the latest version CVSS vector,
+// CVSS(V4) is the latest version, so it returns itself.
+func (in Vector) ToLatest() compatible.Vector {
+	return in
+}
+
+// Override merges the valued metrics of the given Vector.
+func (in Vector) Override(v Vector) (out Vector) {
+	out = in
+
+	// version
+	if v.Version != "" {
+		out.Version = v.Version
+	}
+
+	// basic metrics
+	var bm = v.BasicMetrics
+	if bm.AttackVector != "" {
+		out.AttackVector = bm.AttackVector
+	}
+	if bm.AttackComplexity != "" {
+		out.AttackComplexity = bm.AttackComplexity
+	}
+	if bm.AttackRequirements != "" {
+		out.AttackRequirements = bm.AttackRequirements
+	}
+	if bm.PrivilegesRequired != "" {
+		out.PrivilegesRequired = bm.PrivilegesRequired
+	}
+	if bm.UserInteraction != "" {
+		out.UserInteraction = bm.UserInteraction
+	}
+	if bm.VulnerableSystemConfidentiality != "" {
+		out.VulnerableSystemConfidentiality = bm.VulnerableSystemConfidentiality
+	}
+	if bm.VulnerableSystemIntegrity != "" {
+		out.VulnerableSystemIntegrity = bm.VulnerableSystemIntegrity
+	}
+	if bm.VulnerableSystemAvailability != "" {
+		out.VulnerableSystemAvailability = bm.VulnerableSystemAvailability
+	}
+	if bm.SubsequentSystemConfidentiality != "" {
+		out.SubsequentSystemConfidentiality = bm.SubsequentSystemConfidentiality
+	}
+	if bm.SubsequentSystemIntegrity != "" {
+		out.SubsequentSystemIntegrity = bm.SubsequentSystemIntegrity
+	}
+	if bm.SubsequentSystemAvailability != "" {
+		out.SubsequentSystemAvailability = bm.SubsequentSystemAvailability
+	}
+	// threat metrics
+	var tm = v.ThreatMetrics
+	if tm.ExploitMaturity != "" {
+		out.ExploitMaturity = tm.ExploitMaturity
+	}
+	// environmental metrics
+	var em = v.EnvironmentalMetrics
+	if em.ConfidentialityRequirement != "" {
+		out.ConfidentialityRequirement = em.ConfidentialityRequirement
+	}
+	if em.IntegrityRequirement != "" {
+		out.IntegrityRequirement = em.IntegrityRequirement
+	}
+	if em.AvailabilityRequirement != "" {
+		out.AvailabilityRequirement = em.AvailabilityRequirement
+	}
+	if em.ModifiedAttackVector != "" {
+		out.ModifiedAttackVector = em.ModifiedAttackVector
+	}
+	if em.ModifiedAttackComplexity != "" {
+		out.ModifiedAttackComplexity = em.ModifiedAttackComplexity
+	}
+	if em.ModifiedAttackRequirements != "" {
+		out.ModifiedAttackRequirements = em.ModifiedAttackRequirements
+	}
+	if em.ModifiedPrivilegesRequired != "" {
+		out.ModifiedPrivilegesRequired = em.ModifiedPrivilegesRequired
+	}
+	if em.ModifiedUserInteraction != "" {
+		out.ModifiedUserInteraction = em.ModifiedUserInteraction
+	}
+	if em.ModifiedVulnerableSystemConfidentiality != "" {
+		out.ModifiedVulnerableSystemConfidentiality = em.ModifiedVulnerableSystemConfidentiality
+	}
+	if em.ModifiedVulnerableSystemIntegrity != "" {
+		out.ModifiedVulnerableSystemIntegrity = em.ModifiedVulnerableSystemIntegrity
+	}
+	if em.ModifiedVulnerableSystemAvailability != "" {
+		out.ModifiedVulnerableSystemAvailability = em.ModifiedVulnerableSystemAvailability
+	}
+	if em.ModifiedSubsequentSystemConfidentiality != "" {
+		out.ModifiedSubsequentSystemConfidentiality = em.ModifiedSubsequentSystemConfidentiality
+	}
+	if em.ModifiedSubsequentSystemIntegrity != "" {
+		out.ModifiedSubsequentSystemIntegrity = em.ModifiedSubsequentSystemIntegrity
+	}
+	if em.ModifiedSubsequentSystemAvailability != "" {
+		out.ModifiedSubsequentSystemAvailability = em.ModifiedSubsequentSystemAvailability
+	}
+	// supplemental metrics
+	var sm = v.SupplementalMetrics
+	if sm.Safety != "" {
+		out.Safety = sm.Safety
+	}
+	if sm.Automatable != "" {
+		out.Automatable = sm.Automatable
+	}
+	if sm.Recovery != "" {
+		out.Recovery = sm.Recovery
+	}
+	if sm.ValueDensity != "" {
+		out.ValueDensity = sm.ValueDensity
+	}
+	if sm.VulnerabilityResponseEffort != "" {
+		out.VulnerabilityResponseEffort = sm.VulnerabilityResponseEffort
+	}
+	if sm.ProviderUrgency != "" {
+		out.ProviderUrgency = sm.ProviderUrgency
+	}
+
+	return
+}
+
+// Version of CVSS(V4) vector.
+type Version string
+
+// constants of Version.
+const (
+	Version40 Version = "4.0"
+)
+
+func (in Version) isDefined() bool {
+	switch in {
+	default:
+		return false
+	case Version40:
+	}
+	return true
+}
+
+// types of basic metrics.
+type (
+	BasicMetrics struct {
+		// Exploitability Metrics
+		AttackVector
+		AttackComplexity
+		AttackRequirements
+		PrivilegesRequired
+		UserInteraction
+		// Vulnerable System Impact Metrics
+		VulnerableSystemConfidentiality VulnerableSystemImpact
+		VulnerableSystemIntegrity       VulnerableSystemImpact
+		VulnerableSystemAvailability    VulnerableSystemImpact
+		// Subsequent System Impact Metrics
+		SubsequentSystemConfidentiality SubsequentSystemImpact
+		SubsequentSystemIntegrity       SubsequentSystemImpact
+		SubsequentSystemAvailability    SubsequentSystemImpact
+	}
+
+	AttackVector           string
+	AttackComplexity       string
+	AttackRequirements     string
+	PrivilegesRequired     string
+	UserInteraction        string
+	VulnerableSystemImpact string
+	SubsequentSystemImpact string
+)
+
+// constants of basic metrics.
+const (
+	AttackVectorNotDefined AttackVector = "X"
+	AttackVectorPhysical   AttackVector = "P"
+	AttackVectorLocal      AttackVector = "L"
+	AttackVectorAdjacent   AttackVector = "A"
+	AttackVectorNetwork    AttackVector = "N"
+
+	AttackComplexityNotDefined AttackComplexity = "X"
+	AttackComplexityHigh       AttackComplexity = "H"
+	AttackComplexityLow        AttackComplexity = "L"
+
+	AttackRequirementsNotDefined AttackRequirements = "X"
+	AttackRequirementsPresent    AttackRequirements = "P"
+	AttackRequirementsNone       AttackRequirements = "N"
+
+	PrivilegesRequiredNotDefined PrivilegesRequired = "X"
+	PrivilegesRequiredHigh       PrivilegesRequired = "H"
+	PrivilegesRequiredLow        PrivilegesRequired = "L"
+	PrivilegesRequiredNone       PrivilegesRequired = "N"
+
+	UserInteractionNotDefined UserInteraction = "X"
+	UserInteractionActive     UserInteraction = "A"
+	UserInteractionPassive    UserInteraction = "P"
+	UserInteractionNone       UserInteraction = "N"
+
+	VulnerableSystemImpactNotDefined VulnerableSystemImpact = "X"
+	VulnerableSystemImpactNone       VulnerableSystemImpact = "N"
+	VulnerableSystemImpactLow        VulnerableSystemImpact = "L"
+	VulnerableSystemImpactHigh       VulnerableSystemImpact = "H"
+
+	SubsequentSystemImpactNotDefined SubsequentSystemImpact = "X"
+	SubsequentSystemImpactNone       SubsequentSystemImpact = "N"
+	SubsequentSystemImpactLow        SubsequentSystemImpact = "L"
+	SubsequentSystemImpactHigh       SubsequentSystemImpact = "H"
+	// SubsequentSystemImpactSafety is only available for the modified subsequent system integrity and availability.
+	SubsequentSystemImpactSafety SubsequentSystemImpact = "S"
+)
+
+func (in AttackVector) isDefined() bool {
+	switch in {
+	default:
+		return false
+	case AttackVectorPhysical:
+	case AttackVectorLocal:
+	case AttackVectorAdjacent:
+	case AttackVectorNetwork:
+	}
+	return true
+}
+
+func (in AttackVector) isValid() bool {
+	return in == AttackVectorNotDefined || in.isDefined()
+}
+
+func (in AttackComplexity) isDefined() bool {
+	switch in {
+	default:
+		return false
+	case AttackComplexityHigh:
+	case AttackComplexityLow:
+	}
+	return true
+}
+
+func (in AttackComplexity) isValid() bool {
+	return in == AttackComplexityNotDefined || in.isDefined()
+}
+
+func (in AttackRequirements) isDefined() bool {
+	switch in {
+	default:
+		return false
+	case AttackRequirementsPresent:
+	case AttackRequirementsNone:
+	}
+	return true
+}
+
+func (in AttackRequirements) isValid() bool {
+	return in == AttackRequirementsNotDefined || in.isDefined()
+}
+
+func (in PrivilegesRequired) isDefined() bool {
+	switch in {
+	default:
+		return false
+	case PrivilegesRequiredHigh:
+	case PrivilegesRequiredLow:
+	case PrivilegesRequiredNone:
+	}
+	return true
+}
+
+func (in PrivilegesRequired) isValid() bool {
+	return in == PrivilegesRequiredNotDefined || in.isDefined()
+}
+
+func (in UserInteraction) isDefined() bool {
+	switch in {
+	default:
+		return false
+	case UserInteractionActive:
+	case UserInteractionPassive:
+	case UserInteractionNone:
+	}
+	return true
+}
+
+func (in UserInteraction) isValid() bool {
+	return in == UserInteractionNotDefined || in.isDefined()
+}
+
+func (in VulnerableSystemImpact) isDefined() bool {
+	switch in {
+	default:
+		return false
+	case VulnerableSystemImpactNone:
+	case VulnerableSystemImpactLow:
+	case VulnerableSystemImpactHigh:
+	}
+	return true
+}
+
+func (in VulnerableSystemImpact) isValid() bool {
+	return in == VulnerableSystemImpactNotDefined || in.isDefined()
+}
+
+func (in SubsequentSystemImpact) isDefined() bool {
+	switch in {
+	default:
+		return false
+	case SubsequentSystemImpactNone:
+	case SubsequentSystemImpactLow:
+	case SubsequentSystemImpactHigh:
+	}
+	return true
+}
+
+func (in SubsequentSystemImpact) isValid() bool {
+	return in == SubsequentSystemImpactNotDefined || in.isDefined()
+}
+
+// types of threat metrics.
+type (
+	ThreatMetrics struct {
+		ExploitMaturity
+	}
+
+	ExploitMaturity string
+)
+
+// constants of threat metrics.
+const (
+	ExploitMaturityNotDefined     ExploitMaturity = "X"
+	ExploitMaturityAttacked       ExploitMaturity = "A"
+	ExploitMaturityProofOfConcept ExploitMaturity = "P"
+	ExploitMaturityUnreported     ExploitMaturity = "U"
+)
+
+func (in ExploitMaturity) isValid() bool {
+	switch in {
+	default:
+		return false
+	case ExploitMaturityNotDefined:
+	case ExploitMaturityAttacked:
+	case ExploitMaturityProofOfConcept:
+	case ExploitMaturityUnreported:
+	}
+	return true
+}
+
+// types of environmental metrics.
+type (
+	EnvironmentalMetrics struct {
+		// Security Requirements
+		ConfidentialityRequirement SecurityRequirement
+		IntegrityRequirement       SecurityRequirement
+		AvailabilityRequirement    SecurityRequirement
+		// Modified Base Metrics
+		ModifiedAttackVector                    AttackVector
+		ModifiedAttackComplexity                AttackComplexity
+		ModifiedAttackRequirements              AttackRequirements
+		ModifiedPrivilegesRequired              PrivilegesRequired
+		ModifiedUserInteraction                 UserInteraction
+		ModifiedVulnerableSystemConfidentiality VulnerableSystemImpact
+		ModifiedVulnerableSystemIntegrity       VulnerableSystemImpact
+		ModifiedVulnerableSystemAvailability    VulnerableSystemImpact
+		ModifiedSubsequentSystemConfidentiality SubsequentSystemImpact
+		ModifiedSubsequentSystemIntegrity       SubsequentSystemImpact
+		ModifiedSubsequentSystemAvailability    SubsequentSystemImpact
+	}
+
+	SecurityRequirement string
+)
+
+// constants of environmental metrics.
+const (
+	SecurityRequirementNotDefined SecurityRequirement = "X"
+	SecurityRequirementLow        SecurityRequirement = "L"
+	SecurityRequirementMedium     SecurityRequirement = "M"
+	SecurityRequirementHigh       SecurityRequirement = "H"
+)
+
+func (in SecurityRequirement) isValid() bool {
+	switch in {
+	default:
+		return false
+	case SecurityRequirementNotDefined:
+	case SecurityRequirementLow:
+	case SecurityRequirementMedium:
+	case SecurityRequirementHigh:
+	}
+	return true
+}
+
+// types of supplemental metrics,
+// which don't take effect on the score.
+type (
+	SupplementalMetrics struct {
+		Safety
+		Automatable
+		Recovery
+		ValueDensity
+		VulnerabilityResponseEffort
+		ProviderUrgency
+	}
+
+	Safety                      string
+	Automatable                 string
+	Recovery                    string
+	ValueDensity                string
+	VulnerabilityResponseEffort string
+	ProviderUrgency             string
+)
+
+// constants of supplemental metrics.
+const (
+	SafetyNotDefined Safety = "X"
+	SafetyNegligible Safety = "N"
+	SafetyPresent    Safety = "P"
+
+	AutomatableNotDefined Automatable = "X"
+	AutomatableNo         Automatable = "N"
+	AutomatableYes        Automatable = "Y"
+
+	RecoveryNotDefined    Recovery = "X"
+	RecoveryAutomatic     Recovery = "A"
+	RecoveryUser          Recovery = "U"
+	RecoveryIrrecoverable Recovery = "I"
+
+	ValueDensityNotDefined   ValueDensity = "X"
+	ValueDensityDiffuse      ValueDensity = "D"
+	ValueDensityConcentrated ValueDensity = "C"
+
+	VulnerabilityResponseEffortNotDefined VulnerabilityResponseEffort = "X"
+	VulnerabilityResponseEffortLow        VulnerabilityResponseEffort = "L"
+	VulnerabilityResponseEffortModerate   VulnerabilityResponseEffort = "M"
+	VulnerabilityResponseEffortHigh       VulnerabilityResponseEffort = "H"
+
+	ProviderUrgencyNotDefined ProviderUrgency = "X"
+	ProviderUrgencyClear      ProviderUrgency = "Clear"
+	ProviderUrgencyGreen      ProviderUrgency = "Green"
+	ProviderUrgencyAmber      ProviderUrgency = "Amber"
+	ProviderUrgencyRed        ProviderUrgency = "Red"
+)
+
+func (in Safety) isValid() bool {
+	switch in {
+	default:
+		return false
+	case SafetyNotDefined:
+	case SafetyNegligible:
+	case SafetyPresent:
+	}
+	return true
+}
+
+func (in Automatable) isValid() bool {
+	switch in {
+	default:
+		return false
+	case AutomatableNotDefined:
+	case AutomatableNo:
+	case AutomatableYes:
+	}
+	return true
+}
+
+func (in Recovery) isValid() bool {
+	switch in {
+	default:
+		return false
+	case RecoveryNotDefined:
+	case RecoveryAutomatic:
+	case RecoveryUser:
+	case RecoveryIrrecoverable:
+	}
+	return true
+}
+
+func (in ValueDensity) isValid() bool {
+	switch in {
+	default:
+		return false
+	case ValueDensityNotDefined:
+	case ValueDensityDiffuse:
+	case ValueDensityConcentrated:
+	}
+	return true
+}
+
+func (in VulnerabilityResponseEffort) isValid() bool {
+	switch in {
+	default:
+		return false
+	case VulnerabilityResponseEffortNotDefined:
+	case VulnerabilityResponseEffortLow:
+	case VulnerabilityResponseEffortModerate:
+	case VulnerabilityResponseEffortHigh:
+	}
+	return true
+}
+
+func (in ProviderUrgency) isValid() bool {
+	switch in {
+	default:
+		return false
+	case ProviderUrgencyNotDefined:
+	case ProviderUrgencyClear:
+	case ProviderUrgencyGreen:
+	case ProviderUrgencyAmber:
+	case ProviderUrgencyRed:
+	}
+	return true
+}
@@ -0,0 +1,312 @@
+package cvssv4
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	type output struct {
+		r   Vector
+		err error
+	}
+	var testCases = []struct {
+		given    string
+		expected output
+	}{
+		{
+			given: DefaultVector().String(),
+			expected: output{
+				r: DefaultVector(),
+			},
+		},
+		{
+			given: "CVSS:4.0/AV:N/AC:L/AT:P/PR:N/UI:P/VC:H/VI:H/VA:H/SC:L/SI:L/SA:N" +
+				"/E:P/CR:L/MAV:A/MSI:S/S:P/AU:Y/R:U/V:C/RE:M/U:Amber",
+			expected: output{
+				r: Vector{
+					Version: Version40,
+					BasicMetrics: BasicMetrics{
+						AttackVector:                    AttackVectorNetwork,
+						AttackComplexity:                AttackComplexityLow,
+						AttackRequirements:              AttackRequirementsPresent,
+						PrivilegesRequired:              PrivilegesRequiredNone,
+						UserInteraction:                 UserInteractionPassive,
+						VulnerableSystemConfidentiality: VulnerableSystemImpactHigh,
+						VulnerableSystemIntegrity:       VulnerableSystemImpactHigh,
+						VulnerableSystemAvailability:    VulnerableSystemImpactHigh,
+						SubsequentSystemConfidentiality: SubsequentSystemImpactLow,
+						SubsequentSystemIntegrity:       SubsequentSystemImpactLow,
+						SubsequentSystemAvailability:    SubsequentSystemImpactNone,
+					},
+					ThreatMetrics: ThreatMetrics{
+						ExploitMaturity: ExploitMaturityProofOfConcept,
+					},
+					EnvironmentalMetrics: EnvironmentalMetrics{
+						ConfidentialityRequirement:              SecurityRequirementLow,
+						IntegrityRequirement:                    SecurityRequirementNotDefined,
+						AvailabilityRequirement:                 SecurityRequirementNotDefined,
+						ModifiedAttackVector:                    AttackVectorAdjacent,
+						ModifiedAttackComplexity:                AttackComplexityNotDefined,
+						ModifiedAttackRequirements:              AttackRequirementsNotDefined,
+						ModifiedPrivilegesRequired:              PrivilegesRequiredNotDefined,
+						ModifiedUserInteraction:                 UserInteractionNotDefined,
+						ModifiedVulnerableSystemConfidentiality: VulnerableSystemImpactNotDefined,
+						ModifiedVulnerableSystemIntegrity:       VulnerableSystemImpactNotDefined,
+						ModifiedVulnerableSystemAvailability:    VulnerableSystemImpactNotDefined,
+						ModifiedSubsequentSystemConfidentiality: SubsequentSystemImpactNotDefined,
+						ModifiedSubsequentSystemIntegrity:       SubsequentSystemImpactSafety,
+						ModifiedSubsequentSystemAvailability:    SubsequentSystemImpactNotDefined,
+					},
+					SupplementalMetrics: SupplementalMetrics{
+						Safety:                      SafetyPresent,
+						Automatable:                 AutomatableYes,
+						Recovery:                    RecoveryUser,
+						ValueDensity:                ValueDensityConcentrated,
+						VulnerabilityResponseEffort: VulnerabilityResponseEffortModerate,
+						ProviderUrgency:             ProviderUrgencyAmber,
+					},
+				},
+			},
+		},
+		{
+			given: "AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+			expected: output{
+				err: errors.New("illegal CVSS(V4) vector: AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N"),
+			},
+		},
+		{
+			given: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA",
+			expected: output{
+				err: errors.New("incomplete CVSS(V4) vector: CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA"),
+			},
+		},
+		{
+			given: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:S/SA:N",
+			expected: output{
+				err: errors.New("undefined mandatory metric 'SI' in CVSS(V4) vector: CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:S/SA:N"),
+			},
+		},
+		{
+			given: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/E:A",
+			expected: output{
+				err: errors.New("'E' is not mandatory metric in CVSS(V4) vector: CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/E:A"),
+			},
+		},
+		{
+			given: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/MSC:S",
+			expected: output{
+				err: errors.New("invalid value 'S' of metric 'MSC' in CVSS(V4) vector: CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/MSC:S"),
+			},
+		},
+		{
+			given: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/RL:W",
+			expected: output{
+				err: errors.New("unknown metric 'RL' in CVSS(V4) vector: CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/RL:W"),
+			},
+		},
+	}
+	for _, c := range testCases {
+		var actual output
+		actual.r, actual.err = Parse(c.given)
+		if c.expected.err != nil {
+			if fmt.Sprint(actual.err) != fmt.Sprint(c.expected.err) {
+				t.Errorf("Parse(%s) == %v, but got %v",
+					c.given, c.expected.err, actual.err)
+			}
+		} else {
+			if c.expected.r != actual.r {
+				t.Errorf("Parse(%s) == %v, but got %v",
+					c.given, c.expected.r, actual.r)
+			}
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	type output struct {
+		macroVector           string
+		nomenclature          string
+		baseScore             float64
+		baseSeverity          string
+		temporalScore         float64
+		temporalSeverity      string
+		environmentalScore    float64
+		environmentalSeverity string
+	}
+	var testCases = []struct {
+		given    string
+		expected output
+	}{
+		// highest severity vector of the MacroVector, https://www.first.org/cvss/calculator/4.0#CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N.
+		{
+			given: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+			expected: output{
+				macroVector:           "000200",
+				nomenclature:          "CVSS-B",
+				baseScore:             9.3,
+				baseSeverity:          SeverityCritical,
+				temporalScore:         9.3,
+				temporalSeverity:      SeverityCritical,
+				environmentalScore:    9.3,
+				environmentalSeverity: SeverityCritical,
+			},
+		},
+		// interpolated, https://www.first.org/cvss/calculator/4.0#CVSS:4.0/AV:L/AC:L/AT:N/PR:L/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N.
+		{
+			given: "CVSS:4.0/AV:L/AC:L/AT:N/PR:L/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+			expected: output{
+				macroVector:           "100200",
+				nomenclature:          "CVSS-B",
+				baseScore:             8.5,
+				baseSeverity:          SeverityHigh,
+				temporalScore:         8.5,
+				temporalSeverity:      SeverityHigh,
+				environmentalScore:    8.5,
+				environmentalSeverity: SeverityHigh,
+			},
+		},
+		// https://www.first.org/cvss/calculator/4.0#CVSS:4.0/AV:N/AC:L/AT:P/PR:N/UI:P/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N.
+		{
+			given: "CVSS:4.0/AV:N/AC:L/AT:P/PR:N/UI:P/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+			expected: output{
+				macroVector:           "110200",
+				nomenclature:          "CVSS-B",
+				baseScore:             7.7,
+				baseSeverity:          SeverityHigh,
+				temporalScore:         7.7,
+				temporalSeverity:      SeverityHigh,
+				environmentalScore:    7.7,
+				environmentalSeverity: SeverityHigh,
+			},
+		},
+		// no lower MacroVector of EQ3 and EQ6 and EQ4, https://www.first.org/cvss/calculator/4.0#CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:L/VI:N/VA:N/SC:N/SI:N/SA:N.
+		{
+			given: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:L/VI:N/VA:N/SC:N/SI:N/SA:N",
+			expected: output{
+				macroVector:           "002201",
+				nomenclature:          "CVSS-B",
+				baseScore:             6.9,
+				baseSeverity:          SeverityMedium,
+				temporalScore:         6.9,
+				temporalSeverity:      SeverityMedium,
+				environmentalScore:    6.9,
+				environmentalSeverity: SeverityMedium,
+			},
+		},
+		// threat downgrade, https://www.first.org/cvss/calculator/4.0#CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/E:U.
+		{
+			given: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/E:U",
+			expected: output{
+				macroVector:           "000220",
+				nomenclature:          "CVSS-BT",
+				baseScore:             9.3,
+				baseSeverity:          SeverityCritical,
+				temporalScore:         8.1,
+				temporalSeverity:      SeverityHigh,
+				environmentalScore:    8.1,
+				environmentalSeverity: SeverityHigh,
+			},
+		},
+		// environmental upgrade with safety, https://www.first.org/cvss/calculator/4.0#CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/E:P/CR:L/MSI:S/S:P/U:Amber.
+		{
+			given: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/E:P/CR:L/MSI:S/S:P/U:Amber",
+			expected: output{
+				macroVector:           "000010",
+				nomenclature:          "CVSS-BTE",
+				baseScore:             9.3,
+				baseSeverity:          SeverityCritical,
+				temporalScore:         8.9,
+				temporalSeverity:      SeverityHigh,
+				environmentalScore:    9.7,
+				environmentalSeverity: SeverityCritical,
+			},
+		},
+		// no impact, https://www.first.org/cvss/calculator/4.0#CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:N/VA:N/SC:N/SI:N/SA:N/MVC:N.
+		{
+			given: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:N/VA:N/SC:N/SI:N/SA:N/MVC:N",
+			expected: output{
+				macroVector:           "002201",
+				nomenclature:          "CVSS-BE",
+				baseScore:             8.7,
+				baseSeverity:          SeverityHigh,
+				temporalScore:         8.7,
+				temporalSeverity:      SeverityHigh,
+				environmentalScore:    0,
+				environmentalSeverity: SeverityNone,
+			},
+		},
+	}
+	for _, c := range testCases {
+		var actual output
+		var v = ShouldParse(c.given)
+		actual.macroVector = v.MacroVector()
+		actual.nomenclature = v.Nomenclature()
+		actual.baseScore, actual.baseSeverity, actual.temporalScore, actual.temporalSeverity, actual.environmentalScore, actual.environmentalSeverity = v.ScoreAndSeverity()
+		if actual != c.expected {
+			t.Errorf("socres of %s == %#v, but got %#v", c.given, c.expected, actual)
+		}
+	}
+}
+
+func TestVector_String(t *testing.T) {
+	var testCases = []struct {
+		given    Vector
+		expected string
+	}{
+		{
+			given:    DefaultVector(),
+			expected: "CVSS:4.0/AV:P/AC:H/AT:P/PR:H/UI:A/VC:N/VI:N/VA:N/SC:N/SI:N/SA:N",
+		},
+		{
+			given: Vector{
+				BasicMetrics: BasicMetrics{
+					AttackVector:                 AttackVectorNetwork,
+					VulnerableSystemAvailability: VulnerableSystemImpactHigh,
+				},
+				EnvironmentalMetrics: EnvironmentalMetrics{
+					ModifiedSubsequentSystemAvailability: SubsequentSystemImpactSafety,
+				},
+				SupplementalMetrics: SupplementalMetrics{
+					ProviderUrgency: ProviderUrgencyRed,
+				},
+			},
+			expected: "CVSS:4.0/AV:N/AC:H/AT:P/PR:H/UI:A/VC:N/VI:N/VA:H/SC:N/SI:N/SA:N/MSA:S/U:Red",
+		},
+	}
+	for i, c := range testCases {
+		var actual = c.given.String()
+		if actual != c.expected {
+			t.Errorf("#%d expected %s, but got %s", i+1, c.expected, actual)
+		}
+	}
+}
+
+func TestVector_Override(t *testing.T) {
+	var testCases = []struct {
+		given    [2]string
+		expected string
+	}{
+		{
+			given: [2]string{
+				"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/E:U",
+				"CVSS:4.0/AV:L/AC:L/AT:N/PR:L/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/CR:H",
+			},
+			expected: "CVSS:4.0/AV:L/AC:L/AT:N/PR:L/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/CR:H",
+		},
+		{
+			given: [2]string{
+				"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/E:U",
+				"",
+			},
+			expected: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/E:U",
+		},
+	}
+	for i, c := range testCases {
+		var actual = ShouldParse(c.given[0]).Override(ShouldParse(c.given[1])).String()
+		if actual != c.expected {
+			t.Errorf("#%d expected %s, but got %s", i+1, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package cvss
+
+import (
+	"math"
+
+	"github.com/seal-io/meta-api/cvss/compatible"
+	"github.com/seal-io/meta-api/cvss/cvssv4"
+)
+
+// Difference holds the changes from a CVSS vector to another CVSS vector.
+type Difference struct {
+	// Converted is true if the vectors are in different versions,
+	// and both of them are converted to the same version before comparing metrics.
+	Converted bool
+	// Metrics is the changed metrics in order of the vector string.
+	Metrics []MetricChange
+	// Base is the change of the base score and severity.
+	Base ScoreChange
+	// Temporal is the change of the temporal score and severity.
+	Temporal ScoreChange
+	// Environmental is the change of the environmental score and severity.
+	Environmental ScoreChange
+}
+
+// MetricChange holds the change of a metric.
+type MetricChange struct {
+	// Abbreviation is the abbreviated metric name, e.g. AV.
+	Abbreviation string
+	// From is the metric of the original vector,
+	// it is empty if the original vector doesn't have this metric.
+	From compatible.Metric
+	// To is the metric of the changed vector,
+	// it is empty if the changed vector doesn't have this metric.
+	To compatible.Metric
+}
+
+// ScoreChange holds the change of a score and its severity.
+type ScoreChange struct {
+	FromScore    float64
+	FromSeverity string
+	ToScore      float64
+	ToSeverity   string
+	// Delta is the difference of the scores, positive means the score is raised.
+	Delta float64
+}
+
+// SeverityDelta returns the difference of the severity numbers,
+// positive means the severity is raised.
+func (in ScoreChange) SeverityDelta() int {
+	return GetSeverityNumber(in.ToSeverity) - GetSeverityNumber(in.FromSeverity)
+}
+
+// SeverityCrossed returns true if the score crossed a severity threshold.
+func (in ScoreChange) SeverityCrossed() bool {
+	return in.SeverityDelta() != 0
+}
+
+// IsZero returns true if there is no change of the metrics and the scores.
+func (in Difference) IsZero() bool {
+	return len(in.Metrics) == 0 &&
+		in.Base.Delta == 0 && in.Temporal.Delta == 0 && in.Environmental.Delta == 0 &&
+		!in.SeverityCrossed()
+}
+
+// SeverityCrossed returns true if any of the base, temporal and environmental severities crossed a threshold.
+func (in Difference) SeverityCrossed() bool {
+	return in.Base.SeverityCrossed() || in.Temporal.SeverityCrossed() || in.Environmental.SeverityCrossed()
+}
+
+// Diff returns the Difference from the given vector a to the given vector b,
+// the scores are compared in their own versions,
+// the metrics are compared after converting to the same version if the versions are different,
+// which is CVSS(V4.0) if either of them is CVSS(V4.0), otherwise, the latest version of CVSS(V3).
+// A nil vector is treated as a vector without any metric and score.
+func Diff(a, b compatible.Vector) (d Difference) {
+	d.Base = diffScore(a, b, func(v compatible.Vector) (float64, string) {
+		return v.BaseScoreAndSeverity()
+	})
+	d.Temporal = diffScore(a, b, func(v compatible.Vector) (float64, string) {
+		return v.TemporalScoreAndSeverity()
+	})
+	d.Environmental = diffScore(a, b, func(v compatible.Vector) (float64, string) {
+		return v.EnvironmentalScoreAndSeverity()
+	})
+
+	if a != nil && b != nil && a.GetVersion() != b.GetVersion() {
+		d.Converted = true
+		a, b = align(a, b)
+	}
+	var ams, bms []compatible.Metric
+	if a != nil {
+		ams = a.Metrics()
+	}
+	if b != nil {
+		bms = b.Metrics()
+	}
+	var ami = make(map[string]compatible.Metric, len(ams))
+	for i := range ams {
+		ami[ams[i].Abbreviation] = ams[i]
+	}
+	var bmi = make(map[string]compatible.Metric, len(bms))
+	for i := range bms {
+		bmi[bms[i].Abbreviation] = bms[i]
+	}
+	for i := range bms {
+		var am = ami[bms[i].Abbreviation]
+		if am.Value == bms[i].Value {
+			continue
+		}
+		d.Metrics = append(d.Metrics, MetricChange{
+			Abbreviation: bms[i].Abbreviation,
+			From:         am,
+			To:           bms[i],
+		})
+	}
+	for i := range ams {
+		if _, exist := bmi[ams[i].Abbreviation]; exist {
+			continue
+		}
+		d.Metrics = append(d.Metrics, MetricChange{
+			Abbreviation: ams[i].Abbreviation,
+			From:         ams[i],
+		})
+	}
+	return
+}
+
+// align converts the given vectors in different versions to the same version,
+// NB: the latest version of CVSS(V3) is not convertible to CVSS(V4.0) by compatible.Vector.ToLatest.
+func align(a, b compatible.Vector) (compatible.Vector, compatible.Vector) {
+	var v40 = string(cvssv4.Version40)
+	if a.GetVersion() != v40 && b.GetVersion() != v40 {
+		return a.ToLatest(), b.ToLatest()
+	}
+	if c, _, err := Convert(a, v40); err == nil {
+		a = c
+	}
+	if c, _, err := Convert(b, v40); err == nil {
+		b = c
+	}
+	return a, b
+}
+
+func diffScore(a, b compatible.Vector, get func(compatible.Vector) (float64, string)) (r ScoreChange) {
+	if a != nil {
+		r.FromScore, r.FromSeverity = get(a)
+	}
+	if b != nil {
+		r.ToScore, r.ToSeverity = get(b)
+	}
+	// NB: avoid the floating error, e.g. 7.5 - 7.2 is represented as 0.2999999.
+	r.Delta = math.Round((r.ToScore-r.FromScore)*10) / 10
+	return
+}
@@ -0,0 +1,163 @@
+package cvss
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/seal-io/meta-api/cvss/compatible"
+)
+
+func TestDiff(t *testing.T) {
+	type input struct {
+		a compatible.Vector
+		b compatible.Vector
+	}
+	type output struct {
+		converted       bool
+		metrics         string
+		base            ScoreChange
+		severityCrossed bool
+		isZero          bool
+	}
+	var testCases = []struct {
+		given    input
+		expected output
+	}{
+		{
+			given: input{
+				a: ShouldParse("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"),
+				b: ShouldParse("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"),
+			},
+			expected: output{
+				metrics: "[]",
+				base: ScoreChange{
+					FromScore:    9.8,
+					FromSeverity: "CRITICAL",
+					ToScore:      9.8,
+					ToSeverity:   "CRITICAL",
+				},
+				isZero: true,
+			},
+		},
+		{
+			given: input{
+				a: ShouldParse("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"),
+				b: ShouldParse("CVSS:3.1/AV:L/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/E:P"),
+			},
+			expected: output{
+				metrics: "[AV:N->L E:X->P]",
+				base: ScoreChange{
+					FromScore:    9.8,
+					FromSeverity: "CRITICAL",
+					ToScore:      8.4,
+					ToSeverity:   "HIGH",
+					Delta:        -1.4,
+				},
+				severityCrossed: true,
+			},
+		},
+		{
+			given: input{
+				a: ShouldParse("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:L"),
+				b: ShouldParse("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"),
+			},
+			expected: output{
+				metrics: "[A:L->H]",
+				base: ScoreChange{
+					FromScore:    9.4,
+					FromSeverity: "CRITICAL",
+					ToScore:      9.8,
+					ToSeverity:   "CRITICAL",
+					Delta:        0.4,
+				},
+			},
+		},
+		{
+			given: input{
+				a: ShouldParse("AV:N/AC:L/Au:N/C:P/I:P/A:P"),
+				b: ShouldParse("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"),
+			},
+			expected: output{
+				converted: true,
+				metrics:   "[]",
+				base: ScoreChange{
+					FromScore:    7.5,
+					FromSeverity: "HIGH",
+					ToScore:      9.8,
+					ToSeverity:   "CRITICAL",
+					Delta:        2.3,
+				},
+				severityCrossed: true,
+			},
+		},
+		{
+			given: input{
+				a: ShouldParse("CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"),
+				b: ShouldParse("CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:U/C:H/I:H/A:H"),
+			},
+			expected: output{
+				converted: true,
+				metrics:   "[PR:N->L]",
+				base: ScoreChange{
+					FromScore:    9.8,
+					FromSeverity: "CRITICAL",
+					ToScore:      8.8,
+					ToSeverity:   "HIGH",
+					Delta:        -1,
+				},
+				severityCrossed: true,
+			},
+		},
+		{
+			given: input{
+				a: ShouldParse("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"),
+				b: ShouldParse("CVSS:4.0/AV:L/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N"),
+			},
+			expected: output{
+				converted: true,
+				metrics:   "[AV:N->L]",
+				base: ScoreChange{
+					FromScore:    9.8,
+					FromSeverity: "CRITICAL",
+					ToScore:      8.6,
+					ToSeverity:   "HIGH",
+					Delta:        -1.2,
+				},
+				severityCrossed: true,
+			},
+		},
+		{
+			given: input{
+				b: ShouldParse("CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N"),
+			},
+			expected: output{
+				metrics: "[AV:->N AC:->L AT:->N PR:->N UI:->N VC:->H VI:->H VA:->H SC:->N SI:->N SA:->N " +
+					"E:->X CR:->X IR:->X AR:->X MAV:->X MAC:->X MAT:->X MPR:->X MUI:->X MVC:->X MVI:->X MVA:->X " +
+					"MSC:->X MSI:->X MSA:->X S:->X AU:->X R:->X V:->X RE:->X U:->X]",
+				base: ScoreChange{
+					ToScore:    9.3,
+					ToSeverity: "CRITICAL",
+					Delta:      9.3,
+				},
+				severityCrossed: true,
+			},
+		},
+	}
+	for _, c := range testCases {
+		var d = Diff(c.given.a, c.given.b)
+		var ms = make([]string, 0, len(d.Metrics))
+		for _, m := range d.Metrics {
+			ms = append(ms, fmt.Sprintf("%s:%s->%s", m.Abbreviation, m.From.Value, m.To.Value))
+		}
+		var actual = output{
+			converted:       d.Converted,
+			metrics:         fmt.Sprint(ms),
+			base:            d.Base,
+			severityCrossed: d.SeverityCrossed(),
+			isZero:          d.IsZero(),
+		}
+		if actual != c.expected {
+			t.Errorf("Diff(%v, %v) == %#v, but got %#v", c.given.a, c.given.b, c.expected, actual)
+		}
+	}
+}
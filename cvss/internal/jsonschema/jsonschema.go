@@ -0,0 +1,174 @@
+// Package jsonschema provides a minimal JSON schema validator,
+// which only supports the keywords used by the FIRST CVSS JSON schemas,
+// e.g. https://www.first.org/cvss/cvss-v3.1.json.
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Validate validates the given JSON document against the given JSON schema,
+// returns error if the document is invalid or the schema uses any unsupported keyword.
+func Validate(schema, doc []byte) error {
+	var s map[string]any
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return fmt.Errorf("error unmarshalling schema: %w", err)
+	}
+	var d any
+	if err := json.Unmarshal(doc, &d); err != nil {
+		return fmt.Errorf("error unmarshalling document: %w", err)
+	}
+	var v = validator{root: s}
+	v.validate(s, d, "$")
+	if len(v.errs) != 0 {
+		return errors.New(strings.Join(v.errs, "; "))
+	}
+	return nil
+}
+
+type validator struct {
+	root map[string]any
+	errs []string
+}
+
+func (in *validator) errorf(format string, args ...any) {
+	in.errs = append(in.errs, fmt.Sprintf(format, args...))
+}
+
+func (in *validator) validate(s map[string]any, d any, path string) {
+	var keys = make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		switch k {
+		default:
+			in.errorf("%s: unsupported keyword '%s'", path, k)
+		case "$schema", "$id", "id", "title", "description", "license", "definitions":
+		case "$ref":
+			var ref, _ = s[k].(string)
+			var rs, ok = in.resolve(ref)
+			if !ok {
+				in.errorf("%s: unresolved reference '%s'", path, ref)
+				continue
+			}
+			in.validate(rs, d, path)
+		case "type":
+			var t, _ = s[k].(string)
+			if !isType(d, t) {
+				in.errorf("%s: %v is not %s", path, d, t)
+			}
+		case "enum":
+			var es, _ = s[k].([]any)
+			var found bool
+			for i := range es {
+				if es[i] == d {
+					found = true
+					break
+				}
+			}
+			if !found {
+				in.errorf("%s: %v is not one of %v", path, d, es)
+			}
+		case "pattern":
+			var p, _ = s[k].(string)
+			var str, ok = d.(string)
+			if !ok {
+				continue
+			}
+			var r, err = regexp.Compile(p)
+			if err != nil {
+				in.errorf("%s: invalid pattern '%s': %v", path, p, err)
+				continue
+			}
+			if !r.MatchString(str) {
+				in.errorf("%s: '%s' does not match pattern '%s'", path, str, p)
+			}
+		case "minimum":
+			var m, _ = s[k].(float64)
+			if n, ok := d.(float64); ok && n < m {
+				in.errorf("%s: %v is less than %v", path, n, m)
+			}
+		case "maximum":
+			var m, _ = s[k].(float64)
+			if n, ok := d.(float64); ok && n > m {
+				in.errorf("%s: %v is greater than %v", path, n, m)
+			}
+		case "required":
+			var rs, _ = s[k].([]any)
+			var o, ok = d.(map[string]any)
+			if !ok {
+				continue
+			}
+			for i := range rs {
+				var n, _ = rs[i].(string)
+				if _, exist := o[n]; !exist {
+					in.errorf("%s: missing required property '%s'", path, n)
+				}
+			}
+		case "properties":
+			var ps, _ = s[k].(map[string]any)
+			var o, ok = d.(map[string]any)
+			if !ok {
+				continue
+			}
+			for n, pv := range o {
+				var sub, ok = ps[n].(map[string]any)
+				if !ok {
+					continue
+				}
+				in.validate(sub, pv, path+"."+n)
+			}
+		}
+	}
+}
+
+// resolve returns the sub schema of the given local reference, e.g. #/definitions/scoreType.
+func (in *validator) resolve(ref string) (map[string]any, bool) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, false
+	}
+	var s = in.root
+	for _, n := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		var ok bool
+		s, ok = s[n].(map[string]any)
+		if !ok {
+			return nil, false
+		}
+	}
+	return s, true
+}
+
+func isType(d any, t string) bool {
+	switch t {
+	case "object":
+		var _, ok = d.(map[string]any)
+		return ok
+	case "array":
+		var _, ok = d.([]any)
+		return ok
+	case "string":
+		var _, ok = d.(string)
+		return ok
+	case "number":
+		var _, ok = d.(float64)
+		return ok
+	case "integer":
+		var n, ok = d.(float64)
+		return ok && n == math.Trunc(n)
+	case "boolean":
+		var _, ok = d.(bool)
+		return ok
+	case "null":
+		return d == nil
+	}
+	return false
+}
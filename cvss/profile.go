@@ -0,0 +1,270 @@
+package cvss
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/seal-io/meta-api/cvss/compatible"
+	"github.com/seal-io/meta-api/cvss/cvssv2"
+	"github.com/seal-io/meta-api/cvss/cvssv3"
+	"github.com/seal-io/meta-api/cvss/cvssv4"
+)
+
+// Profile holds the environmental metrics of an asset, e.g. the internal systems,
+// which can be applied to any version CVSS vector.
+//
+// The metric values are in the abbreviations of the vector string, e.g. H,
+// and the empty value means keeping the metric of the applied vector.
+// The metrics are mapped to the applied vector as below,
+//   - ConfidentialityRequirement, IntegrityRequirement and AvailabilityRequirement apply to all versions.
+//   - CollateralDamagePotential and TargetDistribution only apply to CVSS(V2).
+//   - ModifiedScope only applies to CVSS(V3).
+//   - ModifiedAttackRequirements and ModifiedSub*Impact only apply to CVSS(V4),
+//     ModifiedAttackRequirements:P is mapped to ModifiedAttackComplexity:H of CVSS(V3)
+//     if ModifiedAttackComplexity is not specified.
+//   - ModifiedUserInteraction:R is mapped to P of CVSS(V4),
+//     and ModifiedUserInteraction:P/A is mapped to R of CVSS(V3).
+//   - The modified base metrics are ignored by CVSS(V2), which doesn't define them.
+type Profile struct {
+	// Name is the name of the profile, e.g. internal.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// security requirements
+	ConfidentialityRequirement string `json:"confidentialityRequirement,omitempty" yaml:"confidentialityRequirement,omitempty"`
+	IntegrityRequirement       string `json:"integrityRequirement,omitempty" yaml:"integrityRequirement,omitempty"`
+	AvailabilityRequirement    string `json:"availabilityRequirement,omitempty" yaml:"availabilityRequirement,omitempty"`
+
+	// modified base metrics
+	ModifiedAttackVector             string `json:"modifiedAttackVector,omitempty" yaml:"modifiedAttackVector,omitempty"`
+	ModifiedAttackComplexity         string `json:"modifiedAttackComplexity,omitempty" yaml:"modifiedAttackComplexity,omitempty"`
+	ModifiedAttackRequirements       string `json:"modifiedAttackRequirements,omitempty" yaml:"modifiedAttackRequirements,omitempty"`
+	ModifiedPrivilegesRequired       string `json:"modifiedPrivilegesRequired,omitempty" yaml:"modifiedPrivilegesRequired,omitempty"`
+	ModifiedUserInteraction          string `json:"modifiedUserInteraction,omitempty" yaml:"modifiedUserInteraction,omitempty"`
+	ModifiedScope                    string `json:"modifiedScope,omitempty" yaml:"modifiedScope,omitempty"`
+	ModifiedConfidentialityImpact    string `json:"modifiedConfidentialityImpact,omitempty" yaml:"modifiedConfidentialityImpact,omitempty"`
+	ModifiedIntegrityImpact          string `json:"modifiedIntegrityImpact,omitempty" yaml:"modifiedIntegrityImpact,omitempty"`
+	ModifiedAvailabilityImpact       string `json:"modifiedAvailabilityImpact,omitempty" yaml:"modifiedAvailabilityImpact,omitempty"`
+	ModifiedSubConfidentialityImpact string `json:"modifiedSubConfidentialityImpact,omitempty" yaml:"modifiedSubConfidentialityImpact,omitempty"`
+	ModifiedSubIntegrityImpact       string `json:"modifiedSubIntegrityImpact,omitempty" yaml:"modifiedSubIntegrityImpact,omitempty"`
+	ModifiedSubAvailabilityImpact    string `json:"modifiedSubAvailabilityImpact,omitempty" yaml:"modifiedSubAvailabilityImpact,omitempty"`
+
+	// CVSS(V2) environmental metrics
+	CollateralDamagePotential string `json:"collateralDamagePotential,omitempty" yaml:"collateralDamagePotential,omitempty"`
+	TargetDistribution        string `json:"targetDistribution,omitempty" yaml:"targetDistribution,omitempty"`
+}
+
+// LoadProfile loads Profile from the given file,
+// the file is decoded as JSON if the extension is .json, otherwise as YAML.
+func LoadProfile(filename string) (Profile, error) {
+	var bs, err = os.ReadFile(filename)
+	if err != nil {
+		return Profile{}, fmt.Errorf("error reading profile %s: %w", filename, err)
+	}
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		return decodeProfile(bs, json.Unmarshal)
+	}
+	return decodeProfile(bs, yaml.Unmarshal)
+}
+
+// ParseProfile parses Profile from the given YAML or JSON content.
+func ParseProfile(bs []byte) (Profile, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(bs), []byte("{")) {
+		return decodeProfile(bs, json.Unmarshal)
+	}
+	return decodeProfile(bs, yaml.Unmarshal)
+}
+
+func decodeProfile(bs []byte, unmarshal func([]byte, any) error) (Profile, error) {
+	var p Profile
+	var err = unmarshal(bs, &p)
+	if err != nil {
+		return Profile{}, fmt.Errorf("error decoding profile: %w", err)
+	}
+	p = p.normalize()
+	if err = p.Validate(); err != nil {
+		return Profile{}, err
+	}
+	return p, nil
+}
+
+// Validate returns error if any metric value of this Profile is invalid.
+func (in Profile) Validate() error {
+	var p = in.normalize()
+	for _, m := range []struct {
+		name   string
+		value  string
+		values string
+	}{
+		{"confidentialityRequirement", p.ConfidentialityRequirement, "X/L/M/H"},
+		{"integrityRequirement", p.IntegrityRequirement, "X/L/M/H"},
+		{"availabilityRequirement", p.AvailabilityRequirement, "X/L/M/H"},
+		{"modifiedAttackVector", p.ModifiedAttackVector, "X/N/A/L/P"},
+		{"modifiedAttackComplexity", p.ModifiedAttackComplexity, "X/L/H"},
+		{"modifiedAttackRequirements", p.ModifiedAttackRequirements, "X/N/P"},
+		{"modifiedPrivilegesRequired", p.ModifiedPrivilegesRequired, "X/N/L/H"},
+		{"modifiedUserInteraction", p.ModifiedUserInteraction, "X/N/R/P/A"},
+		{"modifiedScope", p.ModifiedScope, "X/U/C"},
+		{"modifiedConfidentialityImpact", p.ModifiedConfidentialityImpact, "X/N/L/H"},
+		{"modifiedIntegrityImpact", p.ModifiedIntegrityImpact, "X/N/L/H"},
+		{"modifiedAvailabilityImpact", p.ModifiedAvailabilityImpact, "X/N/L/H"},
+		{"modifiedSubConfidentialityImpact", p.ModifiedSubConfidentialityImpact, "X/N/L/H"},
+		{"modifiedSubIntegrityImpact", p.ModifiedSubIntegrityImpact, "X/N/L/H/S"},
+		{"modifiedSubAvailabilityImpact", p.ModifiedSubAvailabilityImpact, "X/N/L/H/S"},
+		{"collateralDamagePotential", p.CollateralDamagePotential, "X/N/L/LM/MH/H"},
+		{"targetDistribution", p.TargetDistribution, "X/N/L/M/H"},
+	} {
+		if m.value == "" {
+			continue
+		}
+		if !contains(strings.Split(m.values, "/"), m.value) {
+			return fmt.Errorf("invalid value '%s' of metric '%s' in profile %s: expected one of %s",
+				m.value, m.name, in.Name, m.values)
+		}
+	}
+	return nil
+}
+
+// Apply merges this Profile into the given vector,
+// the given vector is returned without changes if it is not a CVSS(V2), CVSS(V3) or CVSS(V4) vector.
+func (in Profile) Apply(v compatible.Vector) compatible.Vector {
+	var p = in.normalize()
+	switch t := v.(type) {
+	case cvssv2.Vector:
+		return t.Override(p.toV2())
+	case cvssv3.Vector:
+		return t.Override(p.toV3())
+	case cvssv4.Vector:
+		return t.Override(p.toV4())
+	}
+	return v
+}
+
+// Rescoring holds the environmental score of a vector which is applied a Profile.
+type Rescoring struct {
+	// Vector is the applied vector.
+	Vector compatible.Vector
+	// Score is the environmental score of the applied vector.
+	Score float64
+	// Severity is the environmental severity of the applied vector.
+	Severity string
+}
+
+// Rescore applies this Profile to each of the given vectors,
+// and recomputes their environmental scores, the nil vector is rescored as 0.
+func (in Profile) Rescore(vs []compatible.Vector) []Rescoring {
+	var r = make([]Rescoring, len(vs))
+	for i := range vs {
+		if vs[i] == nil {
+			continue
+		}
+		var v = in.Apply(vs[i])
+		var s, sv = v.EnvironmentalScoreAndSeverity()
+		r[i] = Rescoring{Vector: v, Score: s, Severity: sv}
+	}
+	return r
+}
+
+func (in Profile) normalize() Profile {
+	var out = in
+	for _, s := range []*string{
+		&out.ConfidentialityRequirement,
+		&out.IntegrityRequirement,
+		&out.AvailabilityRequirement,
+		&out.ModifiedAttackVector,
+		&out.ModifiedAttackComplexity,
+		&out.ModifiedAttackRequirements,
+		&out.ModifiedPrivilegesRequired,
+		&out.ModifiedUserInteraction,
+		&out.ModifiedScope,
+		&out.ModifiedConfidentialityImpact,
+		&out.ModifiedIntegrityImpact,
+		&out.ModifiedAvailabilityImpact,
+		&out.ModifiedSubConfidentialityImpact,
+		&out.ModifiedSubIntegrityImpact,
+		&out.ModifiedSubAvailabilityImpact,
+		&out.CollateralDamagePotential,
+		&out.TargetDistribution,
+	} {
+		*s = strings.ToUpper(strings.TrimSpace(*s))
+	}
+	return out
+}
+
+func (in Profile) toV2() (out cvssv2.Vector) {
+	// NB: CVSS(V2) uses ND as not defined.
+	var nd = func(s string) string {
+		if s == "X" {
+			return "ND"
+		}
+		return s
+	}
+	out.ConfidentialityRequirement = cvssv2.SecurityRequirement(nd(in.ConfidentialityRequirement))
+	out.IntegrityRequirement = cvssv2.SecurityRequirement(nd(in.IntegrityRequirement))
+	out.AvailabilityRequirement = cvssv2.SecurityRequirement(nd(in.AvailabilityRequirement))
+	out.CollateralDamagePotential = cvssv2.CollateralDamagePotential(nd(in.CollateralDamagePotential))
+	out.TargetDistribution = cvssv2.TargetDistribution(nd(in.TargetDistribution))
+	return
+}
+
+func (in Profile) toV3() (out cvssv3.Vector) {
+	out.ConfidentialityRequirement = cvssv3.SecurityRequirement(in.ConfidentialityRequirement)
+	out.IntegrityRequirement = cvssv3.SecurityRequirement(in.IntegrityRequirement)
+	out.AvailabilityRequirement = cvssv3.SecurityRequirement(in.AvailabilityRequirement)
+	out.ModifiedAttackVector = cvssv3.AttackVector(in.ModifiedAttackVector)
+	out.ModifiedAttackComplexity = cvssv3.AttackComplexity(in.ModifiedAttackComplexity)
+	if out.ModifiedAttackComplexity == "" && in.ModifiedAttackRequirements == "P" {
+		// NB: the attack requirements are part of the attack complexity in CVSS(V3).
+		out.ModifiedAttackComplexity = cvssv3.AttackComplexityHigh
+	}
+	out.ModifiedPrivilegesRequired = cvssv3.PrivilegesRequired(in.ModifiedPrivilegesRequired)
+	switch in.ModifiedUserInteraction {
+	case "P", "A":
+		out.ModifiedUserInteraction = cvssv3.UserInteractionRequired
+	default:
+		out.ModifiedUserInteraction = cvssv3.UserInteraction(in.ModifiedUserInteraction)
+	}
+	out.ModifiedScope = cvssv3.Scope(in.ModifiedScope)
+	out.ModifiedConfidentiality = cvssv3.ConfidentialityImpact(in.ModifiedConfidentialityImpact)
+	out.ModifiedIntegrity = cvssv3.IntegrityImpact(in.ModifiedIntegrityImpact)
+	out.ModifiedAvailability = cvssv3.AvailabilityImpact(in.ModifiedAvailabilityImpact)
+	return
+}
+
+func (in Profile) toV4() (out cvssv4.Vector) {
+	out.ConfidentialityRequirement = cvssv4.SecurityRequirement(in.ConfidentialityRequirement)
+	out.IntegrityRequirement = cvssv4.SecurityRequirement(in.IntegrityRequirement)
+	out.AvailabilityRequirement = cvssv4.SecurityRequirement(in.AvailabilityRequirement)
+	out.ModifiedAttackVector = cvssv4.AttackVector(in.ModifiedAttackVector)
+	out.ModifiedAttackComplexity = cvssv4.AttackComplexity(in.ModifiedAttackComplexity)
+	out.ModifiedAttackRequirements = cvssv4.AttackRequirements(in.ModifiedAttackRequirements)
+	out.ModifiedPrivilegesRequired = cvssv4.PrivilegesRequired(in.ModifiedPrivilegesRequired)
+	switch in.ModifiedUserInteraction {
+	case "R":
+		// NB: takes the more severe one of passive and active.
+		out.ModifiedUserInteraction = cvssv4.UserInteractionPassive
+	default:
+		out.ModifiedUserInteraction = cvssv4.UserInteraction(in.ModifiedUserInteraction)
+	}
+	out.ModifiedVulnerableSystemConfidentiality = cvssv4.VulnerableSystemImpact(in.ModifiedConfidentialityImpact)
+	out.ModifiedVulnerableSystemIntegrity = cvssv4.VulnerableSystemImpact(in.ModifiedIntegrityImpact)
+	out.ModifiedVulnerableSystemAvailability = cvssv4.VulnerableSystemImpact(in.ModifiedAvailabilityImpact)
+	out.ModifiedSubsequentSystemConfidentiality = cvssv4.SubsequentSystemImpact(in.ModifiedSubConfidentialityImpact)
+	out.ModifiedSubsequentSystemIntegrity = cvssv4.SubsequentSystemImpact(in.ModifiedSubIntegrityImpact)
+	out.ModifiedSubsequentSystemAvailability = cvssv4.SubsequentSystemImpact(in.ModifiedSubAvailabilityImpact)
+	return
+}
+
+func contains(ss []string, s string) bool {
+	for i := range ss {
+		if ss[i] == s {
+			return true
+		}
+	}
+	return false
+}
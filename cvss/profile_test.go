@@ -0,0 +1,168 @@
+package cvss
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/seal-io/meta-api/cvss/compatible"
+)
+
+func TestParseProfile(t *testing.T) {
+	type output struct {
+		r      Profile
+		hasErr bool
+	}
+	var testCases = []struct {
+		given    string
+		expected output
+	}{
+		{
+			given: `
+name: internal
+confidentialityRequirement: H
+integrityRequirement: h
+modifiedAttackVector: A
+`,
+			expected: output{
+				r: Profile{
+					Name:                       "internal",
+					ConfidentialityRequirement: "H",
+					IntegrityRequirement:       "H",
+					ModifiedAttackVector:       "A",
+				},
+			},
+		},
+		{
+			given: `{"name":"internal","confidentialityRequirement":"H","targetDistribution":"LM"}`,
+			expected: output{
+				hasErr: true,
+			},
+		},
+		{
+			given: `{"name":"dmz","availabilityRequirement":"L","collateralDamagePotential":"MH"}`,
+			expected: output{
+				r: Profile{
+					Name:                      "dmz",
+					AvailabilityRequirement:   "L",
+					CollateralDamagePotential: "MH",
+				},
+			},
+		},
+		{
+			given: `name: [internal`,
+			expected: output{
+				hasErr: true,
+			},
+		},
+	}
+	for _, c := range testCases {
+		var actual output
+		var err error
+		actual.r, err = ParseProfile([]byte(c.given))
+		actual.hasErr = err != nil
+		if actual != c.expected {
+			t.Errorf("ParseProfile(%s) == %#v, but got %#v, %v", c.given, c.expected, actual, err)
+		}
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	var dir = t.TempDir()
+	var testCases = []struct {
+		filename string
+		content  string
+	}{
+		{
+			filename: "internal.yaml",
+			content:  "name: internal\nconfidentialityRequirement: H\n",
+		},
+		{
+			filename: "internal.json",
+			content:  `{"name":"internal","confidentialityRequirement":"H"}`,
+		},
+	}
+	var expected = Profile{Name: "internal", ConfidentialityRequirement: "H"}
+	for _, c := range testCases {
+		var p = filepath.Join(dir, c.filename)
+		if err := os.WriteFile(p, []byte(c.content), 0o600); err != nil {
+			t.Fatalf("error writing %s: %v", p, err)
+		}
+		var actual, err = LoadProfile(p)
+		if err != nil || actual != expected {
+			t.Errorf("LoadProfile(%s) == %#v, but got %#v, %v", c.filename, expected, actual, err)
+		}
+	}
+	if _, err := LoadProfile(filepath.Join(dir, "none.yaml")); err == nil {
+		t.Errorf("LoadProfile(none.yaml) should return error")
+	}
+}
+
+func TestProfile_Apply(t *testing.T) {
+	var p = Profile{
+		Name:                       "internal",
+		ConfidentialityRequirement: "H",
+		IntegrityRequirement:       "H",
+		ModifiedAttackVector:       "A",
+		ModifiedAttackRequirements: "P",
+		ModifiedUserInteraction:    "R",
+		ModifiedSubIntegrityImpact: "S",
+		CollateralDamagePotential:  "LM",
+	}
+	var testCases = []struct {
+		given    string
+		expected string
+	}{
+		{
+			given:    "AV:N/AC:L/Au:N/C:P/I:P/A:P/CR:L",
+			expected: "AV:N/AC:L/Au:N/C:P/I:P/A:P/CDP:LM/CR:H/IR:H",
+		},
+		{
+			given:    "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/AR:L",
+			expected: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/CR:H/IR:H/AR:L/MAV:A/MAC:H/MUI:R",
+		},
+		{
+			given: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/E:P",
+			expected: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/E:P" +
+				"/CR:H/IR:H/MAV:A/MAT:P/MUI:P/MSI:S",
+		},
+	}
+	for _, c := range testCases {
+		var actual = p.Apply(ShouldParse(c.given)).String()
+		if actual != c.expected {
+			t.Errorf("Apply(%s) == %s, but got %s", c.given, c.expected, actual)
+		}
+	}
+}
+
+func TestProfile_Rescore(t *testing.T) {
+	var p = Profile{
+		Name:                       "internal",
+		ConfidentialityRequirement: "L",
+		IntegrityRequirement:       "L",
+		AvailabilityRequirement:    "L",
+		ModifiedAttackVector:       "A",
+	}
+	var given = []compatible.Vector{
+		ShouldParse("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"),
+		nil,
+		ShouldParse("AV:N/AC:L/Au:N/C:P/I:P/A:P"),
+	}
+	var actual = p.Rescore(given)
+	if len(actual) != len(given) {
+		t.Fatalf("Rescore() should return %d results, but got %d", len(given), len(actual))
+	}
+	for i := range given {
+		if given[i] == nil {
+			if actual[i].Vector != nil || actual[i].Score != 0 {
+				t.Errorf("Rescore() of nil vector should be zero, but got %#v", actual[i])
+			}
+			continue
+		}
+		var expected = p.Apply(given[i]).EnvironmentalScore()
+		if actual[i].Score != expected || actual[i].Score >= given[i].EnvironmentalScore() {
+			t.Errorf("Rescore() of %s == %.1f, which should be lower than %.1f, but got %.1f",
+				given[i], expected, given[i].EnvironmentalScore(), actual[i].Score)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/seal-io/meta-api/cvss/compatible"
 	"github.com/seal-io/meta-api/cvss/cvssv2"
 	"github.com/seal-io/meta-api/cvss/cvssv3"
+	"github.com/seal-io/meta-api/cvss/cvssv4"
 )
 
 // ShouldParse likes Parse but without error returning.
@@ -20,7 +21,41 @@ func Parse(s string) (compatible.Vector, error) {
 	switch prefix {
 	case "CVSS:3.0", "CVSS:3.1":
 		return cvssv3.Parse(s)
+	case "CVSS:4.0":
+		return cvssv4.Parse(s)
 	default:
 		return cvssv2.Parse(s)
 	}
 }
+
+// ParseLenient likes Parse but normalizes the CVSS(V2) or CVSS(V3) vector string before parsing,
+// the vector without prefix is regarded as CVSS(V2) if it has the Au metric, otherwise as CVSS(V3.1),
+// and returns a warning for each fix-up.
+func ParseLenient(s string) (compatible.Vector, []compatible.ParseWarning, error) {
+	var t = strings.ToUpper(strings.Trim(strings.TrimSpace(s), "() "))
+	var prefix = strings.TrimSpace(strings.SplitN(t, "/", 2)[0])
+	switch {
+	case strings.HasPrefix(prefix, "CVSS:4"):
+		var v, err = cvssv4.Parse(s)
+		if err != nil {
+			return nil, nil, err
+		}
+		return v, nil, nil
+	case strings.HasPrefix(prefix, "CVSS:"):
+	default:
+		for _, part := range strings.Split(t, "/") {
+			if strings.TrimSpace(strings.SplitN(part, ":", 2)[0]) == "AU" {
+				var v, ws, err = cvssv2.ParseLenient(s)
+				if err != nil {
+					return nil, nil, err
+				}
+				return v, ws, nil
+			}
+		}
+	}
+	var v, ws, err = cvssv3.ParseLenient(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	return v, ws, nil
+}
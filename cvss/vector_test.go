@@ -1,12 +1,14 @@
 package cvss
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/seal-io/meta-api/cvss/compatible"
 	"github.com/seal-io/meta-api/cvss/cvssv2"
 	"github.com/seal-io/meta-api/cvss/cvssv3"
+	"github.com/seal-io/meta-api/cvss/cvssv4"
 )
 
 func TestParse(t *testing.T) {
@@ -93,6 +95,24 @@ func TestParse(t *testing.T) {
 				},
 			},
 		},
+		{
+			given: cvssv4.DefaultVector().String(),
+			expected: output{
+				r: cvssv4.DefaultVector(),
+			},
+		},
+		{
+			given: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/E:P",
+			expected: output{
+				r: cvssv4.ShouldParse("CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/E:P"),
+			},
+		},
+		{
+			given: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H",
+			expected: output{
+				err: errors.New("illegal CVSS(V4) vector: CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H"),
+			},
+		},
 	}
 	for _, c := range testCases {
 		var actual output
@@ -110,3 +130,52 @@ func TestParse(t *testing.T) {
 		}
 	}
 }
+
+func TestParseLenient(t *testing.T) {
+	type output struct {
+		vector   string
+		warnings int
+		err      bool
+	}
+	var testCases = []struct {
+		given    string
+		expected output
+	}{
+		{
+			given: "(av:n/ac:l/au:n/c:p/i:p/a:p)",
+			expected: output{
+				vector:   "AV:N/AC:L/Au:N/C:P/I:P/A:P",
+				warnings: 7,
+			},
+		},
+		{
+			given: "AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+			expected: output{
+				vector:   "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+				warnings: 1,
+			},
+		},
+		{
+			given: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+			expected: output{
+				vector: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+			},
+		},
+		{
+			given: "AV:N/AC:L/Au:N",
+			expected: output{
+				err: true,
+			},
+		},
+	}
+	for _, c := range testCases {
+		var v, ws, err = ParseLenient(c.given)
+		var actual = output{warnings: len(ws), err: err != nil}
+		if err == nil {
+			actual.vector = v.String()
+		}
+		if actual != c.expected {
+			t.Errorf("lenient parse %s == %v, but got %v", c.given, c.expected, actual)
+		}
+	}
+}
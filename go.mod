@@ -4,9 +4,11 @@ go 1.18
 
 require (
 	github.com/google/uuid v1.1.2
+	go.etcd.io/bbolt v1.3.6
 	golang.org/x/sync v0.0.0-20190423024810-112230192c58
 	google.golang.org/grpc v1.50.1
 	google.golang.org/protobuf v1.28.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/seal-io/meta-api/schema"
+)
+
+// ErrUnknownIngestBody is returned if the body of the ingest response is unknown.
+var ErrUnknownIngestBody = errors.New("unknown ingest response body")
+
+// IngestHandler holds the typed callbacks to handle the ingested dataset,
+// the item callback is called after the batch callback of the same dataset,
+// and the nil callback is skipped.
+type IngestHandler struct {
+	// OnComplianceLicenseTags handles the schema.ComplianceLicenseTag items of a window.
+	OnComplianceLicenseTags func(currentWindow int32, items []*schema.ComplianceLicenseTag) error
+	// OnComplianceLicenseTag handles the schema.ComplianceLicenseTag item one by one.
+	OnComplianceLicenseTag func(item *schema.ComplianceLicenseTag) error
+
+	// OnComplianceLicenses handles the schema.ComplianceLicense items of a window.
+	OnComplianceLicenses func(currentWindow int32, items []*schema.ComplianceLicense) error
+	// OnComplianceLicense handles the schema.ComplianceLicense item one by one.
+	OnComplianceLicense func(item *schema.ComplianceLicense) error
+
+	// OnWeaknessVulnerabilityTags handles the schema.WeaknessVulnerabilityTag items of a window.
+	OnWeaknessVulnerabilityTags func(currentWindow int32, items []*schema.WeaknessVulnerabilityTag) error
+	// OnWeaknessVulnerabilityTag handles the schema.WeaknessVulnerabilityTag item one by one.
+	OnWeaknessVulnerabilityTag func(item *schema.WeaknessVulnerabilityTag) error
+
+	// OnWeaknessVulnerabilities handles the schema.WeaknessVulnerability items of a window.
+	OnWeaknessVulnerabilities func(currentWindow int32, items []*schema.WeaknessVulnerability) error
+	// OnWeaknessVulnerability handles the schema.WeaknessVulnerability item one by one.
+	OnWeaknessVulnerability func(item *schema.WeaknessVulnerability) error
+
+	// OnWeaknessVulnerabilityFeatures handles the schema.WeaknessVulnerabilityFeature items of a window.
+	OnWeaknessVulnerabilityFeatures func(currentWindow int32, items []*schema.WeaknessVulnerabilityFeature) error
+	// OnWeaknessVulnerabilityFeature handles the schema.WeaknessVulnerabilityFeature item one by one.
+	OnWeaknessVulnerabilityFeature func(item *schema.WeaknessVulnerabilityFeature) error
+}
+
+// Parser returns the IngestParser to route the items of each window to the callbacks,
+// it returns ErrUnknownIngestBody if the given schema.DatasetIngestResponseBody is unknown.
+func (h IngestHandler) Parser() IngestParser {
+	return h.parse
+}
+
+func (h IngestHandler) parse(currentWindow int32, body schema.DatasetIngestResponseBody) error {
+	switch b := body.(type) {
+	case *schema.DatasetIngestResponse_ComplianceLicenseTags:
+		return handle(currentWindow, b.ComplianceLicenseTags.GetItems(),
+			h.OnComplianceLicenseTags, h.OnComplianceLicenseTag)
+	case *schema.DatasetIngestResponse_ComplianceLicenses:
+		return handle(currentWindow, b.ComplianceLicenses.GetItems(),
+			h.OnComplianceLicenses, h.OnComplianceLicense)
+	case *schema.DatasetIngestResponse_WeaknessVulnerabilityTags:
+		return handle(currentWindow, b.WeaknessVulnerabilityTags.GetItems(),
+			h.OnWeaknessVulnerabilityTags, h.OnWeaknessVulnerabilityTag)
+	case *schema.DatasetIngestResponse_WeaknessVulnerabilities:
+		return handle(currentWindow, b.WeaknessVulnerabilities.GetItems(),
+			h.OnWeaknessVulnerabilities, h.OnWeaknessVulnerability)
+	case *schema.DatasetIngestResponse_WeaknessVulnerabilityFeatures:
+		return handle(currentWindow, b.WeaknessVulnerabilityFeatures.GetItems(),
+			h.OnWeaknessVulnerabilityFeatures, h.OnWeaknessVulnerabilityFeature)
+	}
+	return fmt.Errorf("%w: %T", ErrUnknownIngestBody, body)
+}
+
+func handle[T any](currentWindow int32, items []T, onBatch func(int32, []T) error, onItem func(T) error) error {
+	if onBatch != nil {
+		var err = onBatch(currentWindow, items)
+		if err != nil {
+			return err
+		}
+	}
+	if onItem != nil {
+		for i := range items {
+			var err = onItem(items[i])
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/seal-io/meta-api/schema"
+)
+
+func TestIngestHandler_Parser(t *testing.T) {
+	var actual []string
+	var h = IngestHandler{
+		OnComplianceLicenseTags: func(currentWindow int32, items []*schema.ComplianceLicenseTag) error {
+			actual = append(actual, "tags")
+			return nil
+		},
+		OnComplianceLicenseTag: func(item *schema.ComplianceLicenseTag) error {
+			actual = append(actual, item.GetName())
+			return nil
+		},
+		OnComplianceLicense: func(item *schema.ComplianceLicense) error {
+			actual = append(actual, item.GetNamespace()+"/"+item.GetName())
+			return nil
+		},
+		OnWeaknessVulnerabilities: func(currentWindow int32, items []*schema.WeaknessVulnerability) error {
+			if currentWindow == 9 {
+				return errors.New("stop")
+			}
+			actual = append(actual, "vulnerabilities")
+			return nil
+		},
+		OnWeaknessVulnerability: func(item *schema.WeaknessVulnerability) error {
+			actual = append(actual, item.GetPurl())
+			return nil
+		},
+	}
+	var parse = h.Parser()
+
+	var testCases = []struct {
+		given       schema.DatasetIngestResponseBody
+		window      int32
+		expected    []string
+		expectedErr bool
+	}{
+		{
+			given: &schema.DatasetIngestResponse_ComplianceLicenseTags{
+				ComplianceLicenseTags: &schema.ComplianceLicenseTags{
+					Items: []*schema.ComplianceLicenseTag{{Name: "permissive"}, {Name: "copyleft"}},
+				},
+			},
+			expected: []string{"tags", "permissive", "copyleft"},
+		},
+		{
+			given: &schema.DatasetIngestResponse_ComplianceLicenses{
+				ComplianceLicenses: &schema.ComplianceLicenses{
+					Items: []*schema.ComplianceLicense{{Namespace: "spdx", Name: "MIT"}},
+				},
+			},
+			expected: []string{"spdx/MIT"},
+		},
+		{
+			given: &schema.DatasetIngestResponse_WeaknessVulnerabilities{
+				WeaknessVulnerabilities: &schema.WeaknessVulnerabilities{
+					Items: []*schema.WeaknessVulnerability{{Purl: "pkg:npm/lodash"}},
+				},
+			},
+			expected: []string{"vulnerabilities", "pkg:npm/lodash"},
+		},
+		{
+			given: &schema.DatasetIngestResponse_WeaknessVulnerabilities{
+				WeaknessVulnerabilities: &schema.WeaknessVulnerabilities{
+					Items: []*schema.WeaknessVulnerability{{Purl: "pkg:npm/lodash"}},
+				},
+			},
+			window:      9,
+			expectedErr: true,
+		},
+		{
+			given: &schema.DatasetIngestResponse_WeaknessVulnerabilityFeatures{
+				WeaknessVulnerabilityFeatures: &schema.WeaknessVulnerabilityFeatures{
+					Items: []*schema.WeaknessVulnerabilityFeature{{Name: "ignored"}},
+				},
+			},
+		},
+		{
+			given:       nil,
+			expectedErr: true,
+		},
+	}
+	for i, c := range testCases {
+		actual = nil
+		var err = parse(c.window, c.given)
+		if c.expectedErr {
+			if err == nil {
+				t.Errorf("#%d expected error, but got nil", i+1)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d unexpected error: %v", i+1, err)
+			continue
+		}
+		if len(actual) != len(c.expected) {
+			t.Errorf("#%d expected %v, but got %v", i+1, c.expected, actual)
+			continue
+		}
+		for j := range actual {
+			if actual[j] != c.expected[j] {
+				t.Errorf("#%d expected %v, but got %v", i+1, c.expected, actual)
+				break
+			}
+		}
+	}
+
+	if err := parse(0, nil); !errors.Is(err, ErrUnknownIngestBody) {
+		t.Errorf("expected ErrUnknownIngestBody, but got %v", err)
+	}
+}
@@ -0,0 +1,4 @@
+// Package matcher provides the engine matching an inventory of packages to the vulnerabilities,
+// which matches the package URL with the purl and the purl_fuzzy of schema.WeaknessVulnerability at first,
+// and falls back to match the CPE names with the cpes of schema.WeaknessVulnerabilityTag.
+package matcher
@@ -0,0 +1,380 @@
+package matcher
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/seal-io/meta-api/genver"
+	"github.com/seal-io/meta-api/model"
+	"github.com/seal-io/meta-api/packageurl"
+	"github.com/seal-io/meta-api/schema"
+	"github.com/seal-io/meta-api/wfn"
+)
+
+// Source holds the actions for looking up the candidates of matching,
+// the candidates are verified by the Matcher again, e.g. vulndb.DB is a Source.
+type Source interface {
+	// VulnerabilitiesByPurl returns the vulnerabilities
+	// whose purl or purl_fuzzy has the same type, namespace and name as the given package URL.
+	VulnerabilitiesByPurl(purl string) ([]*schema.WeaknessVulnerability, error)
+
+	// VulnerabilitiesByPurlFuzzy returns the vulnerabilities
+	// whose purl_fuzzy has the same type and the same name case-insensitively as the given package URL,
+	// regardless of the namespace.
+	VulnerabilitiesByPurlFuzzy(purl string) ([]*schema.WeaknessVulnerability, error)
+
+	// VulnerabilityTagsByCPE returns the vulnerability tags with any CPE matching the given CPE name.
+	VulnerabilityTagsByCPE(cpe string) ([]*schema.WeaknessVulnerabilityTag, error)
+}
+
+// Package holds the identities of a package in the inventory.
+type Package struct {
+	// PackageURL is the package URL with version, e.g. "pkg:npm/lodash@4.17.15".
+	PackageURL string
+	// CPEs is the CPE names with version of the package, optional,
+	// e.g. "cpe:2.3:a:lodash:lodash:4.17.15:*:*:*:*:node.js:*:*".
+	CPEs []string
+}
+
+func (in Package) String() string {
+	if in.PackageURL != "" {
+		return in.PackageURL
+	}
+	return strings.Join(in.CPEs, ",")
+}
+
+// Reason is the reason of a Match.
+type Reason string
+
+// constants of Reason.
+const (
+	// ReasonPurl indicates the package URL is compatible with the purl of the vulnerability,
+	// and the version is in the affected range of the vulnerability.
+	ReasonPurl Reason = "purl"
+	// ReasonPurlFuzzy indicates the package URL is compatible with the purl_fuzzy of the vulnerability in name,
+	// and the version is in the affected range of the vulnerability.
+	ReasonPurlFuzzy Reason = "purl_fuzzy"
+	// ReasonCPE indicates the CPE name of the package matches the cpes of the vulnerability tag,
+	// and the version is affected.
+	ReasonCPE Reason = "cpe"
+	// ReasonCPEGuessed indicates the CPE name guessed from the package URL matches the cpes of the vulnerability tag,
+	// and the version is affected.
+	ReasonCPEGuessed Reason = "cpe_guessed"
+)
+
+// constants of the confidence.
+const (
+	confidencePurl      = 1.0
+	confidencePurlFuzzy = 0.7
+	// confidenceCPE is for the CPE with a version or a version range.
+	confidenceCPE = 0.6
+	// confidenceCPEUnversioned is for the CPE affecting any version.
+	confidenceCPEUnversioned = 0.4
+	// confidenceGuessed is the factor of the CPE confidence if the CPE name is guessed.
+	confidenceGuessed = 0.5
+)
+
+// Match holds a vulnerability matching a package.
+type Match struct {
+	// Package is the matched package.
+	Package Package
+	// Reason is the reason of the Match.
+	Reason Reason
+	// Confidence is the confidence of the Match, in range of (0, 1].
+	//  - 1.0 for ReasonPurl.
+	//  - 0.7 for ReasonPurlFuzzy.
+	//  - 0.6 for ReasonCPE with a version or a version range, 0.4 for that affecting any version.
+	//  - half of ReasonCPE for ReasonCPEGuessed.
+	Confidence float64
+	// Detail explains the Match in brief, e.g. "4.17.15 in >=3.7.0,<4.17.19".
+	Detail string
+	// Vulnerability is the matched vulnerability, only for matching via purl or purl_fuzzy.
+	Vulnerability *schema.WeaknessVulnerability
+	// Tag is the matched vulnerability tag, only for matching via CPE.
+	Tag *schema.WeaknessVulnerabilityTag
+}
+
+// ID returns the identity of the matched vulnerability,
+// which is "<namespace>/<name>" of the Vulnerability or the name of the Tag.
+func (in Match) ID() string {
+	if in.Vulnerability != nil {
+		return in.Vulnerability.GetNamespace() + "/" + in.Vulnerability.GetName()
+	}
+	return in.Tag.GetName()
+}
+
+// New returns a Matcher looking up the candidates from the given Source.
+func New(src Source, opts ...Option) *Matcher {
+	var o = _MatcherOptions{
+		CPEFallback: true,
+	}
+	for i := range opts {
+		if opts[i] == nil {
+			continue
+		}
+		opts[i](&o)
+	}
+	return &Matcher{
+		src: src,
+		o:   o,
+	}
+}
+
+type _MatcherOptions struct {
+	MinConfidence float64
+	CPEFallback   bool
+	CPEGuessing   bool
+}
+
+// Option configures the Matcher at New.
+type Option func(*_MatcherOptions)
+
+// WithMinConfidence drops the Match whose confidence is less than the given value.
+func WithMinConfidence(c float64) Option {
+	return func(o *_MatcherOptions) {
+		o.MinConfidence = c
+	}
+}
+
+// WithoutCPEFallback disables matching via CPE if the package URL matches nothing.
+func WithoutCPEFallback() Option {
+	return func(o *_MatcherOptions) {
+		o.CPEFallback = false
+	}
+}
+
+// WithCPEGuessing guesses the CPE names from the package URL for the package without CPE names,
+// e.g. "cpe:2.3:a:openssl:openssl:1.1.1k:*:*:*:*:*:*:*" is guessed from "pkg:generic/openssl@1.1.1k".
+func WithCPEGuessing() Option {
+	return func(o *_MatcherOptions) {
+		o.CPEGuessing = true
+	}
+}
+
+// Matcher matches the packages to the vulnerabilities,
+// it is safe for concurrent use if the Source is.
+type Matcher struct {
+	src Source
+	o   _MatcherOptions
+}
+
+// Match returns the vulnerabilities matching the given packages,
+// the result is in order of the given packages, and then the confidence from high to low.
+// The package URL is matched at first, and the CPE names are matched if the package URL matches nothing.
+func (in *Matcher) Match(ctx context.Context, pkgs ...Package) ([]Match, error) {
+	var r []Match
+	for i := range pkgs {
+		var err = ctx.Err()
+		if err != nil {
+			return nil, err
+		}
+		ms, err := in.match(pkgs[i])
+		if err != nil {
+			return nil, fmt.Errorf("error matching %s: %w", pkgs[i], err)
+		}
+		r = append(r, ms...)
+	}
+	return r, nil
+}
+
+func (in *Matcher) match(pkg Package) ([]Match, error) {
+	var ms []Match
+	var p *packageurl.PackageURL
+	if pkg.PackageURL != "" {
+		var purl, err = packageurl.FromString(pkg.PackageURL)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing purl: %w", err)
+		}
+		p = &purl
+		ms, err = in.matchPurl(pkg, purl)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(ms) == 0 && in.o.CPEFallback {
+		var cpes, reason = pkg.CPEs, ReasonCPE
+		if len(cpes) == 0 && in.o.CPEGuessing && p != nil {
+			cpes, reason = guessCPEs(*p), ReasonCPEGuessed
+		}
+		var err error
+		ms, err = in.matchCPEs(pkg, cpes, reason)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var r = ms[:0]
+	for i := range ms {
+		if ms[i].Confidence < in.o.MinConfidence {
+			continue
+		}
+		r = append(r, ms[i])
+	}
+	sort.SliceStable(r, func(i, j int) bool {
+		if r[i].Confidence != r[j].Confidence {
+			return r[i].Confidence > r[j].Confidence
+		}
+		return r[i].ID() < r[j].ID()
+	})
+	return r, nil
+}
+
+func (in *Matcher) matchPurl(pkg Package, p packageurl.PackageURL) ([]Match, error) {
+	if p.Version == "" {
+		return nil, nil
+	}
+	var q = packageurl.PackageURL{Type: p.Type, Namespace: p.Namespace, Name: p.Name}
+	var vs, err = in.src.VulnerabilitiesByPurl(q.String())
+	if err != nil {
+		return nil, fmt.Errorf("error looking up vulnerabilities: %w", err)
+	}
+	fvs, err := in.src.VulnerabilitiesByPurlFuzzy(q.String())
+	if err != nil {
+		return nil, fmt.Errorf("error looking up vulnerabilities fuzzily: %w", err)
+	}
+	var r []Match
+	var seen = map[string]struct{}{}
+	for _, v := range append(vs, fvs...) {
+		var k = v.GetNamespace() + "\x00" + v.GetName() + "\x00" + v.GetPurl()
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		var rng = v.GetAffected()
+		if rng == "" || !genver.InRange(p.Version, rng) {
+			continue
+		}
+		var m = Match{
+			Package:       pkg,
+			Detail:        fmt.Sprintf("%s in %s", p.Version, rng),
+			Vulnerability: v,
+		}
+		switch {
+		case isCompatible(v.GetPurl(), p, false):
+			m.Reason, m.Confidence = ReasonPurl, confidencePurl
+		case isCompatible(v.GetPurlFuzzy(), p, true):
+			m.Reason, m.Confidence = ReasonPurlFuzzy, confidencePurlFuzzy
+		default:
+			continue
+		}
+		r = append(r, m)
+	}
+	return r, nil
+}
+
+// isCompatible returns true if the given package URL string is compatible with the given PackageURL regardless of the version,
+// the fuzzy comparison ignores the namespace, the subpath and the case of the name.
+func isCompatible(s string, p packageurl.PackageURL, fuzzy bool) bool {
+	if s == "" {
+		return false
+	}
+	var q, err = packageurl.FromString(s)
+	if err != nil {
+		return false
+	}
+	var opts = []packageurl.CompatibleOption{packageurl.WithoutVersion()}
+	if fuzzy {
+		opts = append(opts, packageurl.WithoutNamespace(), packageurl.WithoutSubpath())
+		q.Name, p.Name = strings.ToLower(q.Name), strings.ToLower(p.Name)
+	}
+	return q.CompatibleWith(p, opts...)
+}
+
+func (in *Matcher) matchCPEs(pkg Package, cpes []string, reason Reason) ([]Match, error) {
+	var r []Match
+	var seen = map[string]struct{}{}
+	for _, cpe := range cpes {
+		var a, err = wfn.Parse(cpe)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing cpe: %w", err)
+		}
+		if a.Version == wfn.Any || a.Version == wfn.NA {
+			continue
+		}
+		ts, err := in.src.VulnerabilityTagsByCPE(cpe)
+		if err != nil {
+			return nil, fmt.Errorf("error looking up vulnerability tags: %w", err)
+		}
+		for _, t := range ts {
+			if _, ok := seen[t.GetName()]; ok {
+				continue
+			}
+			var confidence, detail = matchCPE(t, a)
+			if confidence == 0 {
+				continue
+			}
+			seen[t.GetName()] = struct{}{}
+			if reason == ReasonCPEGuessed {
+				confidence *= confidenceGuessed
+			}
+			r = append(r, Match{
+				Package:    pkg,
+				Reason:     reason,
+				Confidence: confidence,
+				Detail:     detail,
+				Tag:        t,
+			})
+		}
+	}
+	return r, nil
+}
+
+// matchCPE returns the highest confidence of the cpes of the given tag matching the given Attributes,
+// returns zero if not matched.
+func matchCPE(tag *schema.WeaknessVulnerabilityTag, a *wfn.Attributes) (float64, string) {
+	// NB: the CPEs of the undecodable tag are skipped.
+	var t, _ = model.DecodeWeaknessVulnerabilityTag(tag)
+	var version = wfn.StripSlashes(a.Version)
+	var av = *a
+	av.Version = wfn.Any
+
+	var confidence float64
+	var detail string
+	for _, c := range t.CPEs {
+		if !wfn.Match(c.Attributes, &av) || !c.IsAffected(version) {
+			continue
+		}
+		var cf, d = confidenceCPE, ""
+		switch {
+		case c.Affected != "":
+			d = fmt.Sprintf("%s in %s of %s", version, c.Affected, c.Name)
+		case c.Attributes.Version == wfn.Any:
+			cf, d = confidenceCPEUnversioned, fmt.Sprintf("%s in any version of %s", version, c.Name)
+		default:
+			d = fmt.Sprintf("%s matches %s", version, c.Name)
+		}
+		if cf > confidence {
+			confidence, detail = cf, d
+		}
+	}
+	return confidence, detail
+}
+
+// guessCPEs returns the CPE names guessed from the given PackageURL,
+// the product is the name, and the vendor is the name or the last segment of the namespace.
+func guessCPEs(p packageurl.PackageURL) []string {
+	if p.Version == "" {
+		return nil
+	}
+	var product = strings.ToLower(p.Name)
+	var vendors = []string{product}
+	if p.Namespace != "" {
+		var ns = p.Namespace[strings.LastIndex(p.Namespace, "/")+1:]
+		ns = strings.ToLower(strings.TrimPrefix(ns, "@"))
+		if ns != "" && ns != product {
+			vendors = append(vendors, ns)
+		}
+	}
+	var r = make([]string, 0, len(vendors))
+	for _, vendor := range vendors {
+		var a = wfn.NewAttributesWithAny()
+		a.Part = wfn.PartApplication
+		a.Vendor = wfn.ShouldWFNize(vendor)
+		a.Product = wfn.ShouldWFNize(product)
+		a.Version = wfn.ShouldWFNize(p.Version)
+		r = append(r, a.BindToFmtString())
+	}
+	return r
+}
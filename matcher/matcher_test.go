@@ -0,0 +1,205 @@
+package matcher
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/seal-io/meta-api/packageurl"
+	"github.com/seal-io/meta-api/schema"
+	"github.com/seal-io/meta-api/vulndb"
+	"github.com/seal-io/meta-api/wfn"
+)
+
+var _ Source = (*vulndb.DB)(nil)
+
+func TestMatcher(t *testing.T) {
+	var src = &testingSource{
+		vulnerabilities: []*schema.WeaknessVulnerability{
+			{Namespace: "github", Name: "GHSA-p6mc-m468-83gw", Purl: "pkg:npm/lodash", Affected: ">=3.7.0,<4.17.19"},
+			{Namespace: "nvd", Name: "CVE-2021-23337", Purl: "pkg:npm/lodash", Affected: "<4.17.21"},
+			{Namespace: "snyk", Name: "SNYK-JS-LODASHTEMPLATE-1088054", Purl: "pkg:npm/lodash.template", PurlFuzzy: "pkg:npm/Lodash", Affected: "<4.17.21"},
+			{Namespace: "github", Name: "GHSA-jf85-cpcp-j695", Purl: "pkg:npm/lodash", Affected: "<4.17.12"},
+			{Namespace: "nvd", Name: "CVE-2021-44228", Purl: "pkg:maven/org.apache.logging.log4j/log4j-core", PurlFuzzy: "pkg:maven/org.apache.logging.log4j/Log4j-Core", Affected: ">=2.0,<2.15.0"},
+		},
+		tags: []*schema.WeaknessVulnerabilityTag{
+			{
+				Name: "CVE-2021-3711",
+				Cpes: []byte(`[{"criteria":"cpe:2.3:a:openssl:openssl:*:*:*:*:*:*:*:*","versionStartIncluding":"1.1.0","versionEndExcluding":"1.1.1l"}]`),
+			},
+			{
+				Name: "CVE-2021-3450",
+				Cpes: []byte(`["cpe:2.3:a:openssl:openssl:1.1.1h:*:*:*:*:*:*:*","cpe:2.3:a:openssl:openssl:1.1.1k:*:*:*:*:*:*:*"]`),
+			},
+			{
+				Name: "CVE-2000-0001",
+				Cpes: []byte(`["cpe:2.3:a:openssl:openssl:*:*:*:*:*:*:*:*"]`),
+			},
+		},
+	}
+
+	type input struct {
+		pkg  Package
+		opts []Option
+	}
+	var testCases = []struct {
+		given    input
+		expected []string
+	}{
+		{
+			given: input{
+				pkg: Package{PackageURL: "pkg:npm/lodash@4.17.15"},
+			},
+			expected: []string{
+				"github/GHSA-p6mc-m468-83gw purl 1 (4.17.15 in >=3.7.0,<4.17.19)",
+				"nvd/CVE-2021-23337 purl 1 (4.17.15 in <4.17.21)",
+				"snyk/SNYK-JS-LODASHTEMPLATE-1088054 purl_fuzzy 0.7 (4.17.15 in <4.17.21)",
+			},
+		},
+		{
+			given: input{
+				pkg:  Package{PackageURL: "pkg:npm/lodash@4.17.15"},
+				opts: []Option{WithMinConfidence(0.8)},
+			},
+			expected: []string{
+				"github/GHSA-p6mc-m468-83gw purl 1 (4.17.15 in >=3.7.0,<4.17.19)",
+				"nvd/CVE-2021-23337 purl 1 (4.17.15 in <4.17.21)",
+			},
+		},
+		{
+			given: input{
+				pkg: Package{PackageURL: "pkg:npm/lodash@4.17.21"},
+			},
+			expected: nil,
+		},
+		{
+			given: input{
+				pkg: Package{PackageURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"},
+			},
+			expected: []string{
+				"nvd/CVE-2021-44228 purl 1 (2.14.1 in >=2.0,<2.15.0)",
+			},
+		},
+		{
+			given: input{
+				pkg: Package{PackageURL: "pkg:maven/log4j/log4j-core@2.14.1"},
+			},
+			expected: []string{
+				"nvd/CVE-2021-44228 purl_fuzzy 0.7 (2.14.1 in >=2.0,<2.15.0)",
+			},
+		},
+		{
+			given: input{
+				pkg: Package{PackageURL: "pkg:maven/org.apache.logging.log4j/LOG4J-CORE@2.14.1"},
+			},
+			expected: []string{
+				"nvd/CVE-2021-44228 purl_fuzzy 0.7 (2.14.1 in >=2.0,<2.15.0)",
+			},
+		},
+		{
+			given: input{
+				pkg: Package{
+					PackageURL: "pkg:generic/openssl@1.1.1k",
+					CPEs:       []string{"cpe:2.3:a:openssl:openssl:1.1.1k:*:*:*:*:*:*:*"},
+				},
+			},
+			expected: []string{
+				"CVE-2021-3450 cpe 0.6 (1.1.1k matches cpe:2.3:a:openssl:openssl:1.1.1k:*:*:*:*:*:*:*)",
+				"CVE-2021-3711 cpe 0.6 (1.1.1k in >=1.1.0,<1.1.1l of cpe:2.3:a:openssl:openssl:*:*:*:*:*:*:*:*)",
+				"CVE-2000-0001 cpe 0.4 (1.1.1k in any version of cpe:2.3:a:openssl:openssl:*:*:*:*:*:*:*:*)",
+			},
+		},
+		{
+			given: input{
+				pkg: Package{
+					PackageURL: "pkg:generic/openssl@1.1.1k",
+					CPEs:       []string{"cpe:2.3:a:openssl:openssl:1.1.1k:*:*:*:*:*:*:*"},
+				},
+				opts: []Option{WithoutCPEFallback()},
+			},
+			expected: nil,
+		},
+		{
+			given: input{
+				pkg: Package{PackageURL: "pkg:generic/openssl@1.1.1l"},
+			},
+			expected: nil,
+		},
+		{
+			given: input{
+				pkg:  Package{PackageURL: "pkg:generic/openssl@1.1.1l"},
+				opts: []Option{WithCPEGuessing()},
+			},
+			expected: []string{
+				"CVE-2000-0001 cpe_guessed 0.2 (1.1.1l in any version of cpe:2.3:a:openssl:openssl:*:*:*:*:*:*:*:*)",
+			},
+		},
+	}
+	for i, c := range testCases {
+		var ms, err = New(src, c.given.opts...).Match(context.Background(), c.given.pkg)
+		if err != nil {
+			t.Fatalf("#%d unexpected error: %v", i+1, err)
+		}
+		var actual []string
+		for _, m := range ms {
+			actual = append(actual, fmt.Sprintf("%s %s %v (%s)", m.ID(), m.Reason, m.Confidence, m.Detail))
+		}
+		if strings.Join(actual, "\n") != strings.Join(c.expected, "\n") {
+			t.Errorf("#%d expected matches\n%s\nbut got\n%s", i+1, strings.Join(c.expected, "\n"), strings.Join(actual, "\n"))
+		}
+	}
+}
+
+// testingSource looks up the candidates by scanning all items.
+type testingSource struct {
+	vulnerabilities []*schema.WeaknessVulnerability
+	tags            []*schema.WeaknessVulnerabilityTag
+}
+
+func (in *testingSource) VulnerabilitiesByPurl(purl string) ([]*schema.WeaknessVulnerability, error) {
+	var p, err = packageurl.FromString(purl)
+	if err != nil {
+		return nil, err
+	}
+	var r []*schema.WeaknessVulnerability
+	for _, v := range in.vulnerabilities {
+		for _, s := range []string{v.GetPurl(), v.GetPurlFuzzy()} {
+			var q, err = packageurl.FromString(s)
+			if err == nil && q.Type == p.Type && q.Namespace == p.Namespace && q.Name == p.Name {
+				r = append(r, v)
+				break
+			}
+		}
+	}
+	return r, nil
+}
+
+func (in *testingSource) VulnerabilitiesByPurlFuzzy(purl string) ([]*schema.WeaknessVulnerability, error) {
+	var p, err = packageurl.FromString(purl)
+	if err != nil {
+		return nil, err
+	}
+	var r []*schema.WeaknessVulnerability
+	for _, v := range in.vulnerabilities {
+		var q, err = packageurl.FromString(v.GetPurlFuzzy())
+		if err == nil && q.Type == p.Type && strings.EqualFold(q.Name, p.Name) {
+			r = append(r, v)
+		}
+	}
+	return r, nil
+}
+
+func (in *testingSource) VulnerabilityTagsByCPE(cpe string) ([]*schema.WeaknessVulnerabilityTag, error) {
+	var a, err = wfn.Parse(cpe)
+	if err != nil {
+		return nil, err
+	}
+	var r []*schema.WeaknessVulnerabilityTag
+	for _, t := range in.tags {
+		if strings.Contains(string(t.GetCpes()), ":"+a.Vendor+":"+a.Product+":") {
+			r = append(r, t)
+		}
+	}
+	return r, nil
+}
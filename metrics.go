@@ -0,0 +1,226 @@
+package api
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/seal-io/meta-api/schema"
+)
+
+// DefaultLatencyBuckets is the default upper bounds in seconds of the latency histograms.
+var DefaultLatencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30}
+
+// NewMetricsObserver returns a MetricsObserver with the given metric namespace,
+// e.g. "meta" results in the metrics named as "meta_ingest_items_total",
+// the DefaultLatencyBuckets is used if the given buckets is empty.
+func NewMetricsObserver(namespace string, buckets ...float64) *MetricsObserver {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	var prefix = "ingest_"
+	if namespace != "" {
+		prefix = namespace + "_" + prefix
+	}
+	return &MetricsObserver{
+		prefix:  prefix,
+		buckets: buckets,
+		types:   map[schema.DatasetIngestRequestType]*typeMetrics{},
+	}
+}
+
+// MetricsObserver is an IngestObserver collecting the counters and the histograms of ingesting by type,
+// the metrics can be scraped by Prometheus in text exposition format via ServeHTTP or WriteTo.
+type MetricsObserver struct {
+	prefix  string
+	buckets []float64
+
+	m     sync.Mutex
+	types map[schema.DatasetIngestRequestType]*typeMetrics
+}
+
+type typeMetrics struct {
+	inProgress    float64
+	currentWindow float64
+	runs          float64
+	failures      float64
+	windows       float64
+	items         float64
+	bytes         float64
+	retries       float64
+	windowLatency histogram
+	parseLatency  histogram
+	duration      histogram
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func (in *histogram) observe(buckets []float64, v float64) {
+	if in.counts == nil {
+		in.counts = make([]uint64, len(buckets))
+	}
+	for i := range buckets {
+		if v <= buckets[i] {
+			in.counts[i]++
+		}
+	}
+	in.count++
+	in.sum += v
+}
+
+func (in *MetricsObserver) get(typ schema.DatasetIngestRequestType) *typeMetrics {
+	var t = in.types[typ]
+	if t == nil {
+		t = &typeMetrics{}
+		in.types[typ] = t
+	}
+	return t
+}
+
+func (in *MetricsObserver) OnIngestStart(e IngestStartEvent) {
+	in.m.Lock()
+	defer in.m.Unlock()
+	var t = in.get(e.Type)
+	t.inProgress++
+	t.currentWindow = float64(e.Window)
+}
+
+func (in *MetricsObserver) OnIngestWindow(e IngestWindowEvent) {
+	in.m.Lock()
+	defer in.m.Unlock()
+	var t = in.get(e.Type)
+	t.currentWindow = float64(e.Window)
+	t.windows++
+	t.items += float64(e.Items)
+	t.bytes += float64(e.Bytes)
+	t.windowLatency.observe(in.buckets, e.Latency.Seconds())
+	t.parseLatency.observe(in.buckets, e.ParseLatency.Seconds())
+}
+
+func (in *MetricsObserver) OnIngestRetry(e IngestRetryEvent) {
+	in.m.Lock()
+	defer in.m.Unlock()
+	in.get(e.Type).retries++
+}
+
+func (in *MetricsObserver) OnIngestEnd(e IngestEndEvent) {
+	in.m.Lock()
+	defer in.m.Unlock()
+	var t = in.get(e.Type)
+	t.inProgress--
+	t.runs++
+	if e.Err != nil {
+		t.failures++
+	}
+	t.duration.observe(in.buckets, e.Duration.Seconds())
+}
+
+// ServeHTTP responses the metrics in Prometheus text exposition format.
+func (in *MetricsObserver) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = in.WriteTo(w)
+}
+
+// WriteTo writes the metrics in Prometheus text exposition format to the given io.Writer.
+func (in *MetricsObserver) WriteTo(w io.Writer) (int64, error) {
+	in.m.Lock()
+	defer in.m.Unlock()
+
+	var types = make([]schema.DatasetIngestRequestType, 0, len(in.types))
+	for typ := range in.types {
+		types = append(types, typ)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i] < types[j]
+	})
+
+	var cw = &countingWriter{w: bufio.NewWriter(w)}
+	var scalar = func(name, kind, help string, value func(*typeMetrics) float64) {
+		fmt.Fprintf(cw, "# HELP %s%s %s\n# TYPE %s%s %s\n", in.prefix, name, help, in.prefix, name, kind)
+		for _, typ := range types {
+			fmt.Fprintf(cw, "%s%s{type=%q} %s\n", in.prefix, name, typ.String(), formatFloat(value(in.types[typ])))
+		}
+	}
+	var hist = func(name, help string, value func(*typeMetrics) *histogram) {
+		fmt.Fprintf(cw, "# HELP %s%s %s\n# TYPE %s%s histogram\n", in.prefix, name, help, in.prefix, name)
+		for _, typ := range types {
+			var h = value(in.types[typ])
+			for i := range in.buckets {
+				var c uint64
+				if h.counts != nil {
+					c = h.counts[i]
+				}
+				fmt.Fprintf(cw, "%s%s_bucket{type=%q,le=%q} %d\n", in.prefix, name, typ.String(), formatFloat(in.buckets[i]), c)
+			}
+			fmt.Fprintf(cw, "%s%s_bucket{type=%q,le=\"+Inf\"} %d\n", in.prefix, name, typ.String(), h.count)
+			fmt.Fprintf(cw, "%s%s_sum{type=%q} %s\n", in.prefix, name, typ.String(), formatFloat(h.sum))
+			fmt.Fprintf(cw, "%s%s_count{type=%q} %d\n", in.prefix, name, typ.String(), h.count)
+		}
+	}
+
+	scalar("in_progress", "gauge", "Number of the ingesting in progress.",
+		func(t *typeMetrics) float64 { return t.inProgress })
+	scalar("current_window", "gauge", "The window of the latest progress.",
+		func(t *typeMetrics) float64 { return t.currentWindow })
+	scalar("runs_total", "counter", "Total number of the finished ingesting.",
+		func(t *typeMetrics) float64 { return t.runs })
+	scalar("failures_total", "counter", "Total number of the failed ingesting.",
+		func(t *typeMetrics) float64 { return t.failures })
+	scalar("windows_total", "counter", "Total number of the parsed windows.",
+		func(t *typeMetrics) float64 { return t.windows })
+	scalar("items_total", "counter", "Total number of the parsed items.",
+		func(t *typeMetrics) float64 { return t.items })
+	scalar("received_bytes_total", "counter", "Total size of the received messages in bytes.",
+		func(t *typeMetrics) float64 { return t.bytes })
+	scalar("retries_total", "counter", "Total number of the retrying.",
+		func(t *typeMetrics) float64 { return t.retries })
+	hist("window_latency_seconds", "Latency of requesting and receiving a window.",
+		func(t *typeMetrics) *histogram { return &t.windowLatency })
+	hist("parse_latency_seconds", "Latency of parsing a window.",
+		func(t *typeMetrics) *histogram { return &t.parseLatency })
+	hist("duration_seconds", "Duration of the ingesting.",
+		func(t *typeMetrics) *histogram { return &t.duration })
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// countingWriter counts the written bytes and keeps the first error.
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (in *countingWriter) Write(p []byte) (int, error) {
+	if in.err != nil {
+		return 0, in.err
+	}
+	var n, err = in.w.Write(p)
+	in.n += int64(n)
+	in.err = err
+	return n, err
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,210 @@
+package model
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/seal-io/meta-api/schema"
+)
+
+// ComplianceLicense is the decoded schema.ComplianceLicense.
+type ComplianceLicense struct {
+	// primary key
+	Namespace string
+	Name      string
+
+	// management
+	CreateTime    time.Time
+	UpdateTime    time.Time
+	DeprecateTime *time.Time
+	Tags          []string
+
+	// information
+	Code           string
+	Description    string
+	References     []Reference
+	Text           string
+	StandardHeader string
+	Comments       string
+
+	// extension
+	Specific []byte
+}
+
+// DecodeComplianceLicense decodes the given schema.ComplianceLicense,
+// the returning error is FieldErrors if any field cannot be decoded,
+// and the other fields of the returning ComplianceLicense are still valid.
+func DecodeComplianceLicense(in *schema.ComplianceLicense) (ComplianceLicense, error) {
+	var out = ComplianceLicense{
+		Namespace:      in.GetNamespace(),
+		Name:           in.GetName(),
+		CreateTime:     toTime(in.GetCreateTime()),
+		UpdateTime:     toTime(in.GetUpdateTime()),
+		DeprecateTime:  toTimePtr(in.GetDeprecateTime()),
+		Code:           in.GetCode(),
+		Description:    in.GetDescription(),
+		Text:           in.GetText(),
+		StandardHeader: in.GetStandardHeader(),
+		Comments:       in.GetComments(),
+		Specific:       in.GetSpecific(),
+	}
+
+	var errs FieldErrors
+	var err error
+	out.Tags, err = decodeStrings(in.GetTags())
+	errs.append("tags", err)
+	out.References, err = decodeReferences(in.GetReferences())
+	errs.append("references", err)
+	return out, errs.orNil()
+}
+
+// IsDeprecated returns true if the license has been deprecated.
+func (in ComplianceLicense) IsDeprecated() bool {
+	return in.DeprecateTime != nil
+}
+
+// LicenseCatalog indexes the ComplianceLicense and ComplianceLicenseTag,
+// and resolves the relationships between them,
+// it is not safe for concurrent writing.
+type LicenseCatalog struct {
+	tags           map[string]ComplianceLicenseTag
+	licenses       map[string]ComplianceLicense
+	licensesByCode map[string]string
+	licensesByTag  map[string][]string
+}
+
+// NewLicenseCatalog returns an empty LicenseCatalog.
+func NewLicenseCatalog() *LicenseCatalog {
+	return &LicenseCatalog{
+		tags:           map[string]ComplianceLicenseTag{},
+		licenses:       map[string]ComplianceLicense{},
+		licensesByCode: map[string]string{},
+		licensesByTag:  map[string][]string{},
+	}
+}
+
+// AddTag adds or replaces the given ComplianceLicenseTag.
+func (c *LicenseCatalog) AddTag(t ComplianceLicenseTag) {
+	c.tags[t.Name] = t
+}
+
+// AddLicense adds or replaces the given ComplianceLicense,
+// the duplicated tags of the given ComplianceLicense are removed.
+func (c *LicenseCatalog) AddLicense(l ComplianceLicense) {
+	var k = licenseKey(l.Namespace, l.Name)
+	if o, exist := c.licenses[k]; exist {
+		if oc := strings.ToLower(o.Code); oc != "" && c.licensesByCode[oc] == k {
+			delete(c.licensesByCode, oc)
+		}
+		for _, tn := range o.Tags {
+			c.licensesByTag[tn] = remove(c.licensesByTag[tn], k)
+		}
+	}
+	l.Tags = dedupe(l.Tags)
+	c.licenses[k] = l
+	if l.Code != "" {
+		c.licensesByCode[strings.ToLower(l.Code)] = k
+	}
+	for _, tn := range l.Tags {
+		c.licensesByTag[tn] = append(c.licensesByTag[tn], k)
+	}
+}
+
+// Tag returns the ComplianceLicenseTag by the given name.
+func (c *LicenseCatalog) Tag(name string) (ComplianceLicenseTag, bool) {
+	var t, exist = c.tags[name]
+	return t, exist
+}
+
+// License returns the ComplianceLicense by the given namespace and name.
+func (c *LicenseCatalog) License(namespace, name string) (ComplianceLicense, bool) {
+	var l, exist = c.licenses[licenseKey(namespace, name)]
+	return l, exist
+}
+
+// LicenseByCode returns the ComplianceLicense by the given code case-insensitively,
+// e.g. Apache-2.0.
+func (c *LicenseCatalog) LicenseByCode(code string) (ComplianceLicense, bool) {
+	var k, exist = c.licensesByCode[strings.ToLower(code)]
+	if !exist {
+		return ComplianceLicense{}, false
+	}
+	return c.licenses[k], true
+}
+
+// TagsOf returns the resolved ComplianceLicenseTag list of the given ComplianceLicense,
+// the unknown tags are skipped.
+func (c *LicenseCatalog) TagsOf(l ComplianceLicense) []ComplianceLicenseTag {
+	var r = make([]ComplianceLicenseTag, 0, len(l.Tags))
+	for _, tn := range l.Tags {
+		if t, exist := c.tags[tn]; exist {
+			r = append(r, t)
+		}
+	}
+	return r
+}
+
+// LicensesOf returns the ComplianceLicense list tagged by the given tag name,
+// sorted by namespace and name.
+func (c *LicenseCatalog) LicensesOf(tagName string) []ComplianceLicense {
+	var ks = append([]string(nil), c.licensesByTag[tagName]...)
+	sort.Strings(ks)
+	var r = make([]ComplianceLicense, 0, len(ks))
+	for _, k := range ks {
+		r = append(r, c.licenses[k])
+	}
+	return r
+}
+
+// Classify returns the most restrictive LicenseCategory of the tags of the given ComplianceLicense,
+// returns LicenseCategoryUnknown if no tag is categorized.
+func (c *LicenseCatalog) Classify(l ComplianceLicense) LicenseCategory {
+	var r = LicenseCategoryUnknown
+	for _, t := range c.TagsOf(l) {
+		if t.Category.Restriction() > r.Restriction() {
+			r = t.Category
+		}
+	}
+	return r
+}
+
+// IsPermissive returns true if the given ComplianceLicense is classified as permissive or public domain.
+func (c *LicenseCatalog) IsPermissive(l ComplianceLicense) bool {
+	return c.Classify(l).IsPermissive()
+}
+
+// IsCopyleft returns true if the given ComplianceLicense is classified as any kind of copyleft.
+func (c *LicenseCatalog) IsCopyleft(l ComplianceLicense) bool {
+	return c.Classify(l).IsCopyleft()
+}
+
+func licenseKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+func remove(ss []string, s string) []string {
+	for i := range ss {
+		if ss[i] == s {
+			return append(ss[:i], ss[i+1:]...)
+		}
+	}
+	return ss
+}
+
+// dedupe returns the given strings without the duplicated ones in order.
+func dedupe(ss []string) []string {
+	if len(ss) < 2 {
+		return ss
+	}
+	var r = make([]string, 0, len(ss))
+	var seen = make(map[string]struct{}, len(ss))
+	for _, s := range ss {
+		if _, exist := seen[s]; exist {
+			continue
+		}
+		seen[s] = struct{}{}
+		r = append(r, s)
+	}
+	return r
+}
@@ -0,0 +1,137 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/seal-io/meta-api/schema"
+)
+
+// ComplianceLicenseTag is the decoded schema.ComplianceLicenseTag.
+type ComplianceLicenseTag struct {
+	// primary key
+	Name string
+
+	// management
+	CreateTime    time.Time
+	UpdateTime    time.Time
+	DeprecateTime *time.Time
+
+	// information
+	Description string
+	References  []Reference
+	Category    LicenseCategory
+
+	// extension
+	Specific []byte
+}
+
+// DecodeComplianceLicenseTag decodes the given schema.ComplianceLicenseTag,
+// the returning error is FieldErrors if any field cannot be decoded,
+// and the other fields of the returning ComplianceLicenseTag are still valid.
+func DecodeComplianceLicenseTag(in *schema.ComplianceLicenseTag) (ComplianceLicenseTag, error) {
+	var out = ComplianceLicenseTag{
+		Name:          in.GetName(),
+		CreateTime:    toTime(in.GetCreateTime()),
+		UpdateTime:    toTime(in.GetUpdateTime()),
+		DeprecateTime: toTimePtr(in.GetDeprecateTime()),
+		Description:   in.GetDescription(),
+		Category:      ParseLicenseCategory(in.GetCategory()),
+		Specific:      in.GetSpecific(),
+	}
+
+	var errs FieldErrors
+	var err error
+	out.References, err = decodeReferences(in.GetReferences())
+	errs.append("references", err)
+	return out, errs.orNil()
+}
+
+// IsDeprecated returns true if the tag has been deprecated.
+func (in ComplianceLicenseTag) IsDeprecated() bool {
+	return in.DeprecateTime != nil
+}
+
+// LicenseCategory holds the category of license,
+// the categories are ordered by the restriction from low to high.
+type LicenseCategory string
+
+// constants of LicenseCategory.
+const (
+	LicenseCategoryUnknown         LicenseCategory = ""
+	LicenseCategoryPublicDomain    LicenseCategory = "public-domain"
+	LicenseCategoryPermissive      LicenseCategory = "permissive"
+	LicenseCategoryWeakCopyleft    LicenseCategory = "weak-copyleft"
+	LicenseCategoryCopyleft        LicenseCategory = "copyleft"
+	LicenseCategoryNetworkCopyleft LicenseCategory = "network-copyleft"
+	LicenseCategoryProprietary     LicenseCategory = "proprietary"
+)
+
+var licenseCategoryAliases = map[string]LicenseCategory{
+	"public-domain":      LicenseCategoryPublicDomain,
+	"permissive":         LicenseCategoryPermissive,
+	"weak-copyleft":      LicenseCategoryWeakCopyleft,
+	"copyleft-limited":   LicenseCategoryWeakCopyleft,
+	"limited-copyleft":   LicenseCategoryWeakCopyleft,
+	"file-copyleft":      LicenseCategoryWeakCopyleft,
+	"copyleft":           LicenseCategoryCopyleft,
+	"strong-copyleft":    LicenseCategoryCopyleft,
+	"network-copyleft":   LicenseCategoryNetworkCopyleft,
+	"network-protective": LicenseCategoryNetworkCopyleft,
+	"proprietary":        LicenseCategoryProprietary,
+	"proprietary-free":   LicenseCategoryProprietary,
+	"commercial":         LicenseCategoryProprietary,
+	"source-available":   LicenseCategoryProprietary,
+	"free-restricted":    LicenseCategoryProprietary,
+	"unstated-license":   LicenseCategoryUnknown,
+	"unknown":            LicenseCategoryUnknown,
+	"uncategorized":      LicenseCategoryUnknown,
+}
+
+// ParseLicenseCategory parses the given free string into LicenseCategory,
+// e.g. "Copyleft Limited" is parsed as LicenseCategoryWeakCopyleft,
+// returns LicenseCategoryUnknown if not recognized.
+func ParseLicenseCategory(s string) LicenseCategory {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.NewReplacer(" ", "-", "_", "-").Replace(s)
+	return licenseCategoryAliases[s]
+}
+
+// Restriction returns a numeric value of this LicenseCategory,
+// which can be used for comparing.
+func (in LicenseCategory) Restriction() int {
+	switch in {
+	case LicenseCategoryPublicDomain:
+		return 1
+	case LicenseCategoryPermissive:
+		return 2
+	case LicenseCategoryWeakCopyleft:
+		return 3
+	case LicenseCategoryCopyleft:
+		return 4
+	case LicenseCategoryNetworkCopyleft:
+		return 5
+	case LicenseCategoryProprietary:
+		return 6
+	default:
+		return 0
+	}
+}
+
+// IsPermissive returns true if this LicenseCategory is permissive or public domain.
+func (in LicenseCategory) IsPermissive() bool {
+	switch in {
+	case LicenseCategoryPublicDomain, LicenseCategoryPermissive:
+		return true
+	}
+	return false
+}
+
+// IsCopyleft returns true if this LicenseCategory is any kind of copyleft.
+func (in LicenseCategory) IsCopyleft() bool {
+	switch in {
+	case LicenseCategoryWeakCopyleft, LicenseCategoryCopyleft, LicenseCategoryNetworkCopyleft:
+		return true
+	}
+	return false
+}
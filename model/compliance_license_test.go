@@ -0,0 +1,132 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/seal-io/meta-api/schema"
+)
+
+func TestParseLicenseCategory(t *testing.T) {
+	var testCases = []struct {
+		given    string
+		expected LicenseCategory
+	}{
+		{given: "Permissive", expected: LicenseCategoryPermissive},
+		{given: "Copyleft Limited", expected: LicenseCategoryWeakCopyleft},
+		{given: "weak_copyleft", expected: LicenseCategoryWeakCopyleft},
+		{given: " Copyleft ", expected: LicenseCategoryCopyleft},
+		{given: "Public Domain", expected: LicenseCategoryPublicDomain},
+		{given: "Commercial", expected: LicenseCategoryProprietary},
+		{given: "whatever", expected: LicenseCategoryUnknown},
+	}
+	for _, c := range testCases {
+		var actual = ParseLicenseCategory(c.given)
+		if actual != c.expected {
+			t.Errorf("ParseLicenseCategory(%q) == %q, but got %q", c.given, c.expected, actual)
+		}
+	}
+}
+
+func TestDecodeComplianceLicense(t *testing.T) {
+	var given = &schema.ComplianceLicense{
+		Namespace:  "spdx",
+		Name:       "LGPL-2.1-only",
+		Code:       "LGPL-2.1-only",
+		Tags:       []byte(`["copyleft-limited","osi-approved"]`),
+		References: []byte(`["https://www.gnu.org/licenses/old-licenses/lgpl-2.1-standalone.html"]`),
+		Specific:   []byte(`{"isOsiApproved":true}`),
+	}
+	var actual, err = DecodeComplianceLicense(given)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(actual.Tags) != "[copyleft-limited osi-approved]" {
+		t.Errorf("expected tags [copyleft-limited osi-approved], but got %v", actual.Tags)
+	}
+	if len(actual.References) != 1 {
+		t.Errorf("expected 1 reference, but got %v", actual.References)
+	}
+	if specific, err := DecodeSpecific(actual.Specific); err != nil || specific["isOsiApproved"] != true {
+		t.Errorf("expected specific isOsiApproved, but got %v: %v", specific, err)
+	}
+
+	_, err = DecodeComplianceLicense(&schema.ComplianceLicense{
+		Tags:       []byte(`{`),
+		References: []byte(`{`),
+	})
+	var errs FieldErrors
+	if !errors.As(err, &errs) || fmt.Sprint(errs.Fields()) != "[tags references]" {
+		t.Errorf("expected failed fields [tags references], but got %v", err)
+	}
+	if _, err = DecodeSpecific([]byte(`[]`)); err == nil {
+		t.Errorf("expected error of decoding non-object specific")
+	}
+}
+
+func TestLicenseCatalog(t *testing.T) {
+	var c = NewLicenseCatalog()
+	for _, given := range []*schema.ComplianceLicenseTag{
+		{Name: "permissive", Category: "Permissive"},
+		{Name: "copyleft-limited", Category: "Copyleft Limited"},
+		{Name: "osi-approved", Category: "Certification"},
+	} {
+		var tag, err = DecodeComplianceLicenseTag(given)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		c.AddTag(tag)
+	}
+	for _, given := range []*schema.ComplianceLicense{
+		{Namespace: "spdx", Name: "MIT", Code: "MIT", Tags: []byte(`["permissive","osi-approved"]`)},
+		{Namespace: "spdx", Name: "LGPL-2.1-only", Code: "LGPL-2.1-only", Tags: []byte(`["permissive"]`)},
+		{Namespace: "spdx", Name: "LGPL-2.1-only", Code: "LGPL-2.1", Tags: []byte(`["copyleft-limited","osi-approved","osi-approved"]`)},
+		{Namespace: "spdx", Name: "Unlicensed", Code: "Unlicensed"},
+	} {
+		var l, err = DecodeComplianceLicense(given)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		c.AddLicense(l)
+	}
+
+	var mit, _ = c.LicenseByCode("mit")
+	if !c.IsPermissive(mit) || c.IsCopyleft(mit) {
+		t.Errorf("expected MIT is permissive, but got %s", c.Classify(mit))
+	}
+	if tags := c.TagsOf(mit); len(tags) != 2 || tags[1].Category != LicenseCategoryUnknown {
+		t.Errorf("expected 2 tags of MIT, but got %v", tags)
+	}
+	var lgpl, _ = c.License("spdx", "LGPL-2.1-only")
+	if c.Classify(lgpl) != LicenseCategoryWeakCopyleft || !c.IsCopyleft(lgpl) {
+		t.Errorf("expected LGPL-2.1-only is weak copyleft, but got %s", c.Classify(lgpl))
+	}
+	if tags := c.TagsOf(lgpl); len(tags) != 2 {
+		t.Errorf("expected 2 tags of LGPL-2.1-only, but got %v", tags)
+	}
+	if _, exist := c.LicenseByCode("LGPL-2.1-only"); exist {
+		t.Errorf("expected replaced code LGPL-2.1-only is not found")
+	}
+	if l, exist := c.LicenseByCode("lgpl-2.1"); !exist || l.Name != "LGPL-2.1-only" {
+		t.Errorf("expected license of code LGPL-2.1 is LGPL-2.1-only, but got %v", l.Name)
+	}
+	var unlicensed, _ = c.LicenseByCode("Unlicensed")
+	if c.Classify(unlicensed) != LicenseCategoryUnknown {
+		t.Errorf("expected Unlicensed is unknown, but got %s", c.Classify(unlicensed))
+	}
+	var names []string
+	for _, l := range c.LicensesOf("permissive") {
+		names = append(names, l.Name)
+	}
+	if fmt.Sprint(names) != "[MIT]" {
+		t.Errorf("expected licenses of permissive [MIT], but got %v", names)
+	}
+	names = nil
+	for _, l := range c.LicensesOf("osi-approved") {
+		names = append(names, l.Name)
+	}
+	if fmt.Sprint(names) != "[LGPL-2.1-only MIT]" {
+		t.Errorf("expected licenses of osi-approved [LGPL-2.1-only MIT], but got %v", names)
+	}
+}
@@ -0,0 +1,192 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// FieldError holds the error of decoding a field.
+type FieldError struct {
+	// Field is the name of the field in proto definition, e.g. cvss.
+	Field string
+	// Err is the decoding error.
+	Err error
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("error decoding field %s: %v", e.Field, e.Err)
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrors holds the errors of decoding fields,
+// the decoded model is still usable except the failed fields.
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	var sb strings.Builder
+	for i := range e {
+		if i != 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(e[i].Error())
+	}
+	return sb.String()
+}
+
+// Fields returns the names of the failed fields.
+func (e FieldErrors) Fields() []string {
+	var r = make([]string, 0, len(e))
+	for i := range e {
+		r = append(r, e[i].Field)
+	}
+	return r
+}
+
+func (e *FieldErrors) append(field string, err error) {
+	if err == nil {
+		return
+	}
+	*e = append(*e, FieldError{Field: field, Err: err})
+}
+
+func (e FieldErrors) orNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Reference holds the reference link.
+type Reference struct {
+	// Type is the type of the reference, e.g. ADVISORY, WEB, optional.
+	Type string `json:"type,omitempty"`
+	// URL is the link of the reference.
+	URL string `json:"url"`
+}
+
+// decodeReferences decodes the references in the following formats.
+//   - ["https://..."]
+//   - [{"type": "WEB", "url": "https://..."}]
+func decodeReferences(bs []byte) ([]Reference, error) {
+	var raws, err = decodeArray(bs)
+	if err != nil || len(raws) == 0 {
+		return nil, err
+	}
+	var r = make([]Reference, 0, len(raws))
+	for i := range raws {
+		var ref Reference
+		if isJSONString(raws[i]) {
+			err = json.Unmarshal(raws[i], &ref.URL)
+		} else {
+			err = json.Unmarshal(raws[i], &ref)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if ref.URL == "" {
+			continue
+		}
+		r = append(r, ref)
+	}
+	return r, nil
+}
+
+// decodeStrings decodes the string list in the following formats.
+//   - ["a", "b"]
+//   - "a"
+func decodeStrings(bs []byte) ([]string, error) {
+	bs = bytes.TrimSpace(bs)
+	if isJSONNull(bs) {
+		return nil, nil
+	}
+	if isJSONString(bs) {
+		var s string
+		var err = json.Unmarshal(bs, &s)
+		if err != nil || s == "" {
+			return nil, err
+		}
+		return []string{s}, nil
+	}
+	var r []string
+	var err = json.Unmarshal(bs, &r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// decodeArray decodes the given bytes as JSON array,
+// the non-array JSON value is treated as an array with single element.
+func decodeArray(bs []byte) ([]json.RawMessage, error) {
+	bs = bytes.TrimSpace(bs)
+	if isJSONNull(bs) {
+		return nil, nil
+	}
+	if bs[0] != '[' {
+		if !json.Valid(bs) {
+			return nil, fmt.Errorf("invalid JSON: %s", bs)
+		}
+		return []json.RawMessage{bs}, nil
+	}
+	var r []json.RawMessage
+	var err = json.Unmarshal(bs, &r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// DecodeSpecific decodes the given extension bytes as a JSON object,
+// e.g. the Specific of WeaknessVulnerability, returns nil if the extension is empty.
+func DecodeSpecific(bs []byte) (map[string]any, error) {
+	if isJSONNull(bs) {
+		return nil, nil
+	}
+	var r map[string]any
+	var err = json.Unmarshal(bs, &r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func isJSONNull(bs []byte) bool {
+	return len(bs) == 0 || bytes.Equal(bs, []byte("null"))
+}
+
+func isJSONString(bs []byte) bool {
+	return len(bs) != 0 && bs[0] == '"'
+}
+
+func toTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func toTimePtr(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	var t = ts.AsTime()
+	return &t
+}
+
+func sortedKeys(m map[string]string) []string {
+	var r = make([]string, 0, len(m))
+	for k := range m {
+		r = append(r, k)
+	}
+	sort.Strings(r)
+	return r
+}
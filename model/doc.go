@@ -0,0 +1,3 @@
+// Package model provides the domain models of the ingested dataset,
+// which decodes the opaque bytes fields of the messages in schema package.
+package model
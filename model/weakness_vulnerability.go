@@ -0,0 +1,272 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/seal-io/meta-api/cvss"
+	"github.com/seal-io/meta-api/cvss/compatible"
+	"github.com/seal-io/meta-api/genver"
+	"github.com/seal-io/meta-api/packageurl"
+	"github.com/seal-io/meta-api/schema"
+)
+
+// WeaknessVulnerability is the decoded schema.WeaknessVulnerability.
+type WeaknessVulnerability struct {
+	// primary key
+	Namespace string
+	Name      string
+	Purl      string
+
+	// management
+	CreateTime    time.Time
+	UpdateTime    time.Time
+	DeprecateTime *time.Time
+	Tags          []string
+	PurlFuzzy     string
+
+	// information
+	Code         string
+	Description  string
+	References   []Reference
+	Summary      string
+	Mitigation   string
+	Exploitation string
+	Affected     string
+	Patched      []string
+	CVSS         []CVSS
+	CWEs         []string
+	EPSS         *EPSS
+	Published    time.Time
+	Modified     time.Time
+
+	// extension
+	Specific []byte
+
+	// derivation
+	PackageURL *packageurl.PackageURL
+}
+
+// CVSS holds the parsed CVSS vector.
+type CVSS struct {
+	// Source is the provider of the CVSS vector, e.g. nvd, optional.
+	Source string
+	// Vector is the parsed CVSS vector.
+	Vector compatible.Vector
+}
+
+// EPSS holds the Exploit Prediction Scoring System data,
+// according to https://www.first.org/epss/data_stats.
+type EPSS struct {
+	// Score is the probability of exploitation in the next 30 days, in range of [0, 1].
+	Score float64 `json:"score"`
+	// Percentile is the proportion of all scored vulnerabilities with the same or a lower score.
+	Percentile float64 `json:"percentile"`
+	// Date is the date of the scoring, optional.
+	Date string `json:"date,omitempty"`
+}
+
+// DecodeWeaknessVulnerability decodes the given schema.WeaknessVulnerability,
+// the returning error is FieldErrors if any field cannot be decoded,
+// and the other fields of the returning WeaknessVulnerability are still valid.
+func DecodeWeaknessVulnerability(in *schema.WeaknessVulnerability) (WeaknessVulnerability, error) {
+	var out = WeaknessVulnerability{
+		Namespace:     in.GetNamespace(),
+		Name:          in.GetName(),
+		Purl:          in.GetPurl(),
+		CreateTime:    toTime(in.GetCreateTime()),
+		UpdateTime:    toTime(in.GetUpdateTime()),
+		DeprecateTime: toTimePtr(in.GetDeprecateTime()),
+		PurlFuzzy:     in.GetPurlFuzzy(),
+		Code:          in.GetCode(),
+		Description:   in.GetDescription(),
+		Summary:       in.GetSummary(),
+		Mitigation:    in.GetMitigation(),
+		Exploitation:  in.GetExploitation(),
+		Affected:      in.GetAffected(),
+		Published:     toTime(in.GetPublished()),
+		Modified:      toTime(in.GetModified()),
+		Specific:      in.GetSpecific(),
+	}
+
+	var errs FieldErrors
+	var err error
+	out.Tags, err = decodeStrings(in.GetTags())
+	errs.append("tags", err)
+	out.References, err = decodeReferences(in.GetReferences())
+	errs.append("references", err)
+	out.Patched, err = decodeStrings(in.GetPatched())
+	errs.append("patched", err)
+	out.CVSS, err = decodeCVSS(in.GetCvss())
+	errs.append("cvss", err)
+	out.CWEs, err = decodeCWEs(in.GetCwes())
+	errs.append("cwes", err)
+	out.EPSS, err = decodeEPSS(in.GetEpss())
+	errs.append("epss", err)
+	if out.Purl != "" {
+		var p packageurl.PackageURL
+		p, err = packageurl.FromString(out.Purl)
+		if err == nil {
+			out.PackageURL = &p
+		}
+		errs.append("purl", err)
+	}
+	return out, errs.orNil()
+}
+
+// IsDeprecated returns true if the vulnerability has been deprecated.
+func (in WeaknessVulnerability) IsDeprecated() bool {
+	return in.DeprecateTime != nil
+}
+
+// IsAffected returns true if the given version is in the affected range.
+func (in WeaknessVulnerability) IsAffected(version string) bool {
+	if in.Affected == "" {
+		return false
+	}
+	return genver.InRange(version, in.Affected)
+}
+
+// MaxCVSS returns the CVSS with the highest base score,
+// returns nil if no CVSS.
+func (in WeaknessVulnerability) MaxCVSS() *CVSS {
+	var r *CVSS
+	for i := range in.CVSS {
+		if in.CVSS[i].Vector == nil {
+			continue
+		}
+		if r == nil || in.CVSS[i].Vector.BaseScore() > r.Vector.BaseScore() {
+			r = &in.CVSS[i]
+		}
+	}
+	return r
+}
+
+// decodeCVSS decodes the CVSS vectors in the following formats.
+//   - ["CVSS:3.1/AV:N/..."]
+//   - [{"source": "nvd", "vector": "CVSS:3.1/AV:N/..."}]
+//   - {"nvd": "CVSS:3.1/AV:N/..."}
+func decodeCVSS(bs []byte) ([]CVSS, error) {
+	var raws, err = decodeArray(bs)
+	if err != nil || len(raws) == 0 {
+		return nil, err
+	}
+	var r = make([]CVSS, 0, len(raws))
+	for i := range raws {
+		var entries = map[string]string{}
+		if isJSONString(raws[i]) {
+			var s string
+			err = json.Unmarshal(raws[i], &s)
+			entries[""] = s
+		} else {
+			var e struct {
+				Source       string `json:"source"`
+				Vector       string `json:"vector"`
+				VectorString string `json:"vectorString"`
+			}
+			err = json.Unmarshal(raws[i], &e)
+			switch {
+			case err != nil:
+			case e.Vector != "":
+				entries[e.Source] = e.Vector
+			case e.VectorString != "":
+				entries[e.Source] = e.VectorString
+			default:
+				err = json.Unmarshal(raws[i], &entries)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, src := range sortedKeys(entries) {
+			if entries[src] == "" {
+				continue
+			}
+			var v, err = cvss.Parse(entries[src])
+			if err != nil {
+				return nil, err
+			}
+			r = append(r, CVSS{Source: src, Vector: v})
+		}
+	}
+	return r, nil
+}
+
+// decodeCWEs decodes the CWE IDs in the following formats,
+// and normalizes them into the form of CWE-<number>.
+//   - ["CWE-79"]
+//   - [79]
+func decodeCWEs(bs []byte) ([]string, error) {
+	var raws, err = decodeArray(bs)
+	if err != nil || len(raws) == 0 {
+		return nil, err
+	}
+	var r = make([]string, 0, len(raws))
+	for i := range raws {
+		var s string
+		if isJSONString(raws[i]) {
+			err = json.Unmarshal(raws[i], &s)
+		} else {
+			var n json.Number
+			err = json.Unmarshal(raws[i], &n)
+			s = n.String()
+		}
+		if err != nil {
+			return nil, err
+		}
+		s = strings.ToUpper(strings.TrimSpace(s))
+		if s == "" {
+			continue
+		}
+		if !strings.HasPrefix(s, "CWE-") {
+			if _, err = strconv.ParseUint(s, 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid CWE ID: %s", s)
+			}
+			s = "CWE-" + s
+		}
+		r = append(r, s)
+	}
+	return r, nil
+}
+
+// decodeEPSS decodes the EPSS in the following formats,
+// the last one is picked if multiple EPSS are given.
+//   - {"score": 0.00043, "percentile": 0.0784}
+//   - {"score": "0.00043", "percentile": "0.0784"}
+//   - [{"score": 0.00043, "percentile": 0.0784, "date": "2022-11-03"}]
+func decodeEPSS(bs []byte) (*EPSS, error) {
+	var raws, err = decodeArray(bs)
+	if err != nil || len(raws) == 0 {
+		return nil, err
+	}
+	var r EPSS
+	var e struct {
+		Score      json.Number `json:"score"`
+		Percentile json.Number `json:"percentile"`
+		Date       string      `json:"date"`
+	}
+	err = json.Unmarshal(raws[len(raws)-1], &e)
+	if err != nil {
+		return nil, err
+	}
+	r.Score, err = parseFloat(e.Score)
+	if err != nil {
+		return nil, fmt.Errorf("invalid EPSS score: %w", err)
+	}
+	r.Percentile, err = parseFloat(e.Percentile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid EPSS percentile: %w", err)
+	}
+	r.Date = e.Date
+	return &r, nil
+}
+
+func parseFloat(n json.Number) (float64, error) {
+	if n == "" {
+		return 0, nil
+	}
+	return n.Float64()
+}
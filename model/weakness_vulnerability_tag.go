@@ -0,0 +1,171 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/seal-io/meta-api/genver"
+	"github.com/seal-io/meta-api/schema"
+	"github.com/seal-io/meta-api/wfn"
+)
+
+// WeaknessVulnerabilityTag is the decoded schema.WeaknessVulnerabilityTag.
+type WeaknessVulnerabilityTag struct {
+	// primary key
+	Name string
+
+	// management
+	CreateTime    time.Time
+	UpdateTime    time.Time
+	DeprecateTime *time.Time
+
+	// information
+	Description string
+	References  []Reference
+	Exploits    []Reference
+	Category    string
+	CVSS        []CVSS
+	CWEs        []string
+	CPEs        []CPE
+	EPSS        *EPSS
+	Published   time.Time
+	Modified    time.Time
+
+	// extension
+	Specific []byte
+}
+
+// CPE holds the parsed CPE name and the optional affected version range.
+type CPE struct {
+	// Name is the CPE name in URI binding or formatted string binding.
+	Name string
+	// Attributes is the parsed Name.
+	Attributes *wfn.Attributes
+	// Affected is the affected version range in genver form, e.g. ">=1.0.0,<1.2.0", optional.
+	Affected string
+}
+
+// IsAffected returns true if the given version is in the affected range,
+// or matches the version of the CPE name if no affected range.
+func (in CPE) IsAffected(version string) bool {
+	if in.Affected != "" {
+		return genver.InRange(version, in.Affected)
+	}
+	if in.Attributes == nil {
+		return false
+	}
+	switch in.Attributes.Version {
+	case wfn.Any:
+		return true
+	case wfn.NA:
+		return false
+	}
+	return genver.InRange(version, "="+wfn.StripSlashes(in.Attributes.Version))
+}
+
+// DecodeWeaknessVulnerabilityTag decodes the given schema.WeaknessVulnerabilityTag,
+// the returning error is FieldErrors if any field cannot be decoded,
+// and the other fields of the returning WeaknessVulnerabilityTag are still valid.
+func DecodeWeaknessVulnerabilityTag(in *schema.WeaknessVulnerabilityTag) (WeaknessVulnerabilityTag, error) {
+	var out = WeaknessVulnerabilityTag{
+		Name:          in.GetName(),
+		CreateTime:    toTime(in.GetCreateTime()),
+		UpdateTime:    toTime(in.GetUpdateTime()),
+		DeprecateTime: toTimePtr(in.GetDeprecateTime()),
+		Description:   in.GetDescription(),
+		Category:      in.GetCategory(),
+		Published:     toTime(in.GetPublished()),
+		Modified:      toTime(in.GetModified()),
+		Specific:      in.GetSpecific(),
+	}
+
+	var errs FieldErrors
+	var err error
+	out.References, err = decodeReferences(in.GetReferences())
+	errs.append("references", err)
+	out.Exploits, err = decodeReferences(in.GetExploits())
+	errs.append("exploits", err)
+	out.CVSS, err = decodeCVSS(in.GetCvsses())
+	errs.append("cvsses", err)
+	out.CWEs, err = decodeCWEs(in.GetCwes())
+	errs.append("cwes", err)
+	out.CPEs, err = decodeCPEs(in.GetCpes())
+	errs.append("cpes", err)
+	out.EPSS, err = decodeEPSS(in.GetEpsses())
+	errs.append("epsses", err)
+	return out, errs.orNil()
+}
+
+// IsDeprecated returns true if the tag has been deprecated.
+func (in WeaknessVulnerabilityTag) IsDeprecated() bool {
+	return in.DeprecateTime != nil
+}
+
+// MaxCVSS returns the CVSS with the highest base score,
+// returns nil if no CVSS.
+func (in WeaknessVulnerabilityTag) MaxCVSS() *CVSS {
+	return WeaknessVulnerability{CVSS: in.CVSS}.MaxCVSS()
+}
+
+// decodeCPEs decodes the CPE names in the following formats,
+// the version range fields follow the NVD configuration.
+//   - ["cpe:2.3:a:lodash:lodash:*:*:*:*:*:node.js:*:*"]
+//   - [{"cpe": "cpe:2.3:a:lodash:lodash:*:*:*:*:*:node.js:*:*", "versionEndExcluding": "4.17.19"}]
+//   - [{"criteria": "cpe:2.3:a:lodash:lodash:*:*:*:*:*:node.js:*:*", "versionEndExcluding": "4.17.19"}]
+func decodeCPEs(bs []byte) ([]CPE, error) {
+	var raws, err = decodeArray(bs)
+	if err != nil || len(raws) == 0 {
+		return nil, err
+	}
+	var r = make([]CPE, 0, len(raws))
+	for i := range raws {
+		var e struct {
+			CPE                   string `json:"cpe"`
+			CPE23URI              string `json:"cpe23Uri"`
+			Criteria              string `json:"criteria"`
+			VersionStartIncluding string `json:"versionStartIncluding"`
+			VersionStartExcluding string `json:"versionStartExcluding"`
+			VersionEndIncluding   string `json:"versionEndIncluding"`
+			VersionEndExcluding   string `json:"versionEndExcluding"`
+		}
+		if isJSONString(raws[i]) {
+			err = json.Unmarshal(raws[i], &e.CPE)
+		} else {
+			err = json.Unmarshal(raws[i], &e)
+		}
+		if err != nil {
+			return nil, err
+		}
+		var c CPE
+		for _, n := range []string{e.CPE, e.CPE23URI, e.Criteria} {
+			if n != "" {
+				c.Name = n
+				break
+			}
+		}
+		if c.Name == "" {
+			continue
+		}
+		c.Attributes, err = wfn.Parse(c.Name)
+		if err != nil {
+			return nil, err
+		}
+		var rng []string
+		switch {
+		case e.VersionStartIncluding != "":
+			rng = append(rng, ">="+e.VersionStartIncluding)
+		case e.VersionStartExcluding != "":
+			rng = append(rng, ">"+e.VersionStartExcluding)
+		}
+		switch {
+		case e.VersionEndIncluding != "":
+			rng = append(rng, "<="+e.VersionEndIncluding)
+		case e.VersionEndExcluding != "":
+			rng = append(rng, "<"+e.VersionEndExcluding)
+		}
+		c.Affected = strings.Join(rng, ",")
+		r = append(r, c)
+	}
+	return r, nil
+}
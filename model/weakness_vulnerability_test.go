@@ -0,0 +1,135 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/seal-io/meta-api/schema"
+)
+
+func TestDecodeWeaknessVulnerability(t *testing.T) {
+	var ts = time.Date(2022, 11, 3, 11, 18, 47, 0, time.UTC)
+	var given = &schema.WeaknessVulnerability{
+		Namespace:     "github",
+		Name:          "GHSA-p6mc-m468-83gw",
+		Purl:          "pkg:npm/lodash",
+		UpdateTime:    timestamppb.New(ts),
+		DeprecateTime: timestamppb.New(ts),
+		Tags:          []byte(`["CVE-2020-8203"]`),
+		Code:          "CVE-2020-8203",
+		References:    []byte(`["https://nvd.nist.gov/vuln/detail/CVE-2020-8203",{"type":"WEB","url":"https://github.com/lodash/lodash/issues/4874"}]`),
+		Affected:      ">=3.7.0,<4.17.19",
+		Patched:       []byte(`["4.17.19"]`),
+		Cvss:          []byte(`[{"source":"nvd","vector":"CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:N/I:H/A:H"},"AV:N/AC:M/Au:N/C:N/I:P/A:P"]`),
+		Cwes:          []byte(`["CWE-770", 1321]`),
+		Epss:          []byte(`{"score":"0.00832","percentile":0.81}`),
+	}
+	var actual, err = DecodeWeaknessVulnerability(given)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual.PackageURL == nil || actual.PackageURL.Type != "npm" || actual.PackageURL.Name != "lodash" {
+		t.Errorf("expected parsed purl of npm/lodash, but got %v", actual.PackageURL)
+	}
+	if !actual.IsDeprecated() || !actual.UpdateTime.Equal(ts) {
+		t.Errorf("expected deprecated at %v, but got %v", ts, actual.DeprecateTime)
+	}
+	if fmt.Sprint(actual.Tags) != "[CVE-2020-8203]" {
+		t.Errorf("expected tags [CVE-2020-8203], but got %v", actual.Tags)
+	}
+	if len(actual.References) != 2 || actual.References[1].Type != "WEB" {
+		t.Errorf("expected 2 references, but got %v", actual.References)
+	}
+	if fmt.Sprint(actual.Patched) != "[4.17.19]" {
+		t.Errorf("expected patched [4.17.19], but got %v", actual.Patched)
+	}
+	if len(actual.CVSS) != 2 || actual.CVSS[0].Source != "nvd" || actual.CVSS[1].Vector.GetVersion() != "2.0" {
+		t.Errorf("expected 2 cvss, but got %v", actual.CVSS)
+	}
+	if m := actual.MaxCVSS(); m == nil || m.Vector.BaseScore() != 7.4 {
+		t.Errorf("expected max cvss with base score 7.4, but got %v", m)
+	}
+	if fmt.Sprint(actual.CWEs) != "[CWE-770 CWE-1321]" {
+		t.Errorf("expected cwes [CWE-770 CWE-1321], but got %v", actual.CWEs)
+	}
+	if actual.EPSS == nil || actual.EPSS.Score != 0.00832 || actual.EPSS.Percentile != 0.81 {
+		t.Errorf("expected epss 0.00832/0.81, but got %v", actual.EPSS)
+	}
+	for v, expected := range map[string]bool{"4.17.15": true, "4.17.19": false, "3.6.0": false} {
+		if actual.IsAffected(v) != expected {
+			t.Errorf("IsAffected(%s) == %v, but got %v", v, expected, !expected)
+		}
+	}
+}
+
+func TestDecodeWeaknessVulnerability_FieldErrors(t *testing.T) {
+	var given = &schema.WeaknessVulnerability{
+		Namespace: "nvd",
+		Name:      "CVE-2020-8203",
+		Purl:      "npm/lodash",
+		Tags:      []byte(`["CVE-2020-8203"]`),
+		Cvss:      []byte(`["CVSS:3.1/AV:X"]`),
+		Cwes:      []byte(`["79"]`),
+		Epss:      []byte(`{"score":`),
+	}
+	var actual, err = DecodeWeaknessVulnerability(given)
+	var errs FieldErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected FieldErrors, but got %v", err)
+	}
+	if fmt.Sprint(errs.Fields()) != "[cvss epss purl]" {
+		t.Errorf("expected failed fields [cvss epss purl], but got %v", errs.Fields())
+	}
+	if fmt.Sprint(actual.Tags) != "[CVE-2020-8203]" || fmt.Sprint(actual.CWEs) != "[CWE-79]" {
+		t.Errorf("expected the other fields decoded, but got %v, %v", actual.Tags, actual.CWEs)
+	}
+	if actual.Name != "CVE-2020-8203" || actual.PackageURL != nil {
+		t.Errorf("expected name CVE-2020-8203 without purl, but got %s, %v", actual.Name, actual.PackageURL)
+	}
+}
+
+func TestDecodeWeaknessVulnerabilityTag(t *testing.T) {
+	var given = &schema.WeaknessVulnerabilityTag{
+		Name:     "CVE-2020-8203",
+		Exploits: []byte(`["https://hackerone.com/reports/712065"]`),
+		Cvsses:   []byte(`{"nvd":"CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:N/I:H/A:H"}`),
+		Cwes:     []byte(`[1321]`),
+		Cpes: []byte(`["cpe:2.3:a:lodash:lodash:4.17.15:*:*:*:*:node.js:*:*",` +
+			`{"criteria":"cpe:2.3:a:lodash:lodash:*:*:*:*:*:node.js:*:*","versionStartIncluding":"3.7.0","versionEndExcluding":"4.17.19"}]`),
+		Epsses: []byte(`[{"score":0.001,"percentile":0.3},{"score":0.00832,"percentile":0.81}]`),
+	}
+	var actual, err = DecodeWeaknessVulnerabilityTag(given)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(actual.Exploits) != 1 || fmt.Sprint(actual.CWEs) != "[CWE-1321]" {
+		t.Errorf("expected 1 exploit and cwes [CWE-1321], but got %v, %v", actual.Exploits, actual.CWEs)
+	}
+	if m := actual.MaxCVSS(); m == nil || m.Source != "nvd" || m.Vector.BaseScore() != 7.4 {
+		t.Errorf("expected max cvss from nvd with base score 7.4, but got %v", m)
+	}
+	if actual.EPSS == nil || actual.EPSS.Score != 0.00832 {
+		t.Errorf("expected the last epss 0.00832, but got %v", actual.EPSS)
+	}
+	if len(actual.CPEs) != 2 || actual.CPEs[0].Attributes.Product != "lodash" || actual.CPEs[1].Affected != ">=3.7.0,<4.17.19" {
+		t.Fatalf("expected 2 cpes of lodash, but got %v", actual.CPEs)
+	}
+	var testCases = []struct {
+		given    string
+		expected [2]bool
+	}{
+		{given: "4.17.15", expected: [2]bool{true, true}},
+		{given: "4.17.19", expected: [2]bool{false, false}},
+		{given: "3.6.0", expected: [2]bool{false, false}},
+	}
+	for _, c := range testCases {
+		var actual = [2]bool{actual.CPEs[0].IsAffected(c.given), actual.CPEs[1].IsAffected(c.given)}
+		if actual != c.expected {
+			t.Errorf("expected IsAffected(%s) of cpes %v, but got %v", c.given, c.expected, actual)
+		}
+	}
+}
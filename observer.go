@@ -0,0 +1,200 @@
+package api
+
+import (
+	"time"
+
+	"github.com/seal-io/meta-api/schema"
+)
+
+// IngestObserver holds the callbacks to observe the progress of ingesting,
+// which might be called concurrently during concurrent IngestAll,
+// so the implementation must be safe for concurrent use and should return quickly.
+type IngestObserver interface {
+	// OnIngestStart is called before ingesting the specified type dataset.
+	OnIngestStart(e IngestStartEvent)
+	// OnIngestWindow is called after a window parsed.
+	OnIngestWindow(e IngestWindowEvent)
+	// OnIngestRetry is called before retrying a failed window.
+	OnIngestRetry(e IngestRetryEvent)
+	// OnIngestEnd is called after ingesting the specified type dataset, no matter succeeded or failed.
+	OnIngestEnd(e IngestEndEvent)
+}
+
+// IngestStartEvent holds the information of starting to ingest a type dataset.
+type IngestStartEvent struct {
+	// Type is the type of the ingesting dataset.
+	Type schema.DatasetIngestRequestType
+	// Since is the since condition of the ingesting dataset.
+	Since time.Time
+	// Window is the first window to request, which is not zero if resuming from a Checkpoint.
+	Window int32
+}
+
+// IngestWindowEvent holds the information of a parsed window.
+type IngestWindowEvent struct {
+	// Type is the type of the ingesting dataset.
+	Type schema.DatasetIngestRequestType
+	// Window is the parsed window.
+	Window int32
+	// WindowSize is the window size responded by the exposing service.
+	WindowSize int32
+	// Items is the count of the items in the window.
+	Items int
+	// Bytes is the size of the received message in bytes.
+	Bytes int
+	// Latency is the duration of requesting and receiving the window.
+	Latency time.Duration
+	// ParseLatency is the duration of parsing the window.
+	ParseLatency time.Duration
+}
+
+// IngestRetryEvent holds the information of retrying a failed window.
+type IngestRetryEvent struct {
+	// Type is the type of the ingesting dataset.
+	Type schema.DatasetIngestRequestType
+	// Window is the failed window.
+	Window int32
+	// Attempts is the count of the failed attempts of the window.
+	Attempts int
+	// Backoff is the duration to wait before retrying.
+	Backoff time.Duration
+	// Err is the error of the last attempt.
+	Err error
+}
+
+// IngestEndEvent holds the summary of ingesting a type dataset.
+type IngestEndEvent struct {
+	// Type is the type of the ingesting dataset.
+	Type schema.DatasetIngestRequestType
+	// Windows is the count of the parsed windows.
+	Windows int
+	// Items is the count of the parsed items.
+	Items int
+	// Bytes is the total size of the received messages in bytes.
+	Bytes int
+	// Retries is the count of the retrying.
+	Retries int
+	// Duration is the duration of ingesting.
+	Duration time.Duration
+	// Err is the error of ingesting, nil if succeeded.
+	Err error
+}
+
+// WithObserver reports the progress of ingesting to the given IngestObserver,
+// multiple IngestObserver are called in order.
+func WithObserver(obs ...IngestObserver) IngestOption {
+	return func(o *_IngestOptions) {
+		for i := range obs {
+			if obs[i] == nil {
+				continue
+			}
+			o.Observers = append(o.Observers, obs[i])
+		}
+	}
+}
+
+// ingestObservers calls the IngestObserver list in order.
+type ingestObservers []IngestObserver
+
+func (in ingestObservers) OnIngestStart(e IngestStartEvent) {
+	for i := range in {
+		in[i].OnIngestStart(e)
+	}
+}
+
+func (in ingestObservers) OnIngestWindow(e IngestWindowEvent) {
+	for i := range in {
+		in[i].OnIngestWindow(e)
+	}
+}
+
+func (in ingestObservers) OnIngestRetry(e IngestRetryEvent) {
+	for i := range in {
+		in[i].OnIngestRetry(e)
+	}
+}
+
+func (in ingestObservers) OnIngestEnd(e IngestEndEvent) {
+	for i := range in {
+		in[i].OnIngestEnd(e)
+	}
+}
+
+// countItems returns the count of the items in the given schema.DatasetIngestResponseBody.
+func countItems(body schema.DatasetIngestResponseBody) int {
+	switch b := body.(type) {
+	case *schema.DatasetIngestResponse_ComplianceLicenseTags:
+		return len(b.ComplianceLicenseTags.GetItems())
+	case *schema.DatasetIngestResponse_ComplianceLicenses:
+		return len(b.ComplianceLicenses.GetItems())
+	case *schema.DatasetIngestResponse_WeaknessVulnerabilityTags:
+		return len(b.WeaknessVulnerabilityTags.GetItems())
+	case *schema.DatasetIngestResponse_WeaknessVulnerabilities:
+		return len(b.WeaknessVulnerabilities.GetItems())
+	case *schema.DatasetIngestResponse_WeaknessVulnerabilityFeatures:
+		return len(b.WeaknessVulnerabilityFeatures.GetItems())
+	}
+	return 0
+}
+
+// StructuredLogger holds the leveled logging actions with key-value pairs,
+// which is satisfied by the *slog.Logger of log/slog package.
+type StructuredLogger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// NewLoggingObserver returns an IngestObserver logging the progress of ingesting to the given StructuredLogger,
+// the windows are logged in debug level, the retrying is logged in warn level.
+func NewLoggingObserver(logger StructuredLogger) IngestObserver {
+	return loggingObserver{logger: logger}
+}
+
+type loggingObserver struct {
+	logger StructuredLogger
+}
+
+func (in loggingObserver) OnIngestStart(e IngestStartEvent) {
+	in.logger.Info("ingest started",
+		"type", e.Type.String(),
+		"since", e.Since,
+		"window", e.Window)
+}
+
+func (in loggingObserver) OnIngestWindow(e IngestWindowEvent) {
+	in.logger.Debug("ingest window parsed",
+		"type", e.Type.String(),
+		"window", e.Window,
+		"windowSize", e.WindowSize,
+		"items", e.Items,
+		"bytes", e.Bytes,
+		"latency", e.Latency,
+		"parseLatency", e.ParseLatency)
+}
+
+func (in loggingObserver) OnIngestRetry(e IngestRetryEvent) {
+	in.logger.Warn("ingest window retrying",
+		"type", e.Type.String(),
+		"window", e.Window,
+		"attempts", e.Attempts,
+		"backoff", e.Backoff,
+		"error", e.Err)
+}
+
+func (in loggingObserver) OnIngestEnd(e IngestEndEvent) {
+	var args = []any{
+		"type", e.Type.String(),
+		"windows", e.Windows,
+		"items", e.Items,
+		"bytes", e.Bytes,
+		"retries", e.Retries,
+		"duration", e.Duration,
+	}
+	if e.Err != nil {
+		in.logger.Error("ingest failed", append(args, "error", e.Err)...)
+		return
+	}
+	in.logger.Info("ingest finished", args...)
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/seal-io/meta-api/schema"
+)
+
+func TestClient_Ingest_WithObserver(t *testing.T) {
+	var typ = schema.DatasetIngestRequestType_Compliance_License_Tag
+	var failures int32
+	var srv = &testingDatasetServer{
+		windows: map[schema.DatasetIngestRequestType][]schema.DatasetIngestResponseBody{
+			typ: testingLicenseTagWindows(3),
+		},
+		intercept: func(req *schema.DatasetIngestRequest) error {
+			if req.GetWindow() == 1 && atomic.AddInt32(&failures, 1) == 1 {
+				return status.Error(codes.Unavailable, "transient")
+			}
+			return nil
+		},
+	}
+	var policy = DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+
+	var rec = &testingObserver{}
+	var logger = &testingLogger{}
+	var metrics = NewMetricsObserver("meta", 0.1, 1)
+	var err = testingClient(t, srv).Ingest(context.Background(), typ, time.Time{}, nil,
+		WithRetry(policy), WithObserver(rec, NewLoggingObserver(logger), metrics))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := "[start:0 window:0 retry:1 window:1 window:2 end:3/3]"; fmt.Sprint(rec.events) != expected {
+		t.Errorf("expected observed %s, but got %v", expected, rec.events)
+	}
+	if expected := "INFO ingest started; DEBUG ingest window parsed; WARN ingest window retrying; " +
+		"DEBUG ingest window parsed; DEBUG ingest window parsed; INFO ingest finished"; strings.Join(logger.logs, "; ") != expected {
+		t.Errorf("expected logged %s, but got %v", expected, logger.logs)
+	}
+
+	var sb strings.Builder
+	if _, err = metrics.WriteTo(&sb); err != nil {
+		t.Fatalf("error writing metrics: %v", err)
+	}
+	for _, expected := range []string{
+		"# TYPE meta_ingest_items_total counter",
+		`meta_ingest_in_progress{type="Compliance_License_Tag"} 0`,
+		`meta_ingest_windows_total{type="Compliance_License_Tag"} 3`,
+		`meta_ingest_items_total{type="Compliance_License_Tag"} 3`,
+		`meta_ingest_retries_total{type="Compliance_License_Tag"} 1`,
+		`meta_ingest_runs_total{type="Compliance_License_Tag"} 1`,
+		"# TYPE meta_ingest_window_latency_seconds histogram",
+		`meta_ingest_window_latency_seconds_bucket{type="Compliance_License_Tag",le="+Inf"} 3`,
+		`meta_ingest_duration_seconds_count{type="Compliance_License_Tag"} 1`,
+	} {
+		if !strings.Contains(sb.String(), expected) {
+			t.Errorf("expected metrics containing %q, but got\n%s", expected, sb.String())
+		}
+	}
+}
+
+// testingObserver records the observed events in brief.
+type testingObserver struct {
+	m      sync.Mutex
+	events []string
+}
+
+func (in *testingObserver) record(format string, args ...any) {
+	in.m.Lock()
+	defer in.m.Unlock()
+	in.events = append(in.events, fmt.Sprintf(format, args...))
+}
+
+func (in *testingObserver) OnIngestStart(e IngestStartEvent) {
+	in.record("start:%d", e.Window)
+}
+
+func (in *testingObserver) OnIngestWindow(e IngestWindowEvent) {
+	in.record("window:%d", e.Window)
+}
+
+func (in *testingObserver) OnIngestRetry(e IngestRetryEvent) {
+	in.record("retry:%d", e.Window)
+}
+
+func (in *testingObserver) OnIngestEnd(e IngestEndEvent) {
+	in.record("end:%d/%d", e.Windows, e.Items)
+}
+
+// testingLogger records the logged messages with level.
+type testingLogger struct {
+	m    sync.Mutex
+	logs []string
+}
+
+func (in *testingLogger) log(level, msg string) {
+	in.m.Lock()
+	defer in.m.Unlock()
+	in.logs = append(in.logs, level+" "+msg)
+}
+
+func (in *testingLogger) Debug(msg string, _ ...any) { in.log("DEBUG", msg) }
+func (in *testingLogger) Info(msg string, _ ...any)  { in.log("INFO", msg) }
+func (in *testingLogger) Warn(msg string, _ ...any)  { in.log("WARN", msg) }
+func (in *testingLogger) Error(msg string, _ ...any) { in.log("ERROR", msg) }
@@ -0,0 +1,254 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// CycloneDX 1.4 VEX documents, according to https://cyclonedx.org/docs/1.4/json.
+type (
+	cdxBOM struct {
+		BOMFormat       string             `json:"bomFormat"`
+		SpecVersion     string             `json:"specVersion"`
+		SerialNumber    string             `json:"serialNumber"`
+		Version         int                `json:"version"`
+		Metadata        cdxMetadata        `json:"metadata"`
+		Components      []cdxComponent     `json:"components,omitempty"`
+		Vulnerabilities []cdxVulnerability `json:"vulnerabilities"`
+	}
+
+	cdxMetadata struct {
+		Timestamp string    `json:"timestamp"`
+		Tools     []cdxTool `json:"tools,omitempty"`
+		Authors   []cdxName `json:"authors,omitempty"`
+	}
+
+	cdxTool struct {
+		Name    string `json:"name"`
+		Version string `json:"version,omitempty"`
+	}
+
+	cdxName struct {
+		Name string `json:"name"`
+	}
+
+	cdxComponent struct {
+		BOMRef string `json:"bom-ref"`
+		Type   string `json:"type"`
+		Name   string `json:"name"`
+		Purl   string `json:"purl,omitempty"`
+		CPE    string `json:"cpe,omitempty"`
+	}
+
+	cdxVulnerability struct {
+		ID             string        `json:"id"`
+		Source         *cdxName      `json:"source,omitempty"`
+		References     []cdxRef      `json:"references,omitempty"`
+		Ratings        []cdxRating   `json:"ratings,omitempty"`
+		CWEs           []int         `json:"cwes,omitempty"`
+		Description    string        `json:"description,omitempty"`
+		Recommendation string        `json:"recommendation,omitempty"`
+		Advisories     []cdxAdvisory `json:"advisories,omitempty"`
+		Published      string        `json:"published,omitempty"`
+		Updated        string        `json:"updated,omitempty"`
+		Analysis       cdxAnalysis   `json:"analysis"`
+		Affects        []cdxAffect   `json:"affects"`
+		Properties     []cdxProperty `json:"properties,omitempty"`
+	}
+
+	cdxRef struct {
+		ID     string  `json:"id"`
+		Source cdxName `json:"source"`
+	}
+
+	cdxRating struct {
+		Source   *cdxName `json:"source,omitempty"`
+		Score    float64  `json:"score"`
+		Severity string   `json:"severity"`
+		Method   string   `json:"method"`
+		Vector   string   `json:"vector"`
+	}
+
+	cdxAdvisory struct {
+		URL string `json:"url"`
+	}
+
+	cdxAnalysis struct {
+		State         string `json:"state"`
+		Justification string `json:"justification,omitempty"`
+		Detail        string `json:"detail,omitempty"`
+	}
+
+	cdxAffect struct {
+		Ref string `json:"ref"`
+	}
+
+	cdxProperty struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+)
+
+// WriteCycloneDXVEX writes the given Report as a CycloneDX 1.4 VEX document,
+// each package is a component referred by the package URL or the CPE name,
+// and each vulnerability affects the packages of the findings in the same Status.
+// The SSVC decision is recorded in the properties of the vulnerability.
+// It returns error if any Finding in StatusNotAffected has neither Justification nor ImpactStatement.
+func WriteCycloneDXVEX(w io.Writer, r Report) error {
+	var bom = cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.4",
+		SerialNumber: "urn:uuid:" + reportUUID(r.ID),
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: timestamp(r.Timestamp),
+		},
+		Vulnerabilities: []cdxVulnerability{},
+	}
+	if r.Tool.Name != "" {
+		bom.Metadata.Tools = []cdxTool{{Name: r.Tool.Name, Version: r.Tool.Version}}
+	}
+	if r.Author != "" {
+		bom.Metadata.Authors = []cdxName{{Name: r.Author}}
+	}
+
+	var components = map[string]struct{}{}
+	var vulns = map[string]int{}
+	for _, f := range r.Findings {
+		if _, ok := components[f.Package]; !ok {
+			components[f.Package] = struct{}{}
+			bom.Components = append(bom.Components, cdxNewComponent(f.Package))
+		}
+		var key = f.ID() + "/" + string(f.status()) + "/" + string(f.Justification) + "/" + f.ImpactStatement + "/" + f.ssvcVector()
+		if idx, ok := vulns[key]; ok {
+			bom.Vulnerabilities[idx].Affects = append(bom.Vulnerabilities[idx].Affects, cdxAffect{Ref: f.Package})
+			continue
+		}
+		var v, err = cdxNewVulnerability(f)
+		if err != nil {
+			return err
+		}
+		vulns[key] = len(bom.Vulnerabilities)
+		bom.Vulnerabilities = append(bom.Vulnerabilities, v)
+	}
+	return encode(w, bom)
+}
+
+func cdxNewComponent(pkg string) cdxComponent {
+	var c = cdxComponent{
+		BOMRef: pkg,
+		Type:   "library",
+		Name:   pkg,
+	}
+	if strings.HasPrefix(pkg, "cpe:") {
+		c.CPE = pkg
+	} else {
+		c.Purl = pkg
+		// NB: the name is the part between the last slash and the version.
+		var name = pkg[strings.LastIndex(strings.SplitN(pkg, "?", 2)[0], "/")+1:]
+		name, _, _ = strings.Cut(name, "@")
+		c.Name = name
+	}
+	return c
+}
+
+func cdxNewVulnerability(f Finding) (cdxVulnerability, error) {
+	var v = f.Vulnerability
+	var r = cdxVulnerability{
+		ID:             f.ID(),
+		CWEs:           cweNumbers(v.CWEs),
+		Description:    v.Description,
+		Recommendation: f.remediation(),
+		Analysis:       cdxAnalysis{State: cdxState(f.status())},
+		Affects:        []cdxAffect{{Ref: f.Package}},
+	}
+	if v.Namespace != "" {
+		r.Source = &cdxName{Name: v.Namespace}
+	}
+	for _, a := range f.Aliases() {
+		r.References = append(r.References, cdxRef{ID: a, Source: cdxName{Name: v.Namespace}})
+	}
+	for _, c := range v.CVSS {
+		if c.Vector == nil {
+			continue
+		}
+		var s, sv = c.Vector.BaseScoreAndSeverity()
+		var rt = cdxRating{
+			Score:    s,
+			Severity: strings.ToLower(sv),
+			Method:   cvssMethod(c.Vector.GetVersion()),
+			Vector:   c.Vector.String(),
+		}
+		if c.Source != "" {
+			rt.Source = &cdxName{Name: c.Source}
+		}
+		r.Ratings = append(r.Ratings, rt)
+	}
+	for _, ref := range v.References {
+		r.Advisories = append(r.Advisories, cdxAdvisory{URL: ref.URL})
+	}
+	if !v.Published.IsZero() {
+		r.Published = timestamp(v.Published)
+	}
+	if !v.Modified.IsZero() {
+		r.Updated = timestamp(v.Modified)
+	}
+	if f.status() == StatusNotAffected {
+		// NB: either the justification or the impact statement is required by the not_affected state.
+		if f.Justification == "" && f.ImpactStatement == "" {
+			return r, fmt.Errorf("error writing %s of %s: neither justification nor impact statement of %s status",
+				f.ID(), f.Package, f.status())
+		}
+		r.Analysis.Justification = cdxJustification(f.Justification)
+		r.Analysis.Detail = f.ImpactStatement
+	}
+	if p := f.ssvcPriority(); p != "" {
+		r.Properties = append(r.Properties,
+			cdxProperty{Name: "ssvc:vector", Value: f.ssvcVector()},
+			cdxProperty{Name: "ssvc:priority", Value: p})
+	}
+	return r, nil
+}
+
+// cdxState returns the analysis state of the given Status.
+func cdxState(s Status) string {
+	switch s {
+	case StatusNotAffected:
+		return "not_affected"
+	case StatusFixed:
+		return "resolved"
+	case StatusUnderInvestigation:
+		return "in_triage"
+	}
+	return "exploitable"
+}
+
+// cdxJustification returns the analysis justification of the given Justification.
+func cdxJustification(j Justification) string {
+	switch j {
+	case JustificationComponentNotPresent, JustificationVulnerableCodeNotPresent:
+		return "code_not_present"
+	case JustificationVulnerableCodeNotInExecutePath:
+		return "code_not_reachable"
+	case JustificationVulnerableCodeCannotBeControlledByAdversary:
+		return "requires_environment"
+	case JustificationInlineMitigationsAlreadyExist:
+		return "protected_by_mitigating_control"
+	}
+	return ""
+}
+
+// reportUUID returns the given ID if it is a UUID,
+// or the UUID derived from the given ID, or a random UUID if the given ID is empty.
+func reportUUID(id string) string {
+	if id == "" {
+		return uuid.New().String()
+	}
+	if u, err := uuid.Parse(id); err == nil {
+		return u.String()
+	}
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(id)).String()
+}
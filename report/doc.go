@@ -0,0 +1,3 @@
+// Package report provides the writers of the vulnerability findings in SARIF 2.1.0, CycloneDX VEX and OpenVEX,
+// which are built from the matcher.Match list or the decoded model.WeaknessVulnerability with the SSVC decisions.
+package report
@@ -0,0 +1,109 @@
+package report
+
+import (
+	"fmt"
+	"io"
+)
+
+// OpenVEX v0.2.0 documents, according to https://github.com/openvex/spec/blob/main/OPENVEX-SPEC.md.
+type (
+	vexDocument struct {
+		Context    string         `json:"@context"`
+		ID         string         `json:"@id"`
+		Author     string         `json:"author"`
+		Timestamp  string         `json:"timestamp"`
+		Version    int            `json:"version"`
+		Tooling    string         `json:"tooling,omitempty"`
+		Statements []vexStatement `json:"statements"`
+	}
+
+	vexStatement struct {
+		Vulnerability   vexVulnerability `json:"vulnerability"`
+		Products        []vexProduct     `json:"products"`
+		Status          Status           `json:"status"`
+		Justification   Justification    `json:"justification,omitempty"`
+		ImpactStatement string           `json:"impact_statement,omitempty"`
+		ActionStatement string           `json:"action_statement,omitempty"`
+		StatusNotes     string           `json:"status_notes,omitempty"`
+	}
+
+	vexVulnerability struct {
+		Name        string   `json:"name"`
+		Description string   `json:"description,omitempty"`
+		Aliases     []string `json:"aliases,omitempty"`
+	}
+
+	vexProduct struct {
+		ID string `json:"@id"`
+	}
+)
+
+// WriteOpenVEX writes the given Report as an OpenVEX v0.2.0 document,
+// the findings of the same vulnerability in the same Status are merged into a statement,
+// the remediation is recorded as the action statement of the Finding in StatusAffected,
+// and the SSVC decision is recorded as the status notes.
+// It returns error if any Finding in StatusNotAffected has neither Justification nor ImpactStatement.
+func WriteOpenVEX(w io.Writer, r Report) error {
+	var doc = vexDocument{
+		Context:    "https://openvex.dev/ns/v0.2.0",
+		ID:         r.ID,
+		Author:     r.Author,
+		Timestamp:  timestamp(r.Timestamp),
+		Version:    1,
+		Tooling:    r.Tool.Name,
+		Statements: []vexStatement{},
+	}
+	if doc.ID == "" {
+		doc.ID = "urn:uuid:" + reportUUID("")
+	}
+	if doc.Author == "" {
+		doc.Author = "Unknown Author"
+	}
+	if r.Tool.Name != "" && r.Tool.Version != "" {
+		doc.Tooling = r.Tool.Name + "/" + r.Tool.Version
+	}
+
+	var stmts = map[string]int{}
+	for _, f := range r.Findings {
+		var key = f.ID() + "/" + string(f.status()) + "/" + string(f.Justification) + "/" + f.ImpactStatement + "/" + f.ssvcVector()
+		if idx, ok := stmts[key]; ok {
+			doc.Statements[idx].Products = append(doc.Statements[idx].Products, vexProduct{ID: f.Package})
+			continue
+		}
+		var s, err = vexNewStatement(f)
+		if err != nil {
+			return err
+		}
+		stmts[key] = len(doc.Statements)
+		doc.Statements = append(doc.Statements, s)
+	}
+	return encode(w, doc)
+}
+
+func vexNewStatement(f Finding) (vexStatement, error) {
+	var s = vexStatement{
+		Vulnerability: vexVulnerability{
+			Name:        f.ID(),
+			Description: f.summary(),
+			Aliases:     f.Aliases(),
+		},
+		Products: []vexProduct{{ID: f.Package}},
+		Status:   f.status(),
+	}
+	switch s.Status {
+	case StatusNotAffected:
+		// NB: either the justification or the impact statement is required by the not_affected status.
+		if f.Justification == "" && f.ImpactStatement == "" {
+			return s, fmt.Errorf("error writing %s of %s: neither justification nor impact statement of %s status",
+				f.ID(), f.Package, s.Status)
+		}
+		s.Justification = f.Justification
+		s.ImpactStatement = f.ImpactStatement
+	case StatusAffected:
+		s.ActionStatement = f.remediation()
+	}
+	if p := f.ssvcPriority(); p != "" {
+		s.StatusNotes = "SSVC priority " + p + ": " + f.ssvcVector()
+	}
+	return s, nil
+}
@@ -0,0 +1,273 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/seal-io/meta-api/cvss"
+	"github.com/seal-io/meta-api/matcher"
+	"github.com/seal-io/meta-api/model"
+	ssvccompatible "github.com/seal-io/meta-api/ssvc/compatible"
+)
+
+// Status is the VEX status of a Finding.
+type Status string
+
+// constants of Status.
+const (
+	StatusAffected           Status = "affected"
+	StatusNotAffected        Status = "not_affected"
+	StatusFixed              Status = "fixed"
+	StatusUnderInvestigation Status = "under_investigation"
+)
+
+// Justification is the reason of StatusNotAffected,
+// according to https://github.com/openvex/spec/blob/main/OPENVEX-SPEC.md#status-justifications.
+type Justification string
+
+// constants of Justification.
+const (
+	JustificationComponentNotPresent                         Justification = "component_not_present"
+	JustificationVulnerableCodeNotPresent                    Justification = "vulnerable_code_not_present"
+	JustificationVulnerableCodeNotInExecutePath              Justification = "vulnerable_code_not_in_execute_path"
+	JustificationVulnerableCodeCannotBeControlledByAdversary Justification = "vulnerable_code_cannot_be_controlled_by_adversary"
+	JustificationInlineMitigationsAlreadyExist               Justification = "inline_mitigations_already_exist"
+)
+
+// Tool holds the information of the tool generating the report.
+type Tool struct {
+	// Name is the name of the tool.
+	Name string
+	// Version is the version of the tool, optional.
+	Version string
+	// InformationURI is the homepage of the tool, optional.
+	InformationURI string
+}
+
+// Report holds the findings to write.
+type Report struct {
+	// ID is the identity of the report, optional,
+	// which is used as the serial number of CycloneDX VEX and the @id of OpenVEX,
+	// a random one is generated if empty.
+	ID string
+	// Author is the author of the report, optional.
+	Author string
+	// Timestamp is the generating time of the report, default is now.
+	Timestamp time.Time
+	// Tool is the tool generating the report.
+	Tool Tool
+	// Findings is the vulnerabilities affecting the packages.
+	Findings []Finding
+}
+
+// Finding holds a vulnerability of a package.
+type Finding struct {
+	// Package is the package URL or the CPE name of the package, e.g. "pkg:npm/lodash@4.17.15".
+	Package string
+	// Location is the path of the file declaring the package, optional, e.g. "package-lock.json".
+	Location string
+	// Vulnerability is the decoded vulnerability.
+	Vulnerability model.WeaknessVulnerability
+	// SSVC is the SSVC decision of the vulnerability, optional.
+	SSVC ssvccompatible.Vector
+	// Status is the VEX status, default is StatusAffected.
+	Status Status
+	// Justification is the reason of StatusNotAffected,
+	// either Justification or ImpactStatement is required by StatusNotAffected.
+	Justification Justification
+	// ImpactStatement explains why the package is not affected in StatusNotAffected in free text,
+	// either Justification or ImpactStatement is required by StatusNotAffected.
+	ImpactStatement string
+	// Reason is the reason of matching, optional.
+	Reason matcher.Reason
+	// Confidence is the confidence of matching, optional.
+	Confidence float64
+}
+
+// FromMatches returns the findings of the given matcher.Match list in StatusAffected,
+// the vulnerability tag of the Match via CPE is converted as the vulnerability.
+func FromMatches(ms []matcher.Match) []Finding {
+	var r = make([]Finding, 0, len(ms))
+	for i := range ms {
+		var f = Finding{
+			Package:    ms[i].Package.PackageURL,
+			Status:     StatusAffected,
+			Reason:     ms[i].Reason,
+			Confidence: ms[i].Confidence,
+		}
+		if f.Package == "" && len(ms[i].Package.CPEs) != 0 {
+			f.Package = ms[i].Package.CPEs[0]
+		}
+		switch {
+		case ms[i].Vulnerability != nil:
+			// NB: the undecodable fields are ignored.
+			f.Vulnerability, _ = model.DecodeWeaknessVulnerability(ms[i].Vulnerability)
+		case ms[i].Tag != nil:
+			var t, _ = model.DecodeWeaknessVulnerabilityTag(ms[i].Tag)
+			f.Vulnerability = model.WeaknessVulnerability{
+				Name:        t.Name,
+				CreateTime:  t.CreateTime,
+				UpdateTime:  t.UpdateTime,
+				Code:        t.Name,
+				Description: t.Description,
+				References:  t.References,
+				CVSS:        t.CVSS,
+				CWEs:        t.CWEs,
+				EPSS:        t.EPSS,
+				Published:   t.Published,
+				Modified:    t.Modified,
+			}
+		}
+		r = append(r, f)
+	}
+	return r
+}
+
+// ID returns the identity of the vulnerability, which is the code or the name.
+func (in Finding) ID() string {
+	if in.Vulnerability.Code != "" {
+		return in.Vulnerability.Code
+	}
+	return in.Vulnerability.Name
+}
+
+// Aliases returns the other identities of the vulnerability except the ID.
+func (in Finding) Aliases() []string {
+	var id = in.ID()
+	var r []string
+	for _, s := range append([]string{in.Vulnerability.Name}, in.Vulnerability.Tags...) {
+		if s == "" || s == id || contains(r, s) {
+			continue
+		}
+		r = append(r, s)
+	}
+	return r
+}
+
+func (in Finding) status() Status {
+	if in.Status == "" {
+		return StatusAffected
+	}
+	return in.Status
+}
+
+// severity returns the base score and the upper-case base severity of the highest CVSS,
+// returns zero and empty if no CVSS.
+func (in Finding) severity() (float64, string) {
+	var m = in.Vulnerability.MaxCVSS()
+	if m == nil {
+		return 0, ""
+	}
+	var s, sv = m.Vector.BaseScoreAndSeverity()
+	return s, strings.ToUpper(sv)
+}
+
+// remediation returns the brief remediation of the vulnerability.
+func (in Finding) remediation() string {
+	var v = in.Vulnerability
+	switch {
+	case len(v.Patched) != 0:
+		return "Upgrade to " + strings.Join(v.Patched, " or ") + "."
+	case v.Mitigation != "":
+		return v.Mitigation
+	}
+	return "No remediation is available."
+}
+
+// summary returns the brief summary of the vulnerability.
+func (in Finding) summary() string {
+	var v = in.Vulnerability
+	switch {
+	case v.Summary != "":
+		return v.Summary
+	case v.Description != "":
+		var s, _, _ = strings.Cut(v.Description, "\n")
+		return s
+	}
+	return in.ID()
+}
+
+// ssvcVector returns the SSVC vector string, returns empty if no SSVC.
+func (in Finding) ssvcVector() string {
+	if in.SSVC == nil || in.SSVC.IsZero() {
+		return ""
+	}
+	return in.SSVC.String()
+}
+
+// ssvcPriority returns the full name of the SSVC priority, returns empty if no SSVC.
+func (in Finding) ssvcPriority() string {
+	if in.SSVC == nil || in.SSVC.IsZero() {
+		return ""
+	}
+	switch p := in.SSVC.Priority(); p {
+	case "D":
+		return "Defer"
+	case "S":
+		return "Scheduled"
+	case "O":
+		return "Out-of-Cycle"
+	case "I":
+		return "Immediate"
+	default:
+		return p
+	}
+}
+
+// cvssMethod returns the rating method of the given CVSS version in CycloneDX 1.4,
+// which has no method of CVSS 4.0, so it is rated as other.
+func cvssMethod(version string) string {
+	switch version {
+	case "2.0":
+		return "CVSSv2"
+	case "3.0":
+		return "CVSSv3"
+	case "3.1":
+		return "CVSSv31"
+	}
+	return "other"
+}
+
+// cweNumbers returns the numbers of the given CWE IDs, the invalid ones are skipped.
+func cweNumbers(cwes []string) []int {
+	var r []int
+	for i := range cwes {
+		var n, err = strconv.Atoi(strings.TrimPrefix(cwes[i], "CWE-"))
+		if err != nil {
+			continue
+		}
+		r = append(r, n)
+	}
+	return r
+}
+
+// severityRank returns the rank of the given severity, the higher is more severe.
+func severityRank(s string) int {
+	return cvss.GetSeverityNumber(s)
+}
+
+func timestamp(t time.Time) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func encode(w io.Writer, v any) error {
+	var enc = json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func contains(ss []string, s string) bool {
+	for i := range ss {
+		if ss[i] == s {
+			return true
+		}
+	}
+	return false
+}
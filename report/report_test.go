@@ -0,0 +1,346 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/seal-io/meta-api/matcher"
+	"github.com/seal-io/meta-api/model"
+	"github.com/seal-io/meta-api/schema"
+	"github.com/seal-io/meta-api/ssvc"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestWrite(t *testing.T) {
+	var r = testingReport(t)
+
+	var testCases = []struct {
+		given    func(io.Writer, Report) error
+		expected string
+	}{
+		{given: WriteSARIF, expected: "report.sarif.json"},
+		{given: WriteCycloneDXVEX, expected: "report.cdx.json"},
+		{given: WriteOpenVEX, expected: "report.openvex.json"},
+	}
+	for _, c := range testCases {
+		var actual bytes.Buffer
+		if err := c.given(&actual, r); err != nil {
+			t.Fatalf("error writing %s: %v", c.expected, err)
+		}
+		var golden = filepath.Join("testdata", c.expected)
+		if *update {
+			if err := os.WriteFile(golden, actual.Bytes(), 0o644); err != nil {
+				t.Fatalf("error updating %s: %v", golden, err)
+			}
+			continue
+		}
+		var expected, err = os.ReadFile(golden)
+		if err != nil {
+			t.Fatalf("error reading %s: %v", golden, err)
+		}
+		if !bytes.Equal(expected, actual.Bytes()) {
+			t.Errorf("expected %s as\n%s\nbut got\n%s", c.expected, expected, actual.Bytes())
+		}
+	}
+}
+
+func TestWriteOpenVEX_notAffected(t *testing.T) {
+	var minimist = model.WeaknessVulnerability{Name: "CVE-2021-44906", Code: "CVE-2021-44906"}
+	type output struct {
+		justification   string
+		impactStatement string
+		err             bool
+	}
+	var testCases = []struct {
+		given    Finding
+		expected output
+	}{
+		{
+			given: Finding{
+				Package:       "pkg:npm/minimist@1.2.5",
+				Vulnerability: minimist,
+				Status:        StatusNotAffected,
+				Justification: JustificationVulnerableCodeNotInExecutePath,
+			},
+			expected: output{
+				justification: "vulnerable_code_not_in_execute_path",
+			},
+		},
+		{
+			given: Finding{
+				Package:         "pkg:npm/minimist@1.2.5",
+				Vulnerability:   minimist,
+				Status:          StatusNotAffected,
+				ImpactStatement: "The arguments are never parsed from the untrusted input.",
+			},
+			expected: output{
+				impactStatement: "The arguments are never parsed from the untrusted input.",
+			},
+		},
+		{
+			given: Finding{
+				Package:       "pkg:npm/minimist@1.2.5",
+				Vulnerability: minimist,
+				Status:        StatusNotAffected,
+			},
+			expected: output{
+				err: true,
+			},
+		},
+	}
+	for i, c := range testCases {
+		var buf bytes.Buffer
+		var err = WriteOpenVEX(&buf, Report{Findings: []Finding{c.given}})
+		var actual = output{err: err != nil}
+		if err == nil {
+			var doc vexDocument
+			if err = json.Unmarshal(buf.Bytes(), &doc); err != nil {
+				t.Fatalf("#%d error decoding: %v", i+1, err)
+			}
+			actual.justification = string(doc.Statements[0].Justification)
+			actual.impactStatement = doc.Statements[0].ImpactStatement
+		}
+		if actual != c.expected {
+			t.Errorf("#%d expected %+v, but got %+v", i+1, c.expected, actual)
+		}
+	}
+}
+
+func TestWriteCycloneDXVEX_notAffected(t *testing.T) {
+	var minimist = model.WeaknessVulnerability{Name: "CVE-2021-44906", Code: "CVE-2021-44906"}
+	type output struct {
+		vulnerabilities int
+		justification   string
+		detail          string
+		err             bool
+	}
+	var testCases = []struct {
+		given    []Finding
+		expected output
+	}{
+		{
+			given: []Finding{
+				{
+					Package:       "pkg:npm/minimist@1.2.5",
+					Vulnerability: minimist,
+					Status:        StatusNotAffected,
+					Justification: JustificationVulnerableCodeNotInExecutePath,
+				},
+				{
+					Package:       "pkg:npm/minimist@1.2.6",
+					Vulnerability: minimist,
+					Status:        StatusNotAffected,
+					Justification: JustificationVulnerableCodeNotInExecutePath,
+				},
+			},
+			expected: output{
+				vulnerabilities: 1,
+				justification:   "code_not_reachable",
+			},
+		},
+		{
+			given: []Finding{
+				{
+					Package:         "pkg:npm/minimist@1.2.5",
+					Vulnerability:   minimist,
+					Status:          StatusNotAffected,
+					ImpactStatement: "The arguments are never parsed from the untrusted input.",
+				},
+				{
+					Package:       "pkg:npm/minimist@1.2.6",
+					Vulnerability: minimist,
+					Status:        StatusNotAffected,
+					Justification: JustificationVulnerableCodeNotInExecutePath,
+				},
+			},
+			expected: output{
+				vulnerabilities: 2,
+				detail:          "The arguments are never parsed from the untrusted input.",
+			},
+		},
+		{
+			given: []Finding{
+				{
+					Package:       "pkg:npm/minimist@1.2.5",
+					Vulnerability: minimist,
+					Status:        StatusNotAffected,
+				},
+			},
+			expected: output{
+				err: true,
+			},
+		},
+	}
+	for i, c := range testCases {
+		var buf bytes.Buffer
+		var err = WriteCycloneDXVEX(&buf, Report{Findings: c.given})
+		var actual = output{err: err != nil}
+		if err == nil {
+			var bom cdxBOM
+			if err = json.Unmarshal(buf.Bytes(), &bom); err != nil {
+				t.Fatalf("#%d error decoding: %v", i+1, err)
+			}
+			actual.vulnerabilities = len(bom.Vulnerabilities)
+			actual.justification = bom.Vulnerabilities[0].Analysis.Justification
+			actual.detail = bom.Vulnerabilities[0].Analysis.Detail
+		}
+		if actual != c.expected {
+			t.Errorf("#%d expected %+v, but got %+v", i+1, c.expected, actual)
+		}
+	}
+}
+
+func TestCvssMethod(t *testing.T) {
+	var testCases = []struct {
+		given    string
+		expected string
+	}{
+		{given: "2.0", expected: "CVSSv2"},
+		{given: "3.0", expected: "CVSSv3"},
+		{given: "3.1", expected: "CVSSv31"},
+		{given: "4.0", expected: "other"},
+		{given: "5.0", expected: "other"},
+	}
+	for _, c := range testCases {
+		if actual := cvssMethod(c.given); actual != c.expected {
+			t.Errorf("cvssMethod(%q) == %s, but got %s", c.given, c.expected, actual)
+		}
+	}
+}
+
+func TestFromMatches(t *testing.T) {
+	var given = []matcher.Match{
+		{
+			Package:    matcher.Package{PackageURL: "pkg:npm/lodash@4.17.15"},
+			Reason:     matcher.ReasonPurl,
+			Confidence: 1,
+			Vulnerability: &schema.WeaknessVulnerability{
+				Namespace: "nvd",
+				Name:      "CVE-2021-23337",
+				Purl:      "pkg:npm/lodash",
+				Patched:   []byte(`["4.17.21"]`),
+			},
+		},
+		{
+			Package:    matcher.Package{CPEs: []string{"cpe:2.3:a:openssl:openssl:1.1.1k:*:*:*:*:*:*:*"}},
+			Reason:     matcher.ReasonCPE,
+			Confidence: 0.6,
+			Tag: &schema.WeaknessVulnerabilityTag{
+				Name:        "CVE-2021-3711",
+				Description: "SM2 decryption buffer overflow.",
+			},
+		},
+	}
+	var expected = []string{
+		"pkg:npm/lodash@4.17.15 CVE-2021-23337 affected purl 1: CVE-2021-23337, Upgrade to 4.17.21.",
+		"cpe:2.3:a:openssl:openssl:1.1.1k:*:*:*:*:*:*:* CVE-2021-3711 affected cpe 0.6: " +
+			"SM2 decryption buffer overflow., No remediation is available.",
+	}
+
+	var actual = FromMatches(given)
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %d findings, but got %d", len(expected), len(actual))
+	}
+	for i := range actual {
+		var f = actual[i]
+		var s = fmt.Sprintf("%s %s %s %s %v: %s, %s",
+			f.Package, f.ID(), f.status(), f.Reason, f.Confidence, f.summary(), f.remediation())
+		if s != expected[i] {
+			t.Errorf("expected finding %d as %q, but got %q", i, expected[i], s)
+		}
+	}
+}
+
+func testingReport(t *testing.T) Report {
+	t.Helper()
+
+	var sv, err = ssvc.Parse("SSVCv2/E:P/X:O/A:N/V:D/U:L/S:N/M:N/H:L/P:D/1667541906/")
+	if err != nil {
+		t.Fatalf("error parsing ssvc: %v", err)
+	}
+	var lodash, _ = model.DecodeWeaknessVulnerability(&schema.WeaknessVulnerability{
+		Namespace:   "github",
+		Name:        "GHSA-35jh-r3h4-6jhm",
+		Purl:        "pkg:npm/lodash",
+		Tags:        []byte(`["CVE-2021-23337"]`),
+		Code:        "GHSA-35jh-r3h4-6jhm",
+		Description: "Command Injection in lodash.\nlodash versions prior to 4.17.21 are vulnerable to Command Injection via the template function.",
+		References:  []byte(`["https://github.com/advisories/GHSA-35jh-r3h4-6jhm","https://nvd.nist.gov/vuln/detail/CVE-2021-23337"]`),
+		Affected:    "<4.17.21",
+		Patched:     []byte(`["4.17.21"]`),
+		Cvss:        []byte(`[{"source":"github","vector":"CVSS:3.1/AV:N/AC:L/PR:H/UI:N/S:U/C:H/I:H/A:H"}]`),
+		Cwes:        []byte(`["CWE-77","CWE-94"]`),
+	})
+	var openssl, _ = model.DecodeWeaknessVulnerabilityTag(&schema.WeaknessVulnerabilityTag{
+		Name:        "CVE-2021-3711",
+		Description: "SM2 decryption buffer overflow.",
+		References:  []byte(`["https://www.openssl.org/news/secadv/20210824.txt"]`),
+		Cvsses:      []byte(`[{"source":"nvd","vector":"AV:N/AC:L/Au:N/C:P/I:P/A:P"}]`),
+	})
+	var minimist, _ = model.DecodeWeaknessVulnerability(&schema.WeaknessVulnerability{
+		Namespace:  "nvd",
+		Name:       "CVE-2021-44906",
+		Purl:       "pkg:npm/minimist",
+		Code:       "CVE-2021-44906",
+		Summary:    "Prototype Pollution in minimist.",
+		Mitigation: "Avoid passing untrusted arguments.",
+		Cvss:       []byte(`[{"source":"nvd","vector":"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:L/I:L/A:L"}]`),
+	})
+
+	return Report{
+		ID:        "https://example.com/reports/acme-app",
+		Author:    "ACME Security",
+		Timestamp: time.Date(2023, 3, 1, 8, 0, 0, 0, time.UTC),
+		Tool: Tool{
+			Name:           "meta-scanner",
+			Version:        "0.1.0",
+			InformationURI: "https://example.com/meta-scanner",
+		},
+		Findings: []Finding{
+			{
+				Package:       "pkg:npm/lodash@4.17.15",
+				Location:      "package-lock.json",
+				Vulnerability: lodash,
+				SSVC:          sv,
+				Reason:        matcher.ReasonPurl,
+				Confidence:    1,
+			},
+			{
+				Package:       "pkg:npm/lodash@4.17.20",
+				Location:      "packages/web/package-lock.json",
+				Vulnerability: lodash,
+				SSVC:          sv,
+				Reason:        matcher.ReasonPurl,
+				Confidence:    1,
+			},
+			{
+				Package: "cpe:2.3:a:openssl:openssl:1.1.1k:*:*:*:*:*:*:*",
+				Vulnerability: model.WeaknessVulnerability{
+					Name:        openssl.Name,
+					Code:        openssl.Name,
+					Description: openssl.Description,
+					References:  openssl.References,
+					CVSS:        openssl.CVSS,
+				},
+				Status:     StatusUnderInvestigation,
+				Reason:     matcher.ReasonCPE,
+				Confidence: 0.6,
+			},
+			{
+				Package:       "pkg:npm/minimist@1.2.5",
+				Location:      "package-lock.json",
+				Vulnerability: minimist,
+				Status:        StatusNotAffected,
+				Justification: JustificationVulnerableCodeNotInExecutePath,
+			},
+		},
+	}
+}
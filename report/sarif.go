@@ -0,0 +1,204 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SARIF 2.1.0 documents, according to https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		Version        string      `json:"version,omitempty"`
+		InformationURI string      `json:"informationUri,omitempty"`
+		Rules          []sarifRule `json:"rules"`
+	}
+
+	sarifRule struct {
+		ID               string         `json:"id"`
+		ShortDescription sarifText      `json:"shortDescription"`
+		FullDescription  *sarifText     `json:"fullDescription,omitempty"`
+		HelpURI          string         `json:"helpUri,omitempty"`
+		Help             *sarifText     `json:"help,omitempty"`
+		Properties       map[string]any `json:"properties,omitempty"`
+	}
+
+	sarifText struct {
+		Text string `json:"text"`
+	}
+
+	sarifResult struct {
+		RuleID       string             `json:"ruleId"`
+		RuleIndex    int                `json:"ruleIndex"`
+		Level        string             `json:"level"`
+		Message      sarifText          `json:"message"`
+		Locations    []sarifLocation    `json:"locations"`
+		Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+		Properties   map[string]any     `json:"properties,omitempty"`
+	}
+
+	sarifLocation struct {
+		PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+		LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+	}
+
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	}
+
+	sarifArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+
+	sarifLogicalLocation struct {
+		Name string `json:"name"`
+		Kind string `json:"kind"`
+	}
+
+	sarifSuppression struct {
+		Kind          string `json:"kind"`
+		Status        string `json:"status"`
+		Justification string `json:"justification,omitempty"`
+	}
+)
+
+// WriteSARIF writes the given Report as a SARIF 2.1.0 log with a run,
+// each vulnerability is a rule and each Finding is a result,
+// the Finding in StatusNotAffected is suppressed, and the Finding in StatusFixed is omitted.
+// The level of the result is "error" for critical and high severity, "warning" for medium or unknown severity,
+// and "note" for the others, the base score of the highest CVSS is recorded as the "security-severity" of the rule.
+func WriteSARIF(w io.Writer, r Report) error {
+	var run = sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           r.Tool.Name,
+				Version:        r.Tool.Version,
+				InformationURI: r.Tool.InformationURI,
+				Rules:          []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+	var rules = map[string]int{}
+	for _, f := range r.Findings {
+		if f.status() == StatusFixed {
+			continue
+		}
+		var id = f.ID()
+		var idx, ok = rules[id]
+		if !ok {
+			idx = len(run.Tool.Driver.Rules)
+			rules[id] = idx
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, newSARIFRule(f))
+		}
+
+		var verb = "is affected by"
+		if f.status() == StatusNotAffected {
+			verb = "is not affected by"
+		}
+		var score, severity = f.severity()
+		var res = sarifResult{
+			RuleID:    id,
+			RuleIndex: idx,
+			Level:     sarifLevel(severity),
+			Message: sarifText{
+				Text: fmt.Sprintf("%s %s %s: %s", f.Package, verb, id, f.summary()),
+			},
+			Locations: []sarifLocation{
+				{
+					LogicalLocations: []sarifLogicalLocation{{Name: f.Package, Kind: "package"}},
+				},
+			},
+			Properties: map[string]any{},
+		}
+		if f.Location != "" {
+			res.Locations[0].PhysicalLocation = &sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: f.Location},
+			}
+		}
+		if f.status() == StatusNotAffected {
+			var justification = string(f.Justification)
+			if justification == "" {
+				justification = f.ImpactStatement
+			}
+			res.Suppressions = []sarifSuppression{
+				{Kind: "external", Status: "accepted", Justification: justification},
+			}
+		}
+		if severity != "" {
+			res.Properties["cvssScore"] = score
+			res.Properties["cvssSeverity"] = severity
+		}
+		if p := f.ssvcPriority(); p != "" {
+			res.Properties["ssvcVector"] = f.ssvcVector()
+			res.Properties["ssvcPriority"] = p
+		}
+		if f.Reason != "" {
+			res.Properties["matchReason"] = string(f.Reason)
+			res.Properties["matchConfidence"] = f.Confidence
+		}
+		if len(res.Properties) == 0 {
+			res.Properties = nil
+		}
+		run.Results = append(run.Results, res)
+	}
+	return encode(w, sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+// newSARIFRule converts the vulnerability of the given Finding as a rule.
+func newSARIFRule(f Finding) sarifRule {
+	var v = f.Vulnerability
+	var r = sarifRule{
+		ID:               f.ID(),
+		ShortDescription: sarifText{Text: f.summary()},
+		Properties: map[string]any{
+			"tags": append([]string{"security"}, v.CWEs...),
+		},
+	}
+	if v.Description != "" {
+		r.FullDescription = &sarifText{Text: v.Description}
+	}
+	if len(v.References) != 0 {
+		r.HelpURI = v.References[0].URL
+	}
+	var help strings.Builder
+	help.WriteString(f.remediation())
+	if m := v.MaxCVSS(); m != nil {
+		fmt.Fprintf(&help, "\nCVSS: %s", m.Vector.String())
+		r.Properties["security-severity"] = fmt.Sprintf("%.1f", m.Vector.BaseScore())
+	}
+	r.Help = &sarifText{Text: help.String()}
+	return r
+}
+
+func sarifLevel(severity string) string {
+	switch {
+	case severity == "":
+		return "warning"
+	case severityRank(severity) >= severityRank("HIGH"):
+		return "error"
+	case severityRank(severity) >= severityRank("MEDIUM"):
+		return "warning"
+	}
+	return "note"
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy holds the policy of retrying the failed window during ingesting,
+// the failed stream is re-established and the failed window is re-requested.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum attempts of requesting one window, including the first one,
+	// no retrying if it is not greater than 1.
+	MaxAttempts int
+	// InitialBackoff is the duration to wait before the first retrying.
+	InitialBackoff time.Duration
+	// MaxBackoff is the upper limit of the duration to wait.
+	MaxBackoff time.Duration
+	// BackoffMultiplier is the factor to grow the duration to wait after each retrying,
+	// treats as 1 if it is less than 1.
+	BackoffMultiplier float64
+	// Jitter is the ratio in range of [0, 1] to randomize the duration to wait,
+	// e.g. 0.2 means the duration to wait is randomized in ±20%.
+	Jitter float64
+	// RetryableCodes is the gRPC status codes that can be retried.
+	RetryableCodes []codes.Code
+}
+
+// DefaultRetryPolicy returns a default RetryPolicy,
+// which retries at most 4 times in exponential backoff from 1s to 30s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:       5,
+		InitialBackoff:    time.Second,
+		MaxBackoff:        30 * time.Second,
+		BackoffMultiplier: 2,
+		Jitter:            0.2,
+		RetryableCodes: []codes.Code{
+			codes.Unavailable,
+			codes.ResourceExhausted,
+			codes.Aborted,
+			codes.Internal,
+		},
+	}
+}
+
+// IsRetryable returns true if the given error can be retried at the given attempts.
+func (p RetryPolicy) IsRetryable(err error, attempts int) bool {
+	if err == nil || attempts >= p.MaxAttempts {
+		return false
+	}
+	var se interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &se) {
+		return false
+	}
+	var c = se.GRPCStatus().Code()
+	for i := range p.RetryableCodes {
+		if p.RetryableCodes[i] == c {
+			return true
+		}
+	}
+	return false
+}
+
+// Backoff returns the duration to wait before the given attempts.
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	if attempts <= 0 || p.InitialBackoff <= 0 {
+		return 0
+	}
+	var m = p.BackoffMultiplier
+	if m < 1 {
+		m = 1
+	}
+	var d = float64(p.InitialBackoff) * math.Pow(m, float64(attempts-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if j := math.Min(math.Max(p.Jitter, 0), 1); j > 0 {
+		// nolint:gosec
+		d = d * (1 + j*(2*rand.Float64()-1))
+	}
+	return time.Duration(d)
+}
+
+// wait blocks until the given duration passed or the given context canceled.
+func wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	var t = time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+	}
+	return nil
+}
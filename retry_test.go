@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/seal-io/meta-api/schema"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	var p = RetryPolicy{
+		InitialBackoff:    100 * time.Millisecond,
+		MaxBackoff:        time.Second,
+		BackoffMultiplier: 2,
+	}
+	var testCases = []struct {
+		given    int
+		expected time.Duration
+	}{
+		{given: 0, expected: 0},
+		{given: 1, expected: 100 * time.Millisecond},
+		{given: 2, expected: 200 * time.Millisecond},
+		{given: 4, expected: 800 * time.Millisecond},
+		{given: 5, expected: time.Second},
+		{given: 10, expected: time.Second},
+	}
+	for _, c := range testCases {
+		var actual = p.Backoff(c.given)
+		if actual != c.expected {
+			t.Errorf("Backoff(%d) == %v, but got %v", c.given, c.expected, actual)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		var actual = p.Backoff(2)
+		if actual < 100*time.Millisecond || actual > 300*time.Millisecond {
+			t.Fatalf("Backoff(2) with jitter should be in [100ms, 300ms], but got %v", actual)
+		}
+	}
+}
+
+func TestRetryPolicy_IsRetryable(t *testing.T) {
+	var p = DefaultRetryPolicy()
+	var testCases = []struct {
+		given    error
+		attempts int
+		expected bool
+	}{
+		{given: nil, attempts: 1, expected: false},
+		{given: errors.New("x"), attempts: 1, expected: false},
+		{given: status.Error(codes.Unavailable, "x"), attempts: 1, expected: true},
+		{given: fmt.Errorf("wrapped: %w", status.Error(codes.Unavailable, "x")), attempts: 1, expected: true},
+		{given: status.Error(codes.Unavailable, "x"), attempts: p.MaxAttempts, expected: false},
+		{given: status.Error(codes.InvalidArgument, "x"), attempts: 1, expected: false},
+	}
+	for _, c := range testCases {
+		var actual = p.IsRetryable(c.given, c.attempts)
+		if actual != c.expected {
+			t.Errorf("IsRetryable(%v, %d) == %v, but got %v", c.given, c.attempts, c.expected, actual)
+		}
+	}
+}
+
+func TestClient_Ingest_WithRetry(t *testing.T) {
+	var typ = schema.DatasetIngestRequestType_Compliance_License_Tag
+	var policy = DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxAttempts = 3
+
+	type output struct {
+		parsed    []int32
+		requested []int32
+		err       codes.Code
+	}
+	var testCases = []struct {
+		name     string
+		given    func(failures *int32) func(req *schema.DatasetIngestRequest) error
+		expected output
+	}{
+		{
+			name: "recover from transient failures",
+			given: func(failures *int32) func(req *schema.DatasetIngestRequest) error {
+				return func(req *schema.DatasetIngestRequest) error {
+					if req.GetWindow() == 1 && atomic.AddInt32(failures, 1) <= 2 {
+						return status.Error(codes.Unavailable, "transient")
+					}
+					return nil
+				}
+			},
+			expected: output{
+				parsed:    []int32{0, 1, 2},
+				requested: []int32{0, 1, 1, 1, 2},
+				err:       codes.OK,
+			},
+		},
+		{
+			name: "exceed max attempts",
+			given: func(failures *int32) func(req *schema.DatasetIngestRequest) error {
+				return func(req *schema.DatasetIngestRequest) error {
+					if req.GetWindow() == 1 {
+						return status.Error(codes.Unavailable, "persistent")
+					}
+					return nil
+				}
+			},
+			expected: output{
+				parsed:    []int32{0},
+				requested: []int32{0, 1, 1, 1},
+				err:       codes.Unavailable,
+			},
+		},
+		{
+			name: "non-retryable failure",
+			given: func(failures *int32) func(req *schema.DatasetIngestRequest) error {
+				return func(req *schema.DatasetIngestRequest) error {
+					if req.GetWindow() == 1 {
+						return status.Error(codes.InvalidArgument, "invalid")
+					}
+					return nil
+				}
+			},
+			expected: output{
+				parsed:    []int32{0},
+				requested: []int32{0, 1},
+				err:       codes.InvalidArgument,
+			},
+		},
+	}
+	for _, c := range testCases {
+		var failures int32
+		var srv = &testingDatasetServer{
+			windows: map[schema.DatasetIngestRequestType][]schema.DatasetIngestResponseBody{
+				typ: testingLicenseTagWindows(3),
+			},
+			intercept: c.given(&failures),
+		}
+		var cli = testingClient(t, srv)
+		var actual output
+		var err = cli.Ingest(context.Background(), typ, time.Time{}, func(currentWindow int32, _ schema.DatasetIngestResponseBody) error {
+			actual.parsed = append(actual.parsed, currentWindow)
+			return nil
+		}, WithRetry(policy))
+		var se interface{ GRPCStatus() *status.Status }
+		if errors.As(err, &se) {
+			actual.err = se.GRPCStatus().Code()
+		} else if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+		actual.requested = srv.requestedWindows(typ)
+		if fmt.Sprint(actual) != fmt.Sprint(c.expected) {
+			t.Errorf("%s: expected %v, but got %v", c.name, c.expected, actual)
+		}
+	}
+}
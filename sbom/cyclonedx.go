@@ -0,0 +1,141 @@
+package sbom
+
+import (
+	"encoding/json"
+	"encoding/xml"
+)
+
+// cdxBOM is the subset of the CycloneDX BOM,
+// according to https://cyclonedx.org/docs/1.4/json and https://cyclonedx.org/docs/1.4/xml.
+type cdxBOM struct {
+	Metadata struct {
+		Component *cdxComponent `json:"component" xml:"component"`
+	} `json:"metadata" xml:"metadata"`
+	Components   []cdxComponent  `json:"components" xml:"components>component"`
+	Dependencies []cdxDependency `json:"dependencies" xml:"dependencies>dependency"`
+}
+
+type cdxComponent struct {
+	BOMRef     string         `json:"bom-ref" xml:"bom-ref,attr"`
+	Name       string         `json:"name" xml:"name"`
+	Version    string         `json:"version" xml:"version"`
+	Purl       string         `json:"purl" xml:"purl"`
+	CPE        string         `json:"cpe" xml:"cpe"`
+	Licenses   cdxLicenses    `json:"licenses" xml:"licenses"`
+	Components []cdxComponent `json:"components" xml:"components>component"`
+}
+
+// cdxLicenses is the license choices, which is either a list of licenses or an expression.
+type cdxLicenses []string
+
+func (in *cdxLicenses) UnmarshalJSON(bs []byte) error {
+	var ls []struct {
+		License *struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"license"`
+		Expression string `json:"expression"`
+	}
+	var err = json.Unmarshal(bs, &ls)
+	if err != nil {
+		return err
+	}
+	for i := range ls {
+		switch {
+		case ls[i].Expression != "":
+			*in = append(*in, ls[i].Expression)
+		case ls[i].License == nil:
+		case ls[i].License.ID != "":
+			*in = append(*in, ls[i].License.ID)
+		case ls[i].License.Name != "":
+			*in = append(*in, ls[i].License.Name)
+		}
+	}
+	return nil
+}
+
+func (in *cdxLicenses) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var ls struct {
+		Licenses []struct {
+			ID   string `xml:"id"`
+			Name string `xml:"name"`
+		} `xml:"license"`
+		Expressions []string `xml:"expression"`
+	}
+	var err = d.DecodeElement(&ls, &start)
+	if err != nil {
+		return err
+	}
+	for i := range ls.Licenses {
+		switch {
+		case ls.Licenses[i].ID != "":
+			*in = append(*in, ls.Licenses[i].ID)
+		case ls.Licenses[i].Name != "":
+			*in = append(*in, ls.Licenses[i].Name)
+		}
+	}
+	for i := range ls.Expressions {
+		if ls.Expressions[i] != "" {
+			*in = append(*in, ls.Expressions[i])
+		}
+	}
+	return nil
+}
+
+type cdxDependency struct {
+	Ref       string   `json:"ref" xml:"ref,attr"`
+	DependsOn []string `json:"dependsOn"`
+	// Dependencies is the nested dependencies of XML.
+	Dependencies []cdxDependency `json:"-" xml:"dependency"`
+}
+
+func parseCycloneDXJSON(bs []byte) (*Document, error) {
+	var bom cdxBOM
+	var err = json.Unmarshal(bs, &bom)
+	if err != nil {
+		return nil, err
+	}
+	return bom.build(), nil
+}
+
+func parseCycloneDXXML(bs []byte) (*Document, error) {
+	var bom cdxBOM
+	var err = xml.Unmarshal(bs, &bom)
+	if err != nil {
+		return nil, err
+	}
+	return bom.build(), nil
+}
+
+func (in cdxBOM) build() *Document {
+	var b builder
+	if c := in.Metadata.Component; c != nil {
+		b.d.Name = c.Name
+		b.d.Root = c.BOMRef
+	}
+	var walk func(cs []cdxComponent)
+	walk = func(cs []cdxComponent) {
+		for i := range cs {
+			var c = Component{
+				Ref:      cs[i].BOMRef,
+				Name:     cs[i].Name,
+				Version:  cs[i].Version,
+				Licenses: cs[i].Licenses,
+			}
+			if c.Ref == "" {
+				c.Ref = cs[i].Purl
+			}
+			b.addComponent(c, cs[i].Purl, []string{cs[i].CPE})
+			walk(cs[i].Components)
+		}
+	}
+	walk(in.Components)
+	for _, d := range in.Dependencies {
+		var dependsOn = d.DependsOn
+		for i := range d.Dependencies {
+			dependsOn = append(dependsOn, d.Dependencies[i].Ref)
+		}
+		b.addDependency(d.Ref, dependsOn...)
+	}
+	return b.build()
+}
@@ -0,0 +1,3 @@
+// Package sbom provides the offline parsing of the SBOM documents in CycloneDX JSON/XML and SPDX JSON/tag-value,
+// which extracts the components with the package URLs, the CPE names, the licenses and the dependency relationships.
+package sbom
@@ -0,0 +1,281 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/seal-io/meta-api/packageurl"
+	"github.com/seal-io/meta-api/wfn"
+)
+
+// Format is the format of the SBOM document.
+type Format string
+
+// constants of Format.
+const (
+	FormatCycloneDXJSON Format = "cyclonedx-json"
+	FormatCycloneDXXML  Format = "cyclonedx-xml"
+	FormatSPDXJSON      Format = "spdx-json"
+	FormatSPDXTagValue  Format = "spdx-tag-value"
+)
+
+// ErrUnknownFormat is returned if the format of the SBOM document cannot be detected.
+var ErrUnknownFormat = errors.New("unknown sbom format")
+
+// Document is the parsed SBOM document.
+type Document struct {
+	// Format is the format of the document.
+	Format Format
+	// Name is the name of the described component, or the name of the document if no described component, optional.
+	Name string
+	// Root is the reference of the described component, optional,
+	// the described component is not included in the Components.
+	Root string
+	// Components is the components in order of appearance, the nested components are flattened.
+	Components []Component
+	// Dependencies is the dependency relationships between the components.
+	Dependencies []Dependency
+	// Issues is the problems of the components found on parsing.
+	Issues []Issue
+}
+
+// Component holds the identities of a component in the SBOM document.
+type Component struct {
+	// Ref is the reference of the component in the document,
+	// i.e. the bom-ref of CycloneDX or the SPDXID of SPDX.
+	Ref string
+	// Name is the name of the component.
+	Name string
+	// Version is the version of the component, optional.
+	Version string
+	// Purl is the normalized package URL, empty if not given or invalid.
+	Purl string
+	// PackageURL is the parsed Purl.
+	PackageURL *packageurl.PackageURL
+	// CPEs is the valid CPE names of the component.
+	CPEs []string
+	// Attributes is the parsed CPEs.
+	Attributes []*wfn.Attributes
+	// Licenses is the license IDs or expressions of the component.
+	Licenses []string
+}
+
+// IsIdentified returns true if the component has any valid package URL or CPE name.
+func (in Component) IsIdentified() bool {
+	return in.PackageURL != nil || len(in.Attributes) != 0
+}
+
+// Dependency holds the direct dependencies of a component.
+type Dependency struct {
+	// Ref is the reference of the dependent component.
+	Ref string
+	// DependsOn is the references of the depended components.
+	DependsOn []string
+}
+
+// Issue describes a problem of a component.
+type Issue struct {
+	// Ref is the reference of the component.
+	Ref string
+	// Message describes the problem.
+	Message string
+}
+
+func (in Issue) String() string {
+	return in.Ref + ": " + in.Message
+}
+
+// Component returns the component of the given reference.
+func (in *Document) Component(ref string) (Component, bool) {
+	for i := range in.Components {
+		if in.Components[i].Ref == ref {
+			return in.Components[i], true
+		}
+	}
+	return Component{}, false
+}
+
+// DependenciesOf returns the references of the direct dependencies of the given reference.
+func (in *Document) DependenciesOf(ref string) []string {
+	for i := range in.Dependencies {
+		if in.Dependencies[i].Ref == ref {
+			return in.Dependencies[i].DependsOn
+		}
+	}
+	return nil
+}
+
+// Unidentified returns the components without any valid package URL or CPE name,
+// which cannot be matched to the vulnerabilities.
+func (in *Document) Unidentified() []Component {
+	var r []Component
+	for i := range in.Components {
+		if !in.Components[i].IsIdentified() {
+			r = append(r, in.Components[i])
+		}
+	}
+	return r
+}
+
+// ParseFile parses the SBOM document from the given file, the format is detected by the content.
+func ParseFile(path string) (*Document, error) {
+	var bs, err = os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading sbom file %s: %w", path, err)
+	}
+	return ParseBytes(bs)
+}
+
+// Parse parses the SBOM document from the given io.Reader, the format is detected by the content.
+func Parse(r io.Reader) (*Document, error) {
+	var bs, err = io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading sbom: %w", err)
+	}
+	return ParseBytes(bs)
+}
+
+// ParseBytes parses the SBOM document from the given bytes, the format is detected by the content.
+func ParseBytes(bs []byte) (*Document, error) {
+	var f, err = Detect(bs)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBytesAs(bs, f)
+}
+
+// ParseBytesAs parses the SBOM document from the given bytes in the given format.
+func ParseBytesAs(bs []byte, f Format) (*Document, error) {
+	var d *Document
+	var err error
+	switch f {
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, f)
+	case FormatCycloneDXJSON:
+		d, err = parseCycloneDXJSON(bs)
+	case FormatCycloneDXXML:
+		d, err = parseCycloneDXXML(bs)
+	case FormatSPDXJSON:
+		d, err = parseSPDXJSON(bs)
+	case FormatSPDXTagValue:
+		d, err = parseSPDXTagValue(bs)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s sbom: %w", f, err)
+	}
+	d.Format = f
+	return d, nil
+}
+
+// Detect detects the format of the given SBOM document.
+func Detect(bs []byte) (Format, error) {
+	bs = bytes.TrimSpace(bytes.TrimPrefix(bs, []byte("\xef\xbb\xbf")))
+	switch {
+	case len(bs) == 0:
+	case bs[0] == '<':
+		if bytes.Contains(bs, []byte("<bom")) {
+			return FormatCycloneDXXML, nil
+		}
+	case bs[0] == '{':
+		var probe struct {
+			BOMFormat   string `json:"bomFormat"`
+			SPDXVersion string `json:"spdxVersion"`
+		}
+		if json.Unmarshal(bs, &probe) != nil {
+			break
+		}
+		switch {
+		case probe.BOMFormat == "CycloneDX":
+			return FormatCycloneDXJSON, nil
+		case probe.SPDXVersion != "":
+			return FormatSPDXJSON, nil
+		}
+	case bytes.HasPrefix(bs, []byte("SPDXVersion:")) || bytes.Contains(bs, []byte("\nSPDXVersion:")):
+		return FormatSPDXTagValue, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// builder builds the Document with normalizing the identities.
+type builder struct {
+	d    Document
+	deps map[string][]string
+	refs []string
+}
+
+// addComponent normalizes the given purl and CPE names, and adds the component.
+func (in *builder) addComponent(c Component, purl string, cpes []string) {
+	if purl != "" {
+		var p, err = packageurl.FromString(purl)
+		if err != nil {
+			in.issue(c.Ref, fmt.Sprintf("invalid purl %q: %v", purl, err))
+		} else {
+			c.Purl = p.String()
+			c.PackageURL = &p
+		}
+	}
+	for _, cpe := range cpes {
+		if cpe == "" {
+			continue
+		}
+		var a, err = wfn.Parse(cpe)
+		if err != nil {
+			in.issue(c.Ref, fmt.Sprintf("invalid cpe %q: %v", cpe, err))
+			continue
+		}
+		c.CPEs = append(c.CPEs, cpe)
+		c.Attributes = append(c.Attributes, a)
+	}
+	if !c.IsIdentified() {
+		in.issue(c.Ref, "no valid purl or cpe")
+	}
+	in.d.Components = append(in.d.Components, c)
+}
+
+// addDependency adds the dependency relationship, the duplicated ones are ignored.
+func (in *builder) addDependency(ref string, dependsOn ...string) {
+	if in.deps == nil {
+		in.deps = map[string][]string{}
+	}
+	var ds, ok = in.deps[ref]
+	if !ok {
+		in.refs = append(in.refs, ref)
+	}
+	for _, d := range dependsOn {
+		if d == "" || contains(ds, d) {
+			continue
+		}
+		ds = append(ds, d)
+	}
+	in.deps[ref] = ds
+}
+
+func (in *builder) issue(ref, msg string) {
+	in.d.Issues = append(in.d.Issues, Issue{Ref: ref, Message: msg})
+}
+
+func (in *builder) build() *Document {
+	for _, ref := range in.refs {
+		var ds = in.deps[ref]
+		if len(ds) == 0 {
+			continue
+		}
+		sort.Strings(ds)
+		in.d.Dependencies = append(in.d.Dependencies, Dependency{Ref: ref, DependsOn: ds})
+	}
+	return &in.d
+}
+
+func contains(ss []string, s string) bool {
+	for i := range ss {
+		if ss[i] == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package sbom
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseFile(t *testing.T) {
+	var expected = strings.Join([]string{
+		"name: acme-app, root: acme-app",
+		"component: express express@4.17.1 pkg:npm/express@4.17.1 [] [MIT]",
+		"component: lodash lodash@4.17.15 pkg:npm/lodash@4.17.15 [] [MIT]",
+		"component: openssl openssl@1.1.1k  [cpe:2.3:a:openssl:openssl:1.1.1k:*:*:*:*:*:*:*] [Apache-2.0 OR OpenSSL]",
+		"component: left-pad left-pad@1.3.0  [] [WTFPL]",
+		"component: broken broken@1.0.0  [] []",
+		"dependency: acme-app -> [broken express left-pad openssl]",
+		"dependency: express -> [lodash]",
+		"issue: left-pad: no valid purl or cpe",
+		"issue: broken: invalid purl",
+		"issue: broken: no valid purl or cpe",
+		"unidentified: left-pad",
+		"unidentified: broken",
+	}, "\n")
+
+	var testCases = []struct {
+		given    string
+		expected Format
+	}{
+		{given: "cyclonedx.json", expected: FormatCycloneDXJSON},
+		{given: "cyclonedx.xml", expected: FormatCycloneDXXML},
+		{given: "spdx.json", expected: FormatSPDXJSON},
+		{given: "spdx.spdx", expected: FormatSPDXTagValue},
+	}
+	for _, c := range testCases {
+		var d, err = ParseFile(filepath.Join("testdata", c.given))
+		if err != nil {
+			t.Fatalf("error parsing %s: %v", c.given, err)
+		}
+		if d.Format != c.expected {
+			t.Errorf("expected %s in format %s, but got %s", c.given, c.expected, d.Format)
+		}
+		if actual := testingSummary(d); actual != expected {
+			t.Errorf("expected %s parsed as\n%s\nbut got\n%s", c.given, expected, actual)
+		}
+		if ld, _ := d.Component(testingRef(d, "lodash")); ld.PackageURL == nil || ld.PackageURL.Version != "4.17.15" {
+			t.Errorf("expected %s lodash with parsed purl, but got %v", c.given, ld.PackageURL)
+		}
+		if os, _ := d.Component(testingRef(d, "openssl")); len(os.Attributes) != 1 || os.Attributes[0].Vendor != "openssl" {
+			t.Errorf("expected %s openssl with parsed cpe, but got %v", c.given, os.Attributes)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	var testCases = []struct {
+		given    string
+		expected error
+	}{
+		{given: ``, expected: ErrUnknownFormat},
+		{given: `{"bomFormat":"SWID"}`, expected: ErrUnknownFormat},
+		{given: `<bom xmlns="http://cyclonedx.org/schema/bom/1.4"></bom>`, expected: nil},
+		{given: "# comment\nSPDXVersion: SPDX-2.3", expected: nil},
+	}
+	for _, c := range testCases {
+		var _, actual = Detect([]byte(c.given))
+		if !errors.Is(actual, c.expected) {
+			t.Errorf("Detect(%q) == %v, but got %v", c.given, c.expected, actual)
+		}
+	}
+}
+
+// testingSummary summarizes the given Document in lines,
+// the SPDX reference is trimmed for comparing with the CycloneDX reference.
+func testingSummary(d *Document) string {
+	var ref = func(s string) string {
+		return strings.TrimPrefix(s, "SPDXRef-")
+	}
+	var refs = func(ss []string) []string {
+		var r = make([]string, 0, len(ss))
+		for i := range ss {
+			r = append(r, ref(ss[i]))
+		}
+		return r
+	}
+	var lines = []string{fmt.Sprintf("name: %s, root: %s", d.Name, ref(d.Root))}
+	for _, c := range d.Components {
+		lines = append(lines, fmt.Sprintf("component: %s %s@%s %s %v %v", ref(c.Ref), c.Name, c.Version, c.Purl, c.CPEs, c.Licenses))
+	}
+	for _, dep := range d.Dependencies {
+		lines = append(lines, fmt.Sprintf("dependency: %s -> %v", ref(dep.Ref), refs(dep.DependsOn)))
+	}
+	for _, i := range d.Issues {
+		// NB: drop the detail of the parsing error.
+		var msg, _, _ = strings.Cut(i.Message, " \"")
+		lines = append(lines, fmt.Sprintf("issue: %s: %s", ref(i.Ref), msg))
+	}
+	for _, c := range d.Unidentified() {
+		lines = append(lines, "unidentified: "+ref(c.Ref))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func testingRef(d *Document, name string) string {
+	if d.Format == FormatSPDXJSON || d.Format == FormatSPDXTagValue {
+		return "SPDXRef-" + name
+	}
+	return name
+}
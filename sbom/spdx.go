@@ -0,0 +1,183 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// spdxDocument is the subset of the SPDX document,
+// according to https://spdx.github.io/spdx-spec/v2.3.
+type spdxDocument struct {
+	Name              string             `json:"name"`
+	DocumentDescribes []string           `json:"documentDescribes"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+const spdxDocumentID = "SPDXRef-DOCUMENT"
+
+func parseSPDXJSON(bs []byte) (*Document, error) {
+	var doc spdxDocument
+	var err = json.Unmarshal(bs, &doc)
+	if err != nil {
+		return nil, err
+	}
+	return doc.build(), nil
+}
+
+// parseSPDXTagValue parses the SPDX document in tag-value format,
+// the multi-line value is wrapped by <text> and </text>.
+func parseSPDXTagValue(bs []byte) (*Document, error) {
+	var doc spdxDocument
+	// NB: the current package is -1 if out of any package section.
+	var pkg = -1
+	var lines = strings.Split(string(bs), "\n")
+	for i := 0; i < len(lines); i++ {
+		var line = strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var tag, value, ok = strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid line %d: %q", i+1, line)
+		}
+		var n = i
+		value = strings.TrimSpace(value)
+		if strings.HasPrefix(value, "<text>") {
+			value = strings.TrimPrefix(value, "<text>")
+			for !strings.Contains(value, "</text>") {
+				i++
+				if i >= len(lines) {
+					return nil, fmt.Errorf("unterminated text of line %d", n+1)
+				}
+				value += "\n" + lines[i]
+			}
+			value = value[:strings.Index(value, "</text>")]
+		}
+
+		switch tag {
+		case "DocumentName":
+			doc.Name = value
+		case "PackageName":
+			doc.Packages = append(doc.Packages, spdxPackage{Name: value})
+			pkg = len(doc.Packages) - 1
+		case "FileName", "SnippetSPDXID":
+			pkg = -1
+		case "SPDXID":
+			if pkg >= 0 && doc.Packages[pkg].SPDXID == "" {
+				doc.Packages[pkg].SPDXID = value
+			}
+		case "PackageVersion":
+			if pkg >= 0 {
+				doc.Packages[pkg].VersionInfo = value
+			}
+		case "PackageLicenseConcluded":
+			if pkg >= 0 {
+				doc.Packages[pkg].LicenseConcluded = value
+			}
+		case "PackageLicenseDeclared":
+			if pkg >= 0 {
+				doc.Packages[pkg].LicenseDeclared = value
+			}
+		case "ExternalRef":
+			var fs = strings.Fields(value)
+			if pkg < 0 || len(fs) < 3 {
+				continue
+			}
+			doc.Packages[pkg].ExternalRefs = append(doc.Packages[pkg].ExternalRefs, spdxExternalRef{
+				ReferenceCategory: fs[0],
+				ReferenceType:     fs[1],
+				ReferenceLocator:  fs[2],
+			})
+		case "Relationship":
+			var fs = strings.Fields(value)
+			if len(fs) < 3 {
+				return nil, fmt.Errorf("invalid relationship of line %d: %q", n+1, value)
+			}
+			doc.Relationships = append(doc.Relationships, spdxRelationship{
+				SPDXElementID:      fs[0],
+				RelationshipType:   fs[1],
+				RelatedSPDXElement: fs[2],
+			})
+		}
+	}
+	return doc.build(), nil
+}
+
+func (in spdxDocument) build() *Document {
+	var b builder
+	b.d.Name = in.Name
+	if len(in.DocumentDescribes) != 0 {
+		b.d.Root = in.DocumentDescribes[0]
+	}
+	for _, r := range in.Relationships {
+		var typ = strings.ToUpper(r.RelationshipType)
+		switch {
+		case typ == "DESCRIBES" && r.SPDXElementID == spdxDocumentID && b.d.Root == "":
+			b.d.Root = r.RelatedSPDXElement
+		case typ == "DESCRIBED_BY" && r.RelatedSPDXElement == spdxDocumentID && b.d.Root == "":
+			b.d.Root = r.SPDXElementID
+		case typ == "DEPENDS_ON":
+			b.addDependency(r.SPDXElementID, r.RelatedSPDXElement)
+		case strings.HasSuffix(typ, "DEPENDENCY_OF"):
+			// NB: includes DEV_DEPENDENCY_OF, BUILD_DEPENDENCY_OF and so on.
+			b.addDependency(r.RelatedSPDXElement, r.SPDXElementID)
+		}
+	}
+
+	for _, p := range in.Packages {
+		if p.SPDXID == b.d.Root {
+			// NB: the described package is the subject of the document rather than a component,
+			// which is the same as the metadata component of CycloneDX.
+			b.d.Name = p.Name
+			continue
+		}
+		var c = Component{
+			Ref:     p.SPDXID,
+			Name:    p.Name,
+			Version: p.VersionInfo,
+		}
+		for _, l := range []string{p.LicenseConcluded, p.LicenseDeclared} {
+			if l == "" || l == "NOASSERTION" || l == "NONE" || contains(c.Licenses, l) {
+				continue
+			}
+			c.Licenses = append(c.Licenses, l)
+		}
+		var purl string
+		var cpes []string
+		for _, r := range p.ExternalRefs {
+			switch r.ReferenceType {
+			case "purl":
+				if purl == "" {
+					purl = r.ReferenceLocator
+				}
+			case "cpe23Type", "cpe22Type":
+				cpes = append(cpes, r.ReferenceLocator)
+			}
+		}
+		b.addComponent(c, purl, cpes)
+	}
+	return b.build()
+}
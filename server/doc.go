@@ -0,0 +1,3 @@
+// Package server provides an in-process implementation of schema.DatasetServiceServer,
+// which serves the dataset from a pluggable Store, e.g. a local mirror or the hermetic testing.
+package server
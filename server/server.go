@@ -0,0 +1,128 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/seal-io/meta-api/schema"
+)
+
+const (
+	defaultWindowSize = 1000
+	maxWindowSize     = 10000
+)
+
+type _ServerOptions struct {
+	DefaultWindowSize int32
+	MaxWindowSize     int32
+}
+
+// ServerOption configures the Server.
+type ServerOption func(*_ServerOptions)
+
+// WithDefaultWindowSize responses the given count of items in a window,
+// if the request does not specify the window_size, by default, it is 1000.
+func WithDefaultWindowSize(n int32) ServerOption {
+	return func(o *_ServerOptions) {
+		if n > 0 {
+			o.DefaultWindowSize = n
+		}
+	}
+}
+
+// WithMaxWindowSize limits the window_size of the request, by default, it is 10000,
+// the larger window_size is reduced to the limit.
+func WithMaxWindowSize(n int32) ServerOption {
+	return func(o *_ServerOptions) {
+		if n > 0 {
+			o.MaxWindowSize = n
+		}
+	}
+}
+
+// NewServer returns a Server serving the dataset of the given Store.
+func NewServer(store Store, opts ...ServerOption) *Server {
+	var o = _ServerOptions{
+		DefaultWindowSize: defaultWindowSize,
+		MaxWindowSize:     maxWindowSize,
+	}
+	for i := range opts {
+		if opts[i] == nil {
+			continue
+		}
+		opts[i](&o)
+	}
+	if o.DefaultWindowSize > o.MaxWindowSize {
+		o.DefaultWindowSize = o.MaxWindowSize
+	}
+	return &Server{
+		store: store,
+		o:     o,
+	}
+}
+
+// Server implements the schema.DatasetServiceServer,
+// which divides the items of the Store into windows in order.
+type Server struct {
+	schema.UnimplementedDatasetServiceServer
+
+	store Store
+	o     _ServerOptions
+}
+
+func (in *Server) Ingest(stream schema.DatasetService_IngestServer) error {
+	var ctx = stream.Context()
+	for {
+		var req, err = stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if req.GetWindow() < 0 {
+			return status.Errorf(codes.InvalidArgument, "invalid window %d", req.GetWindow())
+		}
+		var windowSize = in.o.DefaultWindowSize
+		if req.WindowSize != nil {
+			windowSize = req.GetWindowSize()
+			if windowSize <= 0 {
+				return status.Errorf(codes.InvalidArgument, "invalid window size %d", windowSize)
+			}
+			if windowSize > in.o.MaxWindowSize {
+				windowSize = in.o.MaxWindowSize
+			}
+		}
+		var since time.Time
+		if req.Since != nil {
+			since = req.GetSince().AsTime()
+		}
+		var offset = int(req.GetWindow()) * int(windowSize)
+		body, more, err := in.store.List(ctx, req.GetType(), since, offset, int(windowSize))
+		if err != nil {
+			if errors.Is(err, ErrUnknownType) {
+				return status.Error(codes.InvalidArgument, err.Error())
+			}
+			if s, ok := status.FromError(err); ok {
+				return s.Err()
+			}
+			return status.Errorf(codes.Internal, "error listing dataset: %v", err)
+		}
+		var resp = &schema.DatasetIngestResponse{
+			WindowSize: windowSize,
+			Body:       body,
+		}
+		if more {
+			var next = req.GetWindow() + 1
+			resp.NextWindow = &next
+		}
+		err = stream.Send(resp)
+		if err != nil {
+			return err
+		}
+	}
+}
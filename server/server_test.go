@@ -0,0 +1,233 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	api "github.com/seal-io/meta-api"
+	"github.com/seal-io/meta-api/schema"
+)
+
+func TestServer(t *testing.T) {
+	var base = time.Date(2022, 11, 3, 0, 0, 0, 0, time.UTC)
+	var store = NewMemoryStore()
+	for i := 0; i < 5; i++ {
+		_ = store.Add(0, &schema.DatasetIngestResponse_ComplianceLicenseTags{
+			ComplianceLicenseTags: &schema.ComplianceLicenseTags{
+				Items: []*schema.ComplianceLicenseTag{
+					{
+						Name:       fmt.Sprintf("tag-%d", i),
+						UpdateTime: timestamppb.New(base.AddDate(0, 0, i)),
+					},
+				},
+			},
+		})
+	}
+	var cli = testingClient(t, testingConn(t, NewServer(store, WithDefaultWindowSize(2), WithMaxWindowSize(3))))
+
+	type output struct {
+		windows []int32
+		names   []string
+	}
+	var testCases = []struct {
+		given    time.Time
+		expected output
+	}{
+		{
+			given: time.Time{},
+			expected: output{
+				windows: []int32{0, 1, 2},
+				names:   []string{"tag-0", "tag-1", "tag-2", "tag-3", "tag-4"},
+			},
+		},
+		{
+			given: base.AddDate(0, 0, 2),
+			expected: output{
+				windows: []int32{0, 1},
+				names:   []string{"tag-2", "tag-3", "tag-4"},
+			},
+		},
+		{
+			given: base.AddDate(0, 0, 5),
+			expected: output{
+				windows: []int32{0},
+			},
+		},
+	}
+	for _, c := range testCases {
+		var actual output
+		var err = cli.Ingest(context.Background(), schema.DatasetIngestRequestType_Compliance_License_Tag, c.given,
+			func(currentWindow int32, body schema.DatasetIngestResponseBody) error {
+				actual.windows = append(actual.windows, currentWindow)
+				for _, item := range body.(*schema.DatasetIngestResponse_ComplianceLicenseTags).ComplianceLicenseTags.GetItems() {
+					actual.names = append(actual.names, item.GetName())
+				}
+				return nil
+			})
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", c.given, err)
+			continue
+		}
+		if fmt.Sprint(actual) != fmt.Sprint(c.expected) {
+			t.Errorf("%v: expected %v, but got %v", c.given, c.expected, actual)
+		}
+	}
+}
+
+func TestServer_WindowSize(t *testing.T) {
+	var store = NewMemoryStore()
+	var items = make([]*schema.WeaknessVulnerability, 10)
+	for i := range items {
+		items[i] = &schema.WeaknessVulnerability{Name: fmt.Sprintf("CVE-2022-%04d", i)}
+	}
+	_ = store.Add(0, &schema.DatasetIngestResponse_WeaknessVulnerabilities{
+		WeaknessVulnerabilities: &schema.WeaknessVulnerabilities{Items: items},
+	})
+	var cc = testingConn(t, NewServer(store, WithDefaultWindowSize(4), WithMaxWindowSize(6)))
+
+	type output struct {
+		windowSize int32
+		nextWindow int32
+		count      int
+		err        codes.Code
+	}
+	var testCases = []struct {
+		given    *schema.DatasetIngestRequest
+		expected output
+	}{
+		{
+			given:    &schema.DatasetIngestRequest{Type: schema.DatasetIngestRequestType_Weakness_Vulnerability},
+			expected: output{windowSize: 4, nextWindow: 1, count: 4},
+		},
+		{
+			given:    &schema.DatasetIngestRequest{Type: schema.DatasetIngestRequestType_Weakness_Vulnerability, Window: 2},
+			expected: output{windowSize: 4, nextWindow: -1, count: 2},
+		},
+		{
+			given:    &schema.DatasetIngestRequest{Type: schema.DatasetIngestRequestType_Weakness_Vulnerability, WindowSize: proto.Int32(3), Window: 2},
+			expected: output{windowSize: 3, nextWindow: 3, count: 3},
+		},
+		{
+			given:    &schema.DatasetIngestRequest{Type: schema.DatasetIngestRequestType_Weakness_Vulnerability, WindowSize: proto.Int32(100)},
+			expected: output{windowSize: 6, nextWindow: 1, count: 6},
+		},
+		{
+			given:    &schema.DatasetIngestRequest{Type: schema.DatasetIngestRequestType_Weakness_Vulnerability, WindowSize: proto.Int32(0)},
+			expected: output{err: codes.InvalidArgument},
+		},
+		{
+			given:    &schema.DatasetIngestRequest{Type: schema.DatasetIngestRequestType(99)},
+			expected: output{err: codes.InvalidArgument},
+		},
+	}
+	for i, c := range testCases {
+		var stream, err = schema.NewDatasetServiceClient(cc).Ingest(context.Background())
+		if err != nil {
+			t.Fatalf("error creating stream: %v", err)
+		}
+		var actual output
+		err = stream.Send(c.given)
+		if err == nil {
+			var resp *schema.DatasetIngestResponse
+			resp, err = stream.Recv()
+			if err == nil {
+				actual.windowSize = resp.GetWindowSize()
+				actual.nextWindow = -1
+				if resp.NextWindow != nil {
+					actual.nextWindow = resp.GetNextWindow()
+				}
+				actual.count = len(resp.GetWeaknessVulnerabilities().GetItems())
+			}
+		}
+		actual.err = status.Code(err)
+		_ = stream.CloseSend()
+		if actual != c.expected {
+			t.Errorf("#%d expected %+v, but got %+v", i+1, c.expected, actual)
+		}
+	}
+}
+
+func TestNewSnapshotStore(t *testing.T) {
+	var store = NewMemoryStore()
+	_ = store.Add(0, &schema.DatasetIngestResponse_ComplianceLicenses{
+		ComplianceLicenses: &schema.ComplianceLicenses{
+			Items: []*schema.ComplianceLicense{{Namespace: "spdx", Name: "MIT"}, {Namespace: "spdx", Name: "Apache-2.0"}},
+		},
+	})
+	var cli = testingClient(t, testingConn(t, NewServer(store, WithDefaultWindowSize(1))))
+
+	var path = filepath.Join(t.TempDir(), "snapshot.gz")
+	var w, err = api.CreateSnapshot(path, time.Time{})
+	if err != nil {
+		t.Fatalf("error creating snapshot: %v", err)
+	}
+	err = cli.IngestAll(context.Background(), time.Time{}, nil, api.WithSnapshot(w))
+	if err != nil {
+		t.Fatalf("error recording snapshot: %v", err)
+	}
+	_ = w.Close()
+
+	mirror, err := NewSnapshotStore(context.Background(), path)
+	if err != nil {
+		t.Fatalf("error loading snapshot store: %v", err)
+	}
+	body, more, err := mirror.List(context.Background(), schema.DatasetIngestRequestType_Compliance_License, time.Time{}, 1, 10)
+	if err != nil {
+		t.Fatalf("error listing snapshot store: %v", err)
+	}
+	var actual = body.(*schema.DatasetIngestResponse_ComplianceLicenses).ComplianceLicenses.GetItems()
+	if more || len(actual) != 1 || actual[0].GetName() != "Apache-2.0" {
+		t.Errorf("expected [Apache-2.0] without more, but got %v, %v", actual, more)
+	}
+}
+
+type testingClientConn struct {
+	*grpc.ClientConn
+
+	lis *bufconn.Listener
+}
+
+// testingConn serves the given Server in memory, and returns a connection to it.
+func testingConn(t *testing.T, srv *Server) testingClientConn {
+	var lis = bufconn.Listen(1024 * 1024)
+	var gs = grpc.NewServer()
+	schema.RegisterDatasetServiceServer(gs, srv)
+	go func() { _ = gs.Serve(lis) }()
+	t.Cleanup(gs.Stop)
+
+	var cc, err = grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("error dialing testing server: %v", err)
+	}
+	t.Cleanup(func() { _ = cc.Close() })
+	return testingClientConn{ClientConn: cc, lis: lis}
+}
+
+// testingClient returns an api.Client connecting to the given in memory server.
+func testingClient(t *testing.T, cc testingClientConn) api.Client {
+	var cli, err = api.GetClient(context.Background(), "bufnet",
+		api.WithDialOptions(grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return cc.lis.DialContext(ctx)
+		})))
+	if err != nil {
+		t.Fatalf("error creating client: %v", err)
+	}
+	t.Cleanup(func() { _ = cli.Close() })
+	return cli
+}
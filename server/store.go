@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	api "github.com/seal-io/meta-api"
+	"github.com/seal-io/meta-api/schema"
+)
+
+// ErrUnknownType is returned if the type of the dataset is unknown.
+var ErrUnknownType = errors.New("unknown dataset type")
+
+// Store holds the actions for querying the dataset served by the Server.
+type Store interface {
+	// List returns at most limit items of the given type updated not earlier than the given since,
+	// which skips the first offset items, the result is wrapped as schema.DatasetIngestResponseBody,
+	// and returns true if there are more items after the result.
+	// The order of the items must be stable, so that the windows can be requested one by one.
+	List(ctx context.Context, typ schema.DatasetIngestRequestType, since time.Time, offset, limit int) (body schema.DatasetIngestResponseBody, more bool, err error)
+}
+
+// NewMemoryStore returns a MemoryStore without any items.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// NewSnapshotStore returns a MemoryStore loaded from the given snapshot file,
+// which is recorded by api.SnapshotWriter.
+func NewSnapshotStore(ctx context.Context, path string) (*MemoryStore, error) {
+	var cli, err = api.OpenSnapshotClient(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = cli.Close() }()
+	var s = NewMemoryStore()
+	err = s.Load(ctx, cli, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// MemoryStore is a Store keeping the items in memory in order of adding,
+// it is safe for concurrent use.
+type MemoryStore struct {
+	m                             sync.RWMutex
+	complianceLicenseTags         []*schema.ComplianceLicenseTag
+	complianceLicenses            []*schema.ComplianceLicense
+	weaknessVulnerabilityTags     []*schema.WeaknessVulnerabilityTag
+	weaknessVulnerabilities       []*schema.WeaknessVulnerability
+	weaknessVulnerabilityFeatures []*schema.WeaknessVulnerabilityFeature
+}
+
+// Add appends the items of the given schema.DatasetIngestResponseBody,
+// it matches api.IngestParser, so that the MemoryStore can be filled by api.Client.
+func (in *MemoryStore) Add(_ int32, body schema.DatasetIngestResponseBody) error {
+	in.m.Lock()
+	defer in.m.Unlock()
+	switch b := body.(type) {
+	default:
+		return fmt.Errorf("%w: %T", api.ErrUnknownIngestBody, body)
+	case *schema.DatasetIngestResponse_ComplianceLicenseTags:
+		in.complianceLicenseTags = append(in.complianceLicenseTags, b.ComplianceLicenseTags.GetItems()...)
+	case *schema.DatasetIngestResponse_ComplianceLicenses:
+		in.complianceLicenses = append(in.complianceLicenses, b.ComplianceLicenses.GetItems()...)
+	case *schema.DatasetIngestResponse_WeaknessVulnerabilityTags:
+		in.weaknessVulnerabilityTags = append(in.weaknessVulnerabilityTags, b.WeaknessVulnerabilityTags.GetItems()...)
+	case *schema.DatasetIngestResponse_WeaknessVulnerabilities:
+		in.weaknessVulnerabilities = append(in.weaknessVulnerabilities, b.WeaknessVulnerabilities.GetItems()...)
+	case *schema.DatasetIngestResponse_WeaknessVulnerabilityFeatures:
+		in.weaknessVulnerabilityFeatures = append(in.weaknessVulnerabilityFeatures, b.WeaknessVulnerabilityFeatures.GetItems()...)
+	}
+	return nil
+}
+
+// Load appends all types dataset ingested from the given api.Client,
+// e.g. mirrors the exposing service or replays a snapshot.
+func (in *MemoryStore) Load(ctx context.Context, cli api.Client, since time.Time, opts ...api.IngestOption) error {
+	var err = cli.IngestAll(ctx, since, in.Add, opts...)
+	if err != nil {
+		return fmt.Errorf("error loading memory store: %w", err)
+	}
+	return nil
+}
+
+func (in *MemoryStore) List(_ context.Context, typ schema.DatasetIngestRequestType, since time.Time, offset, limit int) (schema.DatasetIngestResponseBody, bool, error) {
+	in.m.RLock()
+	defer in.m.RUnlock()
+	switch typ {
+	case schema.DatasetIngestRequestType_Compliance_License_Tag:
+		var items, more = page(in.complianceLicenseTags, since, offset, limit)
+		return &schema.DatasetIngestResponse_ComplianceLicenseTags{
+			ComplianceLicenseTags: &schema.ComplianceLicenseTags{Items: items},
+		}, more, nil
+	case schema.DatasetIngestRequestType_Compliance_License:
+		var items, more = page(in.complianceLicenses, since, offset, limit)
+		return &schema.DatasetIngestResponse_ComplianceLicenses{
+			ComplianceLicenses: &schema.ComplianceLicenses{Items: items},
+		}, more, nil
+	case schema.DatasetIngestRequestType_Weakness_Vulnerability_Tag:
+		var items, more = page(in.weaknessVulnerabilityTags, since, offset, limit)
+		return &schema.DatasetIngestResponse_WeaknessVulnerabilityTags{
+			WeaknessVulnerabilityTags: &schema.WeaknessVulnerabilityTags{Items: items},
+		}, more, nil
+	case schema.DatasetIngestRequestType_Weakness_Vulnerability:
+		var items, more = page(in.weaknessVulnerabilities, since, offset, limit)
+		return &schema.DatasetIngestResponse_WeaknessVulnerabilities{
+			WeaknessVulnerabilities: &schema.WeaknessVulnerabilities{Items: items},
+		}, more, nil
+	case schema.DatasetIngestRequestType_Weakness_Vulnerability_Feature:
+		var items, more = page(in.weaknessVulnerabilityFeatures, since, offset, limit)
+		return &schema.DatasetIngestResponse_WeaknessVulnerabilityFeatures{
+			WeaknessVulnerabilityFeatures: &schema.WeaknessVulnerabilityFeatures{Items: items},
+		}, more, nil
+	}
+	return nil, false, fmt.Errorf("%w: %v", ErrUnknownType, typ)
+}
+
+// page returns at most limit items updated not earlier than the given since after skipping offset items,
+// and returns true if there are more items.
+func page[T interface{ GetUpdateTime() *timestamppb.Timestamp }](items []T, since time.Time, offset, limit int) ([]T, bool) {
+	var r = make([]T, 0, limit)
+	for i := range items {
+		if !since.IsZero() && items[i].GetUpdateTime().AsTime().Before(since) {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		if len(r) == limit {
+			return r, true
+		}
+		r = append(r, items[i])
+	}
+	return r, false
+}
@@ -0,0 +1,407 @@
+package api
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/seal-io/meta-api/schema"
+)
+
+// SnapshotVersion is the version of the snapshot file format.
+const SnapshotVersion = 1
+
+// ErrInvalidSnapshot is returned if the snapshot file is malformed or corrupted.
+var ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+// snapshotMagic is the leading bytes of the (uncompressed) snapshot file.
+var snapshotMagic = []byte("SMDS")
+
+const (
+	snapshotFrameWindow byte = iota + 1
+	snapshotFrameManifest
+)
+
+// SnapshotManifest holds the summary of a snapshot file,
+// which is written at the end of the snapshot file.
+type SnapshotManifest struct {
+	// Version is the version of the snapshot file format.
+	Version int `json:"version"`
+	// CreateTime is the time of finishing the snapshot.
+	CreateTime time.Time `json:"createTime"`
+	// Since is the since condition of the recorded dataset.
+	Since time.Time `json:"since"`
+	// Types holds the summary of each recorded type dataset, in order of type.
+	Types []SnapshotManifestType `json:"types"`
+}
+
+// SnapshotManifestType holds the summary of the recorded type dataset.
+type SnapshotManifestType struct {
+	// Type is the type of the recorded dataset.
+	Type schema.DatasetIngestRequestType `json:"type"`
+	// Windows is the count of the recorded windows.
+	Windows int `json:"windows"`
+	// Checksum is the hex encoded SHA-256 checksum of the recorded windows.
+	Checksum string `json:"checksum"`
+}
+
+// Get returns the SnapshotManifestType of the given type, returns nil if not found.
+func (in SnapshotManifest) Get(typ schema.DatasetIngestRequestType) *SnapshotManifestType {
+	for i := range in.Types {
+		if in.Types[i].Type == typ {
+			return &in.Types[i]
+		}
+	}
+	return nil
+}
+
+// SnapshotWriter records the ingested windows into a gzip compressed snapshot,
+// the snapshot is a sequence of length-delimited schema.DatasetIngestResponse frames,
+// and ends with a SnapshotManifest frame written by Close.
+// It is safe for concurrent use, so it can be shared with the concurrent IngestAll.
+type SnapshotWriter struct {
+	m      sync.Mutex
+	since  time.Time
+	gz     *gzip.Writer
+	closer io.Closer
+	types  map[schema.DatasetIngestRequestType]*snapshotTypeSummary
+	err    error
+}
+
+type snapshotTypeSummary struct {
+	windows int
+	hash    hash.Hash
+}
+
+// NewSnapshotWriter returns a SnapshotWriter writing to the given io.Writer,
+// the given since must be the same as the since of the ingesting.
+func NewSnapshotWriter(w io.Writer, since time.Time) (*SnapshotWriter, error) {
+	var gz = gzip.NewWriter(w)
+	var header = append(append([]byte{}, snapshotMagic...), SnapshotVersion)
+	var _, err = gz.Write(header)
+	if err != nil {
+		return nil, fmt.Errorf("error writing snapshot header: %w", err)
+	}
+	return &SnapshotWriter{
+		since: since,
+		gz:    gz,
+		types: map[schema.DatasetIngestRequestType]*snapshotTypeSummary{},
+	}, nil
+}
+
+// CreateSnapshot creates the snapshot file of the given path and returns its SnapshotWriter,
+// the file is closed by SnapshotWriter.Close.
+func CreateSnapshot(path string, since time.Time) (*SnapshotWriter, error) {
+	var err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("error creating snapshot directory: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("error creating snapshot file: %w", err)
+	}
+	w, err := NewSnapshotWriter(f, since)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	w.closer = f
+	return w, nil
+}
+
+// Record records the given response of the specified type and window,
+// the windows of each type must start from window 0, which can be recorded only once.
+func (in *SnapshotWriter) Record(typ schema.DatasetIngestRequestType, window int32, resp *schema.DatasetIngestResponse) error {
+	var bs, err = proto.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("error marshaling snapshot window: %w", err)
+	}
+	var frame = make([]byte, 0, 1+3*binary.MaxVarintLen64+len(bs))
+	frame = append(frame, snapshotFrameWindow)
+	frame = appendUvarint(frame, uint64(typ))
+	frame = appendUvarint(frame, uint64(window))
+	frame = appendUvarint(frame, uint64(len(bs)))
+	frame = append(frame, bs...)
+
+	in.m.Lock()
+	defer in.m.Unlock()
+	if in.err != nil {
+		return in.err
+	}
+	if in.gz == nil {
+		return errors.New("snapshot writer is closed")
+	}
+	var s = in.types[typ]
+	if err = checkSnapshotWindow(typ, window, s); err != nil {
+		return err
+	}
+	_, err = in.gz.Write(frame)
+	if err != nil {
+		in.err = fmt.Errorf("error writing snapshot window: %w", err)
+		return in.err
+	}
+	if s == nil {
+		s = &snapshotTypeSummary{hash: sha256.New()}
+		in.types[typ] = s
+	}
+	s.windows++
+	_, _ = s.hash.Write(frame)
+	return nil
+}
+
+// Manifest returns the SnapshotManifest of the recorded windows so far.
+func (in *SnapshotWriter) Manifest() SnapshotManifest {
+	in.m.Lock()
+	defer in.m.Unlock()
+	return in.manifest()
+}
+
+func (in *SnapshotWriter) manifest() SnapshotManifest {
+	var m = SnapshotManifest{
+		Version:    SnapshotVersion,
+		CreateTime: time.Now(),
+		Since:      in.since,
+		Types:      make([]SnapshotManifestType, 0, len(in.types)),
+	}
+	for typ, s := range in.types {
+		m.Types = append(m.Types, SnapshotManifestType{
+			Type:     typ,
+			Windows:  s.windows,
+			Checksum: hex.EncodeToString(s.hash.Sum(nil)),
+		})
+	}
+	sort.Slice(m.Types, func(i, j int) bool {
+		return m.Types[i].Type < m.Types[j].Type
+	})
+	return m
+}
+
+// Close writes the SnapshotManifest and flushes the snapshot,
+// the snapshot is incomplete and cannot be replayed if it is not closed.
+func (in *SnapshotWriter) Close() error {
+	in.m.Lock()
+	defer in.m.Unlock()
+	if in.gz == nil {
+		return nil
+	}
+	var err = in.err
+	if err == nil {
+		var bs []byte
+		bs, err = json.Marshal(in.manifest())
+		if err == nil {
+			var frame = make([]byte, 0, 1+binary.MaxVarintLen64+len(bs))
+			frame = append(frame, snapshotFrameManifest)
+			frame = appendUvarint(frame, uint64(len(bs)))
+			frame = append(frame, bs...)
+			_, err = in.gz.Write(frame)
+		}
+		if err != nil {
+			err = fmt.Errorf("error writing snapshot manifest: %w", err)
+		}
+	}
+	if cerr := in.gz.Close(); err == nil && cerr != nil {
+		err = fmt.Errorf("error flushing snapshot: %w", cerr)
+	}
+	in.gz = nil
+	if in.closer != nil {
+		if cerr := in.closer.Close(); err == nil && cerr != nil {
+			err = fmt.Errorf("error closing snapshot file: %w", cerr)
+		}
+	}
+	return err
+}
+
+// ReadSnapshotManifest returns the SnapshotManifest of the given snapshot file,
+// it verifies the whole snapshot and returns ErrInvalidSnapshot if the snapshot is corrupted.
+func ReadSnapshotManifest(path string) (SnapshotManifest, error) {
+	return scanSnapshot(path, nil)
+}
+
+// scanSnapshot verifies the whole snapshot and returns its SnapshotManifest,
+// the given callback is called with the ordinal of each window frame if not nil.
+func scanSnapshot(path string, onWindow func(ordinal int, f snapshotFrame)) (SnapshotManifest, error) {
+	var r, err = openSnapshotReader(path)
+	if err != nil {
+		return SnapshotManifest{}, err
+	}
+	defer func() { _ = r.close() }()
+
+	var sums = map[schema.DatasetIngestRequestType]*snapshotTypeSummary{}
+	for ordinal := 0; ; ordinal++ {
+		var f, err = r.next()
+		if err != nil {
+			return SnapshotManifest{}, err
+		}
+		if f.manifest == nil {
+			if onWindow != nil {
+				onWindow(ordinal, f)
+			}
+			var s = sums[f.typ]
+			if err = checkSnapshotWindow(f.typ, f.window, s); err != nil {
+				return SnapshotManifest{}, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+			}
+			if s == nil {
+				s = &snapshotTypeSummary{hash: sha256.New()}
+				sums[f.typ] = s
+			}
+			s.windows++
+			_, _ = s.hash.Write(f.raw)
+			continue
+		}
+
+		var m = *f.manifest
+		if len(m.Types) != len(sums) {
+			return SnapshotManifest{}, fmt.Errorf("%w: expected %d types, but got %d",
+				ErrInvalidSnapshot, len(m.Types), len(sums))
+		}
+		for _, t := range m.Types {
+			var s = sums[t.Type]
+			if s == nil || s.windows != t.Windows {
+				return SnapshotManifest{}, fmt.Errorf("%w: windows count mismatched of %s",
+					ErrInvalidSnapshot, t.Type)
+			}
+			if hex.EncodeToString(s.hash.Sum(nil)) != t.Checksum {
+				return SnapshotManifest{}, fmt.Errorf("%w: checksum mismatched of %s",
+					ErrInvalidSnapshot, t.Type)
+			}
+		}
+		return m, nil
+	}
+}
+
+// checkSnapshotWindow checks the given window against the recorded summary of the type,
+// the first recorded window must be window 0, and window 0 cannot be recorded again,
+// otherwise, the type is recorded from a resumed or repeated ingesting, which cannot be replayed.
+func checkSnapshotWindow(typ schema.DatasetIngestRequestType, window int32, s *snapshotTypeSummary) error {
+	switch {
+	case s == nil && window != 0:
+		return fmt.Errorf("missing window 0 of %s before window %d", typ, window)
+	case s != nil && window == 0:
+		return fmt.Errorf("duplicated window 0 of %s", typ)
+	}
+	return nil
+}
+
+// snapshotReader reads the frames of a snapshot file one by one.
+type snapshotReader struct {
+	f  *os.File
+	gz *gzip.Reader
+	br *bufio.Reader
+}
+
+// snapshotFrame holds a frame of the snapshot file,
+// either a window or the manifest.
+type snapshotFrame struct {
+	typ      schema.DatasetIngestRequestType
+	window   int32
+	data     []byte
+	raw      []byte
+	manifest *SnapshotManifest
+}
+
+func openSnapshotReader(path string) (*snapshotReader, error) {
+	var f, err = os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening snapshot file: %w", err)
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	var r = &snapshotReader{f: f, gz: gz, br: bufio.NewReader(gz)}
+	var header = make([]byte, len(snapshotMagic)+1)
+	_, err = io.ReadFull(r.br, header)
+	if err != nil || string(header[:len(snapshotMagic)]) != string(snapshotMagic) {
+		_ = r.close()
+		return nil, fmt.Errorf("%w: unknown header", ErrInvalidSnapshot)
+	}
+	if header[len(snapshotMagic)] != SnapshotVersion {
+		_ = r.close()
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidSnapshot, header[len(snapshotMagic)])
+	}
+	return r, nil
+}
+
+// next returns the next frame, the manifest frame is the last one.
+func (in *snapshotReader) next() (snapshotFrame, error) {
+	var f snapshotFrame
+	var kind, err = in.br.ReadByte()
+	if err != nil {
+		return f, fmt.Errorf("%w: missing manifest: %v", ErrInvalidSnapshot, err)
+	}
+	switch kind {
+	default:
+		return f, fmt.Errorf("%w: unknown frame %d", ErrInvalidSnapshot, kind)
+	case snapshotFrameManifest:
+		var bs, err = in.readBytes()
+		if err != nil {
+			return f, err
+		}
+		var m SnapshotManifest
+		err = json.Unmarshal(bs, &m)
+		if err != nil {
+			return f, fmt.Errorf("%w: malformed manifest: %v", ErrInvalidSnapshot, err)
+		}
+		f.manifest = &m
+		return f, nil
+	case snapshotFrameWindow:
+	}
+	typ, err := binary.ReadUvarint(in.br)
+	if err != nil {
+		return f, fmt.Errorf("%w: malformed window type: %v", ErrInvalidSnapshot, err)
+	}
+	window, err := binary.ReadUvarint(in.br)
+	if err != nil {
+		return f, fmt.Errorf("%w: malformed window: %v", ErrInvalidSnapshot, err)
+	}
+	bs, err := in.readBytes()
+	if err != nil {
+		return f, err
+	}
+	f.typ = schema.DatasetIngestRequestType(typ)
+	f.window = int32(window)
+	f.data = bs
+	f.raw = append([]byte{kind}, appendUvarint(appendUvarint(nil, typ), window)...)
+	f.raw = appendUvarint(f.raw, uint64(len(bs)))
+	f.raw = append(f.raw, bs...)
+	return f, nil
+}
+
+func (in *snapshotReader) readBytes() ([]byte, error) {
+	var n, err = binary.ReadUvarint(in.br)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed frame length: %v", ErrInvalidSnapshot, err)
+	}
+	var bs = make([]byte, n)
+	_, err = io.ReadFull(in.br, bs)
+	if err != nil {
+		return nil, fmt.Errorf("%w: truncated frame: %v", ErrInvalidSnapshot, err)
+	}
+	return bs, nil
+}
+
+func (in *snapshotReader) close() error {
+	_ = in.gz.Close()
+	return in.f.Close()
+}
+
+// appendUvarint appends the varint-encoded x to bs.
+func appendUvarint(bs []byte, x uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(bs, buf[:binary.PutUvarint(buf[:], x)]...)
+}
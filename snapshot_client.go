@@ -0,0 +1,178 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/seal-io/meta-api/schema"
+)
+
+// OpenSnapshotClient returns the Client replaying the given snapshot file,
+// which is recorded by SnapshotWriter, so that the same IngestParser works without the exposing service.
+// The snapshot is verified before returning,
+// and the ingesting since must not be earlier than the since of the snapshot.
+// The windows are replayed in the recorded order,
+// even if the window size is adjusted by WithAdaptiveWindowSize during recording.
+func OpenSnapshotClient(path string) (Client, error) {
+	var index = map[schema.DatasetIngestRequestType]map[int32][]int{}
+	var next = map[int]int32{}
+	var last = map[schema.DatasetIngestRequestType]int{}
+	var m, err = scanSnapshot(path, func(ordinal int, f snapshotFrame) {
+		if index[f.typ] == nil {
+			index[f.typ] = map[int32][]int{}
+		} else {
+			next[last[f.typ]] = f.window
+		}
+		index[f.typ][f.window] = append(index[f.typ][f.window], ordinal)
+		last[f.typ] = ordinal
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &snapshotClient{
+		path:     path,
+		manifest: m,
+		index:    index,
+		next:     next,
+	}, nil
+}
+
+type snapshotClient struct {
+	path     string
+	manifest SnapshotManifest
+	// index holds the ordinals of each recorded window in order,
+	// a window might be recorded more than once if the window size is adjusted during recording.
+	index map[schema.DatasetIngestRequestType]map[int32][]int
+	// next holds the window of the next recorded frame of the same type by ordinal.
+	next map[int]int32
+}
+
+func (in *snapshotClient) Ingest(ctx context.Context, typ schema.DatasetIngestRequestType, since time.Time, parse IngestParser, opts ...IngestOption) error {
+	if since.Before(in.manifest.Since) {
+		return fmt.Errorf("error replaying snapshot: since %s is earlier than the snapshot since %s",
+			since.Format(time.RFC3339), in.manifest.Since.Format(time.RFC3339))
+	}
+	if in.manifest.Get(typ) == nil {
+		return fmt.Errorf("error replaying snapshot: %s is not recorded", typ)
+	}
+	var stream = &snapshotStream{
+		cli:   in,
+		since: since,
+	}
+	// NB: the recorded windows cannot be resized.
+	var o = getIngestOptions(opts)
+	o.WindowSize = 0
+	o.AdaptiveWindow = nil
+	return ingest(ctx, stream, typ, since, parse, o)
+}
+
+func (in *snapshotClient) IngestAll(ctx context.Context, since time.Time, parse IngestParser, opts ...IngestOption) error {
+	return ingestAll(ctx, in, since, parse, opts)
+}
+
+func (in *snapshotClient) Close() error {
+	return nil
+}
+
+// snapshotStream implements the ingestExchanger by reading the snapshot sequentially,
+// it reopens the snapshot if the requested window is behind the reading position.
+type snapshotStream struct {
+	cli   *snapshotClient
+	since time.Time
+	r     *snapshotReader
+	pos   int
+}
+
+func (in *snapshotStream) exchange(ctx context.Context, req *schema.DatasetIngestRequest) (*schema.DatasetIngestResponse, error) {
+	var err = ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+	var ordinals = in.cli.index[req.GetType()][req.GetWindow()]
+	if len(ordinals) == 0 {
+		return nil, fmt.Errorf("error receiving ingest response: window %d of %s is not recorded",
+			req.GetWindow(), req.GetType())
+	}
+	// NB: prefer the first one not before the reading position,
+	// which follows the order of the recorded windows.
+	var ordinal = ordinals[0]
+	for _, o := range ordinals {
+		if o >= in.pos {
+			ordinal = o
+			break
+		}
+	}
+	if in.r == nil || ordinal < in.pos {
+		in.close()
+		in.r, err = openSnapshotReader(in.cli.path)
+		if err != nil {
+			return nil, fmt.Errorf("error creating ingest client: %w", err)
+		}
+	}
+	for {
+		var f snapshotFrame
+		f, err = in.r.next()
+		if err != nil {
+			return nil, fmt.Errorf("error receiving ingest response: %w", err)
+		}
+		in.pos++
+		if in.pos-1 != ordinal {
+			continue
+		}
+		var resp = &schema.DatasetIngestResponse{}
+		err = proto.Unmarshal(f.data, resp)
+		if err != nil {
+			return nil, fmt.Errorf("error receiving ingest response: %w", err)
+		}
+		if next, exist := in.cli.next[ordinal]; exist && resp.NextWindow != nil {
+			// NB: follow the recorded windows rather than the responded next window,
+			// which might be realigned during recording if the window size is adjusted.
+			resp.NextWindow = &next
+		}
+		if in.since.After(in.cli.manifest.Since) {
+			resp.Body = filterSince(resp.GetBody(), in.since)
+		}
+		return resp, nil
+	}
+}
+
+func (in *snapshotStream) close() {
+	if in.r == nil {
+		return
+	}
+	_ = in.r.close()
+	in.r = nil
+	in.pos = 0
+}
+
+// filterSince returns the given body with the items updated not earlier than the given since.
+func filterSince(body schema.DatasetIngestResponseBody, since time.Time) schema.DatasetIngestResponseBody {
+	switch b := body.(type) {
+	case *schema.DatasetIngestResponse_ComplianceLicenseTags:
+		b.ComplianceLicenseTags.Items = filterItemsSince(b.ComplianceLicenseTags.GetItems(), since)
+	case *schema.DatasetIngestResponse_ComplianceLicenses:
+		b.ComplianceLicenses.Items = filterItemsSince(b.ComplianceLicenses.GetItems(), since)
+	case *schema.DatasetIngestResponse_WeaknessVulnerabilityTags:
+		b.WeaknessVulnerabilityTags.Items = filterItemsSince(b.WeaknessVulnerabilityTags.GetItems(), since)
+	case *schema.DatasetIngestResponse_WeaknessVulnerabilities:
+		b.WeaknessVulnerabilities.Items = filterItemsSince(b.WeaknessVulnerabilities.GetItems(), since)
+	case *schema.DatasetIngestResponse_WeaknessVulnerabilityFeatures:
+		b.WeaknessVulnerabilityFeatures.Items = filterItemsSince(b.WeaknessVulnerabilityFeatures.GetItems(), since)
+	}
+	return body
+}
+
+func filterItemsSince[T interface{ GetUpdateTime() *timestamppb.Timestamp }](items []T, since time.Time) []T {
+	var r = items[:0]
+	for i := range items {
+		if items[i].GetUpdateTime().AsTime().Before(since) {
+			continue
+		}
+		r = append(r, items[i])
+	}
+	return r
+}
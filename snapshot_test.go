@@ -0,0 +1,282 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/seal-io/meta-api/schema"
+)
+
+func TestSnapshot(t *testing.T) {
+	var base = time.Date(2022, 11, 3, 0, 0, 0, 0, time.UTC)
+	var srv = &testingDatasetServer{
+		windows: map[schema.DatasetIngestRequestType][]schema.DatasetIngestResponseBody{},
+	}
+	for typ := range schema.DatasetIngestRequestType_name {
+		var windows = testingLicenseTagWindows(3)
+		for i := range windows {
+			var items = windows[i].(*schema.DatasetIngestResponse_ComplianceLicenseTags).ComplianceLicenseTags.Items
+			items[0].UpdateTime = timestamppb.New(base.AddDate(0, 0, i))
+		}
+		srv.windows[schema.DatasetIngestRequestType(typ)] = windows
+	}
+	var path = filepath.Join(t.TempDir(), "dataset", "snapshot.gz")
+
+	// Record.
+	var record = testingParsedNames()
+	var w, err = CreateSnapshot(path, time.Time{})
+	if err != nil {
+		t.Fatalf("error creating snapshot: %v", err)
+	}
+	err = testingClient(t, srv).IngestAll(context.Background(), time.Time{}, record.parse,
+		WithSnapshot(w), WithConcurrency(3))
+	if err != nil {
+		t.Fatalf("error recording snapshot: %v", err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatalf("error closing snapshot: %v", err)
+	}
+
+	m, err := ReadSnapshotManifest(path)
+	if err != nil {
+		t.Fatalf("error reading snapshot manifest: %v", err)
+	}
+	if len(m.Types) != len(schema.DatasetIngestRequestType_name) || m.Types[0].Windows != 3 || m.Types[0].Checksum == "" {
+		t.Errorf("expected manifest of all types with 3 windows, but got %+v", m)
+	}
+
+	// Replay.
+	cli, err := OpenSnapshotClient(path)
+	if err != nil {
+		t.Fatalf("error opening snapshot client: %v", err)
+	}
+	defer func() { _ = cli.Close() }()
+
+	var replay = testingParsedNames()
+	err = cli.IngestAll(context.Background(), time.Time{}, replay.parse)
+	if err != nil {
+		t.Fatalf("error replaying snapshot: %v", err)
+	}
+	if record.String() != replay.String() {
+		t.Errorf("expected replaying %s, but got %s", record, replay)
+	}
+
+	// Replay from checkpoint and since.
+	var typ = schema.DatasetIngestRequestType_Weakness_Vulnerability
+	var store = NewMemoryCheckpointStore()
+	var since = base.AddDate(0, 0, 1)
+	_ = store.Set(context.Background(), Checkpoint{Type: typ, Since: since, NextWindow: 1})
+	var resume = testingParsedNames()
+	err = cli.Ingest(context.Background(), typ, since, resume.parse, WithCheckpoint(store))
+	if err != nil {
+		t.Fatalf("error replaying snapshot from checkpoint: %v", err)
+	}
+	if resume.String() != "[tag-b tag-c]" {
+		t.Errorf("expected replaying [tag-b tag-c], but got %s", resume)
+	}
+	resume = testingParsedNames()
+	err = cli.Ingest(context.Background(), typ, base.AddDate(0, 0, 2), resume.parse)
+	if err != nil {
+		t.Fatalf("error replaying snapshot since: %v", err)
+	}
+	if resume.String() != "[tag-c]" {
+		t.Errorf("expected replaying [tag-c], but got %s", resume)
+	}
+}
+
+func TestSnapshot_WithAdaptiveWindowSize(t *testing.T) {
+	var typ = schema.DatasetIngestRequestType_Weakness_Vulnerability
+	var srv = &testingSizedDatasetServer{
+		items:       100,
+		itemPayload: 100,
+		maxSize:     24,
+	}
+	var parse = func(names *[]string) IngestParser {
+		return func(_ int32, body schema.DatasetIngestResponseBody) error {
+			for _, item := range body.(*schema.DatasetIngestResponse_WeaknessVulnerabilities).WeaknessVulnerabilities.GetItems() {
+				*names = append(*names, item.GetName())
+			}
+			return nil
+		}
+	}
+	var path = filepath.Join(t.TempDir(), "snapshot.gz")
+
+	// Record with resizing windows, some of which have the same window index in different sizes.
+	var record []string
+	var w, err = CreateSnapshot(path, time.Time{})
+	if err != nil {
+		t.Fatalf("error creating snapshot: %v", err)
+	}
+	err = testingSizedClient(t, srv, 4096).Ingest(context.Background(), typ, time.Time{}, parse(&record),
+		WithSnapshot(w), WithAdaptiveWindowSize(AdaptiveWindowPolicy{
+			InitialWindowSize:  2,
+			MinWindowSize:      1,
+			MaxWindowSize:      1000,
+			TargetMessageRatio: 1,
+		}))
+	if err != nil {
+		t.Fatalf("error recording snapshot: %v", err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatalf("error closing snapshot: %v", err)
+	}
+	if sizes := fmt.Sprint(srv.requestedSizes()); sizes != "[2 2 4 8 16 32 16 24 24 24]" {
+		t.Fatalf("expected resizing windows, but got %s", sizes)
+	}
+
+	// Replay.
+	cli, err := OpenSnapshotClient(path)
+	if err != nil {
+		t.Fatalf("error opening snapshot client: %v", err)
+	}
+	defer func() { _ = cli.Close() }()
+
+	var replay []string
+	err = cli.Ingest(context.Background(), typ, time.Time{}, parse(&replay))
+	if err != nil {
+		t.Fatalf("error replaying snapshot: %v", err)
+	}
+	if len(record) != 100 || fmt.Sprint(record) != fmt.Sprint(replay) {
+		t.Errorf("expected replaying %d items %v, but got %d items %v", len(record), record, len(replay), replay)
+	}
+}
+
+func TestSnapshot_WithCheckpoint(t *testing.T) {
+	var typ = schema.DatasetIngestRequestType_Compliance_License_Tag
+	var srv = &testingDatasetServer{
+		windows: map[schema.DatasetIngestRequestType][]schema.DatasetIngestResponseBody{
+			typ: testingLicenseTagWindows(3),
+		},
+	}
+	var w, err = NewSnapshotWriter(io.Discard, time.Time{})
+	if err != nil {
+		t.Fatalf("error creating snapshot: %v", err)
+	}
+	var store = NewMemoryCheckpointStore()
+	_ = store.Set(context.Background(), Checkpoint{Type: typ, NextWindow: 1})
+
+	var names = testingParsedNames()
+	err = testingClient(t, srv).Ingest(context.Background(), typ, time.Time{}, names.parse,
+		WithSnapshot(w), WithCheckpoint(store))
+	if err == nil {
+		t.Fatal("expected error of recording resumed ingesting, but got nil")
+	}
+	if names.String() != "[]" {
+		t.Errorf("expected nothing parsed, but got %s", names)
+	}
+	if m := w.Manifest(); len(m.Types) != 0 {
+		t.Errorf("expected nothing recorded, but got %+v", m)
+	}
+}
+
+func TestSnapshot_Invalid(t *testing.T) {
+	var dir = t.TempDir()
+
+	var mismatched = filepath.Join(dir, "mismatched.gz")
+	var w, err = CreateSnapshot(mismatched, time.Now())
+	if err != nil {
+		t.Fatalf("error creating snapshot: %v", err)
+	}
+	var typ = schema.DatasetIngestRequestType_Compliance_License
+	err = w.Record(typ, 0, &schema.DatasetIngestResponse{})
+	if err != nil {
+		t.Fatalf("error recording snapshot: %v", err)
+	}
+	w.types[typ].windows++
+	_ = w.Close()
+
+	var unclosed = filepath.Join(dir, "unclosed.gz")
+	w, err = CreateSnapshot(unclosed, time.Now())
+	if err != nil {
+		t.Fatalf("error creating snapshot: %v", err)
+	}
+	_ = w.Record(typ, 0, &schema.DatasetIngestResponse{})
+	_ = w.gz.Flush()
+	_ = w.closer.Close()
+
+	var repeated = filepath.Join(dir, "repeated.gz")
+	w, err = CreateSnapshot(repeated, time.Now())
+	if err != nil {
+		t.Fatalf("error creating snapshot: %v", err)
+	}
+	_ = w.Record(typ, 0, &schema.DatasetIngestResponse{})
+	if err = w.Record(typ, 0, &schema.DatasetIngestResponse{}); err == nil {
+		t.Error("expected error of recording window 0 again, but got nil")
+	}
+	if err = w.Record(schema.DatasetIngestRequestType_Weakness_Vulnerability, 1, &schema.DatasetIngestResponse{}); err == nil {
+		t.Error("expected error of recording without window 0, but got nil")
+	}
+	w.types = map[schema.DatasetIngestRequestType]*snapshotTypeSummary{}
+	_ = w.Record(typ, 0, &schema.DatasetIngestResponse{})
+	_ = w.Close()
+
+	var unknown = filepath.Join(dir, "unknown.gz")
+	_ = os.WriteFile(unknown, []byte("not a snapshot"), 0o600)
+
+	for _, path := range []string{mismatched, unclosed, repeated, unknown} {
+		var _, err = OpenSnapshotClient(path)
+		if !errors.Is(err, ErrInvalidSnapshot) {
+			t.Errorf("%s: expected ErrInvalidSnapshot, but got %v", filepath.Base(path), err)
+		}
+	}
+
+	var since = time.Now()
+	var empty = filepath.Join(dir, "empty.gz")
+	w, err = CreateSnapshot(empty, since)
+	if err != nil {
+		t.Fatalf("error creating snapshot: %v", err)
+	}
+	_ = w.Close()
+	cli, err := OpenSnapshotClient(empty)
+	if err != nil {
+		t.Fatalf("error opening snapshot client: %v", err)
+	}
+	if err = cli.Ingest(context.Background(), typ, since.Add(-time.Hour), nil); err == nil {
+		t.Error("expected error of replaying earlier since, but got nil")
+	}
+	if err = cli.Ingest(context.Background(), typ, since, nil); err == nil {
+		t.Error("expected error of replaying unrecorded type, but got nil")
+	}
+}
+
+// testingNames collects the names of the parsed schema.ComplianceLicenseTag,
+// which is safe for concurrent use.
+type testingNames struct {
+	m     sync.Mutex
+	names []string
+}
+
+func testingParsedNames() *testingNames {
+	return &testingNames{}
+}
+
+func (in *testingNames) parse(_ int32, body schema.DatasetIngestResponseBody) error {
+	var b, ok = body.(*schema.DatasetIngestResponse_ComplianceLicenseTags)
+	if !ok {
+		return fmt.Errorf("unexpected body %T", body)
+	}
+	in.m.Lock()
+	defer in.m.Unlock()
+	for _, item := range b.ComplianceLicenseTags.GetItems() {
+		in.names = append(in.names, item.GetName())
+	}
+	return nil
+}
+
+func (in *testingNames) String() string {
+	in.m.Lock()
+	defer in.m.Unlock()
+	var names = append([]string{}, in.names...)
+	sort.Strings(names)
+	return fmt.Sprint(names)
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/seal-io/meta-api/schema"
+)
+
+// ChangeKind is the kind of the Change.
+type ChangeKind uint8
+
+const (
+	// ChangeCreated indicates the item is created since the last syncing.
+	ChangeCreated ChangeKind = iota + 1
+	// ChangeUpdated indicates the item is created before the last syncing, and updated since then.
+	ChangeUpdated
+	// ChangeDeprecated indicates the item is deprecated, which should be deleted by the consumer.
+	ChangeDeprecated
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeCreated:
+		return "created"
+	case ChangeUpdated:
+		return "updated"
+	case ChangeDeprecated:
+		return "deprecated"
+	}
+	return "unknown"
+}
+
+// Change holds a changed item since the last syncing.
+type Change[T any] struct {
+	// Kind is the kind of the change.
+	Kind ChangeKind
+	// Key is the primary key of the item,
+	// which joins the primary key fields with "/", e.g. "<namespace>/<name>/<purl>" of schema.WeaknessVulnerability.
+	Key string
+	// Item is the changed item.
+	Item T
+}
+
+// SyncHandler holds the typed callbacks to handle the changes of syncing,
+// the nil callback is skipped.
+type SyncHandler struct {
+	// OnComplianceLicenseTag handles the change of schema.ComplianceLicenseTag, keyed by name.
+	OnComplianceLicenseTag func(c Change[*schema.ComplianceLicenseTag]) error
+	// OnComplianceLicense handles the change of schema.ComplianceLicense, keyed by namespace/name.
+	OnComplianceLicense func(c Change[*schema.ComplianceLicense]) error
+	// OnWeaknessVulnerabilityTag handles the change of schema.WeaknessVulnerabilityTag, keyed by name.
+	OnWeaknessVulnerabilityTag func(c Change[*schema.WeaknessVulnerabilityTag]) error
+	// OnWeaknessVulnerability handles the change of schema.WeaknessVulnerability, keyed by namespace/name/purl.
+	OnWeaknessVulnerability func(c Change[*schema.WeaknessVulnerability]) error
+	// OnWeaknessVulnerabilityFeature handles the change of schema.WeaknessVulnerabilityFeature, keyed by name.
+	OnWeaknessVulnerabilityFeature func(c Change[*schema.WeaknessVulnerabilityFeature]) error
+}
+
+// NewSyncer returns a Syncer ingesting from the given Client and tracking the Watermark with the given WatermarkStore.
+func NewSyncer(cli Client, store WatermarkStore) *Syncer {
+	return &Syncer{
+		cli:   cli,
+		store: store,
+	}
+}
+
+// Syncer syncs the changes of the dataset incrementally,
+// it ingests the items updated since the Watermark of each type,
+// and advances the Watermark to the latest update time of the items after the whole type synced.
+// The items updated at the Watermark are synced again by the next syncing,
+// so the SyncHandler should be idempotent.
+type Syncer struct {
+	cli   Client
+	store WatermarkStore
+}
+
+// Sync syncs the changes of the specified type dataset,
+// the given IngestOption list is passed to Client.Ingest.
+func (in *Syncer) Sync(ctx context.Context, typ schema.DatasetIngestRequestType, h SyncHandler, opts ...IngestOption) error {
+	var since, err = in.store.Get(ctx, typ)
+	if err != nil {
+		return fmt.Errorf("error getting sync watermark: %w", err)
+	}
+	var mark = since
+	var parse = func(_ int32, body schema.DatasetIngestResponseBody) error {
+		switch b := body.(type) {
+		case *schema.DatasetIngestResponse_ComplianceLicenseTags:
+			return emitChanges(b.ComplianceLicenseTags.GetItems(), since, &mark,
+				func(i *schema.ComplianceLicenseTag) string {
+					return i.GetName()
+				}, h.OnComplianceLicenseTag)
+		case *schema.DatasetIngestResponse_ComplianceLicenses:
+			return emitChanges(b.ComplianceLicenses.GetItems(), since, &mark,
+				func(i *schema.ComplianceLicense) string {
+					return i.GetNamespace() + "/" + i.GetName()
+				}, h.OnComplianceLicense)
+		case *schema.DatasetIngestResponse_WeaknessVulnerabilityTags:
+			return emitChanges(b.WeaknessVulnerabilityTags.GetItems(), since, &mark,
+				func(i *schema.WeaknessVulnerabilityTag) string {
+					return i.GetName()
+				}, h.OnWeaknessVulnerabilityTag)
+		case *schema.DatasetIngestResponse_WeaknessVulnerabilities:
+			return emitChanges(b.WeaknessVulnerabilities.GetItems(), since, &mark,
+				func(i *schema.WeaknessVulnerability) string {
+					return i.GetNamespace() + "/" + i.GetName() + "/" + i.GetPurl()
+				}, h.OnWeaknessVulnerability)
+		case *schema.DatasetIngestResponse_WeaknessVulnerabilityFeatures:
+			return emitChanges(b.WeaknessVulnerabilityFeatures.GetItems(), since, &mark,
+				func(i *schema.WeaknessVulnerabilityFeature) string {
+					return i.GetName()
+				}, h.OnWeaknessVulnerabilityFeature)
+		}
+		return fmt.Errorf("%w: %T", ErrUnknownIngestBody, body)
+	}
+	err = in.cli.Ingest(ctx, typ, since, parse, opts...)
+	if err != nil {
+		return err
+	}
+	if mark.After(since) {
+		err = in.store.Set(ctx, typ, mark)
+		if err != nil {
+			return fmt.Errorf("error recording sync watermark: %w", err)
+		}
+	}
+	return nil
+}
+
+// SyncAll syncs the changes of all types dataset one by one,
+// and returns the first error.
+func (in *Syncer) SyncAll(ctx context.Context, h SyncHandler, opts ...IngestOption) error {
+	for typ := 0; typ < len(schema.DatasetIngestRequestType_name); typ++ {
+		var err = in.Sync(ctx, schema.DatasetIngestRequestType(typ), h, opts...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// changeItem holds the management fields of the items.
+type changeItem interface {
+	GetCreateTime() *timestamppb.Timestamp
+	GetUpdateTime() *timestamppb.Timestamp
+	GetDeprecateTime() *timestamppb.Timestamp
+}
+
+// emitChanges calls the given callback with the classified Change of each item,
+// and advances the given mark to the latest update time of the items.
+func emitChanges[T changeItem](items []T, since time.Time, mark *time.Time, key func(T) string, on func(Change[T]) error) error {
+	for i := range items {
+		if u := items[i].GetUpdateTime(); u != nil && u.AsTime().After(*mark) {
+			*mark = u.AsTime()
+		}
+		if on == nil {
+			continue
+		}
+		var c = Change[T]{
+			Kind: ChangeUpdated,
+			Key:  key(items[i]),
+			Item: items[i],
+		}
+		switch {
+		case items[i].GetDeprecateTime() != nil:
+			c.Kind = ChangeDeprecated
+		case since.IsZero() || items[i].GetCreateTime().AsTime().After(since):
+			// NB: the items created at the Watermark have been synced by the last syncing.
+			c.Kind = ChangeCreated
+		}
+		var err = on(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/seal-io/meta-api/schema"
+)
+
+func TestSyncer(t *testing.T) {
+	var typ = schema.DatasetIngestRequestType_Weakness_Vulnerability
+	var t1 = time.Date(2022, 11, 3, 0, 0, 0, 0, time.UTC)
+	var t2 = t1.AddDate(0, 0, 1)
+	var item = func(name string, create, update time.Time, deprecated bool) *schema.WeaknessVulnerability {
+		var r = &schema.WeaknessVulnerability{
+			Namespace:  "github",
+			Name:       name,
+			Purl:       "pkg:npm/lodash",
+			CreateTime: timestamppb.New(create),
+			UpdateTime: timestamppb.New(update),
+		}
+		if deprecated {
+			r.DeprecateTime = timestamppb.New(update)
+		}
+		return r
+	}
+	var window = func(items ...*schema.WeaknessVulnerability) []schema.DatasetIngestResponseBody {
+		return []schema.DatasetIngestResponseBody{
+			&schema.DatasetIngestResponse_WeaknessVulnerabilities{
+				WeaknessVulnerabilities: &schema.WeaknessVulnerabilities{Items: items},
+			},
+		}
+	}
+
+	var requestedSince []time.Time
+	var srv = &testingDatasetServer{
+		intercept: func(req *schema.DatasetIngestRequest) error {
+			var since time.Time
+			if req.Since != nil {
+				since = req.GetSince().AsTime()
+			}
+			requestedSince = append(requestedSince, since)
+			return nil
+		},
+	}
+	var path = filepath.Join(t.TempDir(), "watermark.json")
+	var store, err = NewFileWatermarkStore(path)
+	if err != nil {
+		t.Fatalf("error creating file watermark store: %v", err)
+	}
+	var s = NewSyncer(testingClient(t, srv), store)
+
+	var testCases = []struct {
+		given         []schema.DatasetIngestResponseBody
+		expected      string
+		expectedSince time.Time
+		expectedMark  time.Time
+	}{
+		{
+			given:         window(item("GHSA-1", t1, t1, false), item("GHSA-2", t1, t1, false)),
+			expected:      "created github/GHSA-1/pkg:npm/lodash; created github/GHSA-2/pkg:npm/lodash",
+			expectedSince: time.Time{},
+			expectedMark:  t1,
+		},
+		{
+			given: window(item("GHSA-1", t1, t2, false), item("GHSA-2", t1, t2, true),
+				item("GHSA-3", t2, t2, false)),
+			expected: "updated github/GHSA-1/pkg:npm/lodash; deprecated github/GHSA-2/pkg:npm/lodash; " +
+				"created github/GHSA-3/pkg:npm/lodash",
+			expectedSince: t1,
+			expectedMark:  t2,
+		},
+		{
+			given:         window(),
+			expected:      "",
+			expectedSince: t2,
+			expectedMark:  t2,
+		},
+	}
+	for i, c := range testCases {
+		srv.windows = map[schema.DatasetIngestRequestType][]schema.DatasetIngestResponseBody{typ: c.given}
+		requestedSince = nil
+		var actual []string
+		err = s.Sync(context.Background(), typ, SyncHandler{
+			OnWeaknessVulnerability: func(c Change[*schema.WeaknessVulnerability]) error {
+				actual = append(actual, c.Kind.String()+" "+c.Key)
+				return nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("#%d unexpected error: %v", i+1, err)
+		}
+		if strings.Join(actual, "; ") != c.expected {
+			t.Errorf("#%d expected changes %q, but got %q", i+1, c.expected, strings.Join(actual, "; "))
+		}
+		if len(requestedSince) == 0 || !requestedSince[0].Equal(c.expectedSince) {
+			t.Errorf("#%d expected requesting since %v, but got %v", i+1, c.expectedSince, requestedSince)
+		}
+		if mark, _ := store.Get(context.Background(), typ); !mark.Equal(c.expectedMark) {
+			t.Errorf("#%d expected watermark %v, but got %v", i+1, c.expectedMark, mark)
+		}
+	}
+
+	// reload from file.
+	reloaded, err := NewFileWatermarkStore(path)
+	if err != nil {
+		t.Fatalf("error reloading file watermark store: %v", err)
+	}
+	if mark, _ := reloaded.Get(context.Background(), typ); !mark.Equal(t2) {
+		t.Errorf("expected reloaded watermark %v, but got %v", t2, mark)
+	}
+}
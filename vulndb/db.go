@@ -0,0 +1,566 @@
+package vulndb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	api "github.com/seal-io/meta-api"
+	"github.com/seal-io/meta-api/genver"
+	"github.com/seal-io/meta-api/model"
+	"github.com/seal-io/meta-api/packageurl"
+	"github.com/seal-io/meta-api/schema"
+	"github.com/seal-io/meta-api/wfn"
+)
+
+// Types is the dataset types persisted by the DB, in order of updating.
+var Types = []schema.DatasetIngestRequestType{
+	schema.DatasetIngestRequestType_Weakness_Vulnerability_Tag,
+	schema.DatasetIngestRequestType_Weakness_Vulnerability,
+	schema.DatasetIngestRequestType_Weakness_Vulnerability_Feature,
+}
+
+var (
+	bucketWatermarks     = []byte("watermarks")
+	bucketPurlIndex      = []byte("index_purl")
+	bucketPurlFuzzyIndex = []byte("index_purl_fuzzy")
+	bucketCVEIndex       = []byte("index_cve")
+	bucketCPEIndex       = []byte("index_cpe")
+)
+
+// Open opens the DB persisting in the given file,
+// the file is created if not exists.
+func Open(path string, opts ...OpenOption) (*DB, error) {
+	var o = _OpenOptions{
+		Timeout: 5 * time.Second,
+	}
+	for i := range opts {
+		if opts[i] == nil {
+			continue
+		}
+		opts[i](&o)
+	}
+
+	if !o.ReadOnly {
+		var err = os.MkdirAll(filepath.Dir(path), 0o700)
+		if err != nil {
+			return nil, fmt.Errorf("error creating database directory: %w", err)
+		}
+	}
+	var db, err = bolt.Open(path, 0o600, &bolt.Options{
+		Timeout:  o.Timeout,
+		ReadOnly: o.ReadOnly,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error opening database file %s: %w", path, err)
+	}
+	if !o.ReadOnly {
+		err = db.Update(func(tx *bolt.Tx) error {
+			for _, name := range [][]byte{
+				bucketWatermarks, bucketPurlIndex, bucketPurlFuzzyIndex, bucketCVEIndex, bucketCPEIndex,
+				vulnerabilityTags.bucket, vulnerabilities.bucket, vulnerabilityFeatures.bucket,
+			} {
+				var _, err = tx.CreateBucketIfNotExists(name)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("error initializing database buckets: %w", err)
+		}
+	}
+	return &DB{db: db}, nil
+}
+
+type _OpenOptions struct {
+	Timeout  time.Duration
+	ReadOnly bool
+}
+
+// OpenOption configures the DB at Open.
+type OpenOption func(*_OpenOptions)
+
+// WithTimeout specifies the timeout of waiting for the file lock,
+// which is held by the other process opening the same file, default is 5s.
+func WithTimeout(timeout time.Duration) OpenOption {
+	return func(o *_OpenOptions) {
+		o.Timeout = timeout
+	}
+}
+
+// WithReadOnly opens the DB in read-only mode,
+// which shares the file lock with the other read-only processes,
+// and fails on updating.
+func WithReadOnly() OpenOption {
+	return func(o *_OpenOptions) {
+		o.ReadOnly = true
+	}
+}
+
+// DB is an embedded vulnerability database,
+// it is safe for concurrent use.
+type DB struct {
+	db *bolt.DB
+}
+
+// Close closes the DB.
+func (in *DB) Close() error {
+	return in.db.Close()
+}
+
+// Watermark returns the latest update time of the persisted items of the given type,
+// returns zero time if never updated.
+func (in *DB) Watermark(typ schema.DatasetIngestRequestType) (time.Time, error) {
+	var r time.Time
+	var err = in.db.View(func(tx *bolt.Tx) error {
+		var b = tx.Bucket(bucketWatermarks)
+		if b == nil {
+			return nil
+		}
+		var bs = b.Get([]byte(typ.String()))
+		if bs == nil {
+			return nil
+		}
+		return r.UnmarshalBinary(bs)
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error getting watermark: %w", err)
+	}
+	return r, nil
+}
+
+// Update ingests the items of Types updated since the Watermark of each type from the given api.Client,
+// puts the items into the DB and deletes the deprecated items,
+// the given IngestOption list is passed to api.Client.Ingest.
+// Each window is written in a transaction,
+// and the Watermark is advanced after the whole type ingested,
+// so the interrupted Update can be resumed by the next Update.
+func (in *DB) Update(ctx context.Context, cli api.Client, opts ...api.IngestOption) error {
+	for _, typ := range Types {
+		var err = in.update(ctx, cli, typ, opts...)
+		if err != nil {
+			return fmt.Errorf("error updating %s: %w", typ, err)
+		}
+	}
+	return nil
+}
+
+func (in *DB) update(ctx context.Context, cli api.Client, typ schema.DatasetIngestRequestType, opts ...api.IngestOption) error {
+	var since, err = in.Watermark(typ)
+	if err != nil {
+		return err
+	}
+	var m sync.Mutex
+	var mark = since
+	var parse = func(_ int32, body schema.DatasetIngestResponseBody) error {
+		var latest time.Time
+		var err = in.db.Update(func(tx *bolt.Tx) (err error) {
+			switch b := body.(type) {
+			default:
+				return fmt.Errorf("%w: %T", api.ErrUnknownIngestBody, body)
+			case *schema.DatasetIngestResponse_WeaknessVulnerabilityTags:
+				latest, err = vulnerabilityTags.put(tx, b.WeaknessVulnerabilityTags.GetItems())
+			case *schema.DatasetIngestResponse_WeaknessVulnerabilities:
+				latest, err = vulnerabilities.put(tx, b.WeaknessVulnerabilities.GetItems())
+			case *schema.DatasetIngestResponse_WeaknessVulnerabilityFeatures:
+				latest, err = vulnerabilityFeatures.put(tx, b.WeaknessVulnerabilityFeatures.GetItems())
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		m.Lock()
+		defer m.Unlock()
+		if latest.After(mark) {
+			mark = latest
+		}
+		return nil
+	}
+	err = cli.Ingest(ctx, typ, since, parse, opts...)
+	if err != nil {
+		return err
+	}
+	if !mark.After(since) {
+		return nil
+	}
+	err = in.db.Update(func(tx *bolt.Tx) error {
+		var bs, err = mark.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(bucketWatermarks).Put([]byte(typ.String()), bs)
+	})
+	if err != nil {
+		return fmt.Errorf("error recording watermark: %w", err)
+	}
+	return nil
+}
+
+// Vulnerability returns the vulnerability of the given primary key,
+// returns nil if not found.
+func (in *DB) Vulnerability(namespace, name, purl string) (r *schema.WeaknessVulnerability, err error) {
+	err = in.db.View(func(tx *bolt.Tx) error {
+		r, err = vulnerabilities.get(tx, joinKey(namespace, name, purl))
+		return err
+	})
+	return r, err
+}
+
+// VulnerabilityTag returns the vulnerability tag of the given name,
+// returns nil if not found.
+func (in *DB) VulnerabilityTag(name string) (r *schema.WeaknessVulnerabilityTag, err error) {
+	err = in.db.View(func(tx *bolt.Tx) error {
+		r, err = vulnerabilityTags.get(tx, joinKey(name))
+		return err
+	})
+	return r, err
+}
+
+// VulnerabilityFeature returns the vulnerability feature of the given name,
+// returns nil if not found.
+func (in *DB) VulnerabilityFeature(name string) (r *schema.WeaknessVulnerabilityFeature, err error) {
+	err = in.db.View(func(tx *bolt.Tx) error {
+		r, err = vulnerabilityFeatures.get(tx, joinKey(name))
+		return err
+	})
+	return r, err
+}
+
+// VulnerabilitiesByPurl returns the vulnerabilities of the package
+// which has the same type, namespace and name as the given package URL, e.g. "pkg:npm/lodash@4.17.15",
+// both the purl and the purl_fuzzy of the vulnerability are matched.
+// If the version of the given package URL is not empty,
+// only the vulnerabilities affecting the version are returned.
+func (in *DB) VulnerabilitiesByPurl(purl string) ([]*schema.WeaknessVulnerability, error) {
+	var p, err = packageurl.FromString(purl)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing purl: %w", err)
+	}
+	return in.vulnerabilitiesByPurl(bucketPurlIndex, prefixKey(p.Type, p.Namespace, p.Name), p.Version)
+}
+
+// VulnerabilitiesByPurlFuzzy returns the vulnerabilities
+// whose purl_fuzzy has the same type and the same name case-insensitively as the given package URL,
+// regardless of the namespace, e.g. "pkg:maven/log4j-core@2.14.1".
+// If the version of the given package URL is not empty,
+// only the vulnerabilities affecting the version are returned.
+func (in *DB) VulnerabilitiesByPurlFuzzy(purl string) ([]*schema.WeaknessVulnerability, error) {
+	var p, err = packageurl.FromString(purl)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing purl: %w", err)
+	}
+	return in.vulnerabilitiesByPurl(bucketPurlFuzzyIndex, prefixKey(p.Type, strings.ToLower(p.Name)), p.Version)
+}
+
+func (in *DB) vulnerabilitiesByPurl(bucket, prefix []byte, version string) ([]*schema.WeaknessVulnerability, error) {
+	var rs []*schema.WeaknessVulnerability
+	var err = in.db.View(func(tx *bolt.Tx) error {
+		return scan(tx, bucket, prefix, func(key []byte) error {
+			var r, err = vulnerabilities.get(tx, key)
+			if err != nil || r == nil {
+				return err
+			}
+			if version != "" && (r.GetAffected() == "" || !genver.InRange(version, r.GetAffected())) {
+				return nil
+			}
+			rs = append(rs, r)
+			return nil
+		})
+	})
+	return rs, err
+}
+
+// VulnerabilitiesByCVE returns the vulnerabilities referring to the given CVE ID, e.g. "CVE-2020-8203",
+// by the code, the name or the tags.
+func (in *DB) VulnerabilitiesByCVE(cve string) ([]*schema.WeaknessVulnerability, error) {
+	var rs []*schema.WeaknessVulnerability
+	var err = in.db.View(func(tx *bolt.Tx) error {
+		return scan(tx, bucketCVEIndex, prefixKey(strings.ToUpper(cve)), func(key []byte) error {
+			var r, err = vulnerabilities.get(tx, key)
+			if err != nil || r == nil {
+				return err
+			}
+			rs = append(rs, r)
+			return nil
+		})
+	})
+	return rs, err
+}
+
+// VulnerabilityTagsByCPE returns the vulnerability tags with any CPE matching the given CPE name,
+// e.g. "cpe:2.3:a:lodash:lodash:4.17.15:*:*:*:*:node.js:*:*".
+// If the version of the given CPE name is specified,
+// only the vulnerability tags affecting the version are returned.
+func (in *DB) VulnerabilityTagsByCPE(cpe string) ([]*schema.WeaknessVulnerabilityTag, error) {
+	var q, err = wfn.Parse(cpe)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing cpe: %w", err)
+	}
+	var version = q.Version
+	var qv = foldAttributes(*q)
+	qv.Version = wfn.Any
+
+	var prefix []byte
+	switch {
+	case qv.Vendor == wfn.Any || wfn.HasWildcard(qv.Vendor):
+	case qv.Product == wfn.Any || wfn.HasWildcard(qv.Product):
+		prefix = prefixKey(qv.Vendor)
+	default:
+		prefix = prefixKey(qv.Vendor, qv.Product)
+	}
+	var rs []*schema.WeaknessVulnerabilityTag
+	var seen = map[string]struct{}{}
+	err = in.db.View(func(tx *bolt.Tx) error {
+		return scan(tx, bucketCPEIndex, prefix, func(key []byte) error {
+			var name = key[bytes.LastIndexByte(key, 0)+1:]
+			if _, ok := seen[string(name)]; ok {
+				return nil
+			}
+			seen[string(name)] = struct{}{}
+			var r, err = vulnerabilityTags.get(tx, name)
+			if err != nil || r == nil {
+				return err
+			}
+			var t, _ = model.DecodeWeaknessVulnerabilityTag(r)
+			for i := range t.CPEs {
+				if t.CPEs[i].Attributes == nil {
+					continue
+				}
+				var a = foldAttributes(*t.CPEs[i].Attributes)
+				if !wfn.Match(&a, &qv) {
+					continue
+				}
+				if version != wfn.Any && version != wfn.NA && !t.CPEs[i].IsAffected(wfn.StripSlashes(version)) {
+					continue
+				}
+				rs = append(rs, r)
+				break
+			}
+			return nil
+		})
+	})
+	return rs, err
+}
+
+// foldAttributes returns the lower case of the given wfn.Attributes,
+// as the CPE names are matched case-insensitively.
+func foldAttributes(a wfn.Attributes) wfn.Attributes {
+	for _, s := range []*string{
+		&a.Part, &a.Vendor, &a.Product, &a.Version, &a.Update, &a.Edition,
+		&a.SWEdition, &a.TargetSW, &a.TargetHW, &a.Other, &a.Language,
+	} {
+		*s = strings.ToLower(*s)
+	}
+	return a
+}
+
+// scan calls the given callback with the key suffix of each entry prefixed with the given prefix.
+func scan(tx *bolt.Tx, bucket, prefix []byte, fn func(key []byte) error) error {
+	var b = tx.Bucket(bucket)
+	if b == nil {
+		return nil
+	}
+	var c = b.Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		var err = fn(k[len(prefix):])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// joinKey joins the given fields with a zero byte as the primary key.
+func joinKey(fields ...string) []byte {
+	return []byte(strings.Join(fields, "\x00"))
+}
+
+// prefixKey joins the given fields with a zero byte and terminates with a zero byte,
+// which is used as the prefix of the index entries.
+func prefixKey(fields ...string) []byte {
+	return append(joinKey(fields...), 0)
+}
+
+// item holds the management fields of the persisted items.
+type item interface {
+	proto.Message
+	GetUpdateTime() *timestamppb.Timestamp
+	GetDeprecateTime() *timestamppb.Timestamp
+}
+
+// index holds an entry of the index bucket.
+type index struct {
+	bucket []byte
+	key    []byte
+}
+
+// table persists the items of a type in a bucket keyed by the primary key,
+// and maintains the index entries of the items.
+type table[T item] struct {
+	bucket  []byte
+	new     func() T
+	key     func(T) []byte
+	indexes func(T, []byte) []index
+}
+
+var vulnerabilityTags = table[*schema.WeaknessVulnerabilityTag]{
+	bucket: []byte("vulnerability_tags"),
+	new:    func() *schema.WeaknessVulnerabilityTag { return &schema.WeaknessVulnerabilityTag{} },
+	key: func(i *schema.WeaknessVulnerabilityTag) []byte {
+		return joinKey(i.GetName())
+	},
+	indexes: func(i *schema.WeaknessVulnerabilityTag, key []byte) []index {
+		// NB: the CPEs of the undecodable tag are not indexed.
+		var t, _ = model.DecodeWeaknessVulnerabilityTag(i)
+		var r = make([]index, 0, len(t.CPEs))
+		for j := range t.CPEs {
+			var a = t.CPEs[j].Attributes
+			if a == nil {
+				continue
+			}
+			r = append(r, index{bucket: bucketCPEIndex,
+				key: append(prefixKey(strings.ToLower(a.Vendor), strings.ToLower(a.Product)), key...)})
+		}
+		return r
+	},
+}
+
+var vulnerabilities = table[*schema.WeaknessVulnerability]{
+	bucket: []byte("vulnerabilities"),
+	new:    func() *schema.WeaknessVulnerability { return &schema.WeaknessVulnerability{} },
+	key: func(i *schema.WeaknessVulnerability) []byte {
+		return joinKey(i.GetNamespace(), i.GetName(), i.GetPurl())
+	},
+	indexes: func(i *schema.WeaknessVulnerability, key []byte) []index {
+		// NB: the tags and the purl of the undecodable vulnerability are still decoded if valid.
+		var v, _ = model.DecodeWeaknessVulnerability(i)
+		var r []index
+		for _, s := range []string{v.Purl, v.PurlFuzzy} {
+			if s == "" {
+				continue
+			}
+			var p, err = packageurl.FromString(s)
+			if err != nil {
+				continue
+			}
+			r = append(r, index{bucket: bucketPurlIndex, key: append(prefixKey(p.Type, p.Namespace, p.Name), key...)})
+		}
+		if v.PurlFuzzy != "" {
+			var p, err = packageurl.FromString(v.PurlFuzzy)
+			if err == nil {
+				r = append(r, index{bucket: bucketPurlFuzzyIndex, key: append(prefixKey(p.Type, strings.ToLower(p.Name)), key...)})
+			}
+		}
+		for _, s := range append([]string{v.Code, v.Name}, v.Tags...) {
+			s = strings.ToUpper(s)
+			if !strings.HasPrefix(s, "CVE-") {
+				continue
+			}
+			r = append(r, index{bucket: bucketCVEIndex, key: append(prefixKey(s), key...)})
+		}
+		return r
+	},
+}
+
+var vulnerabilityFeatures = table[*schema.WeaknessVulnerabilityFeature]{
+	bucket: []byte("vulnerability_features"),
+	new:    func() *schema.WeaknessVulnerabilityFeature { return &schema.WeaknessVulnerabilityFeature{} },
+	key: func(i *schema.WeaknessVulnerabilityFeature) []byte {
+		return joinKey(i.GetName())
+	},
+}
+
+// get returns the item of the given primary key,
+// returns nil if not found.
+func (in table[T]) get(tx *bolt.Tx, key []byte) (T, error) {
+	var r T
+	var b = tx.Bucket(in.bucket)
+	if b == nil {
+		return r, nil
+	}
+	var bs = b.Get(key)
+	if bs == nil {
+		return r, nil
+	}
+	r = in.new()
+	var err = proto.Unmarshal(bs, r)
+	if err != nil {
+		return r, fmt.Errorf("error decoding %s item %q: %w", in.bucket, key, err)
+	}
+	return r, nil
+}
+
+// put replaces the given items with their index entries, and deletes the deprecated items,
+// returns the latest update time of the given items.
+func (in table[T]) put(tx *bolt.Tx, items []T) (time.Time, error) {
+	var latest time.Time
+	var b = tx.Bucket(in.bucket)
+	if b == nil {
+		return latest, errors.New("database is not initialized")
+	}
+	for _, i := range items {
+		if u := i.GetUpdateTime(); u != nil && u.AsTime().After(latest) {
+			latest = u.AsTime()
+		}
+		var key = in.key(i)
+		var err = in.delete(tx, key)
+		if err != nil {
+			return latest, err
+		}
+		if i.GetDeprecateTime() != nil {
+			continue
+		}
+		bs, err := proto.Marshal(i)
+		if err != nil {
+			return latest, fmt.Errorf("error encoding %s item %q: %w", in.bucket, key, err)
+		}
+		err = b.Put(key, bs)
+		if err != nil {
+			return latest, err
+		}
+		if in.indexes == nil {
+			continue
+		}
+		for _, x := range in.indexes(i, key) {
+			err = tx.Bucket(x.bucket).Put(x.key, nil)
+			if err != nil {
+				return latest, err
+			}
+		}
+	}
+	return latest, nil
+}
+
+// delete deletes the item of the given primary key with its index entries.
+func (in table[T]) delete(tx *bolt.Tx, key []byte) error {
+	if tx.Bucket(in.bucket).Get(key) == nil {
+		return nil
+	}
+	if in.indexes != nil {
+		var old, err = in.get(tx, key)
+		if err != nil {
+			return err
+		}
+		for _, x := range in.indexes(old, key) {
+			err = tx.Bucket(x.bucket).Delete(x.key)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Bucket(in.bucket).Delete(key)
+}
@@ -0,0 +1,232 @@
+package vulndb
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	api "github.com/seal-io/meta-api"
+	"github.com/seal-io/meta-api/schema"
+	"github.com/seal-io/meta-api/server"
+)
+
+func TestDB(t *testing.T) {
+	var t1 = time.Date(2022, 11, 3, 0, 0, 0, 0, time.UTC)
+	var t2 = t1.AddDate(0, 0, 1)
+	var vuln = func(ns, name, purl, affected string, update time.Time, deprecated bool) *schema.WeaknessVulnerability {
+		var r = &schema.WeaknessVulnerability{
+			Namespace:  ns,
+			Name:       name,
+			Purl:       purl,
+			CreateTime: timestamppb.New(t1),
+			UpdateTime: timestamppb.New(update),
+			Tags:       []byte(`["CVE-2020-8203"]`),
+			Affected:   affected,
+		}
+		if deprecated {
+			r.DeprecateTime = timestamppb.New(update)
+		}
+		return r
+	}
+	var fuzzy = vuln("osv", "GHSA-jfh8-c2jp-5v3q", "pkg:maven/org.apache.logging.log4j/log4j-core", "<2.15.0", t1, false)
+	fuzzy.PurlFuzzy = "pkg:maven/org.apache.logging.log4j/Log4j-Core"
+	fuzzy.Tags = nil
+	var store = server.NewMemoryStore()
+	_ = store.Add(0, &schema.DatasetIngestResponse_WeaknessVulnerabilityTags{
+		WeaknessVulnerabilityTags: &schema.WeaknessVulnerabilityTags{
+			Items: []*schema.WeaknessVulnerabilityTag{
+				{
+					Name:       "CVE-2020-8203",
+					UpdateTime: timestamppb.New(t1),
+					Cpes:       []byte(`[{"criteria":"cpe:2.3:a:LoDash:lodash:*:*:*:*:*:Node.js:*:*","versionEndExcluding":"4.17.19"}]`),
+				},
+			},
+		},
+	})
+	_ = store.Add(0, &schema.DatasetIngestResponse_WeaknessVulnerabilities{
+		WeaknessVulnerabilities: &schema.WeaknessVulnerabilities{
+			Items: []*schema.WeaknessVulnerability{
+				vuln("github", "GHSA-p6mc-m468-83gw", "pkg:npm/lodash", ">=3.7.0,<4.17.19", t1, false),
+				vuln("nvd", "CVE-2020-8203", "pkg:npm/lodash", "<4.17.19", t1, false),
+				vuln("github", "GHSA-jf85-cpcp-j695", "pkg:npm/lodash-es", "<4.17.12", t1, false),
+				fuzzy,
+			},
+		},
+	})
+
+	var path = filepath.Join(t.TempDir(), "vuln.db")
+	var db, err = Open(path)
+	if err != nil {
+		t.Fatalf("error opening database: %v", err)
+	}
+	err = db.Update(context.Background(), &testingClient{store: store})
+	if err != nil {
+		t.Fatalf("error updating database: %v", err)
+	}
+
+	var query = func(name string, fn func(string) ([]*schema.WeaknessVulnerability, error), q string) string {
+		var rs, err = fn(q)
+		if err != nil {
+			t.Fatalf("error querying %s of %s: %v", name, q, err)
+		}
+		var names = make([]string, 0, len(rs))
+		for i := range rs {
+			names = append(names, rs[i].GetNamespace()+"/"+rs[i].GetName())
+		}
+		return fmt.Sprint(names)
+	}
+	var queryTags = func(q string) string {
+		var rs, err = db.VulnerabilityTagsByCPE(q)
+		if err != nil {
+			t.Fatalf("error querying tags of %s: %v", q, err)
+		}
+		var names = make([]string, 0, len(rs))
+		for i := range rs {
+			names = append(names, rs[i].GetName())
+		}
+		return fmt.Sprint(names)
+	}
+
+	var testCases = []struct {
+		given    string
+		expected string
+	}{
+		{
+			given:    query("purl", db.VulnerabilitiesByPurl, "pkg:npm/lodash@4.17.15"),
+			expected: "[github/GHSA-p6mc-m468-83gw nvd/CVE-2020-8203]",
+		},
+		{
+			given:    query("purl", db.VulnerabilitiesByPurl, "pkg:npm/lodash@3.6.0"),
+			expected: "[nvd/CVE-2020-8203]",
+		},
+		{
+			given:    query("purl", db.VulnerabilitiesByPurl, "pkg:npm/lodash@4.17.19"),
+			expected: "[]",
+		},
+		{
+			given:    query("purl", db.VulnerabilitiesByPurl, "pkg:npm/lodash-es"),
+			expected: "[github/GHSA-jf85-cpcp-j695]",
+		},
+		{
+			given:    query("purl", db.VulnerabilitiesByPurl, "pkg:maven/log4j/log4j-core@2.14.1"),
+			expected: "[]",
+		},
+		{
+			given:    query("purl_fuzzy", db.VulnerabilitiesByPurlFuzzy, "pkg:maven/log4j/log4j-core@2.14.1"),
+			expected: "[osv/GHSA-jfh8-c2jp-5v3q]",
+		},
+		{
+			given:    query("purl_fuzzy", db.VulnerabilitiesByPurlFuzzy, "pkg:maven/LOG4J-CORE@2.15.0"),
+			expected: "[]",
+		},
+		{
+			given:    query("purl_fuzzy", db.VulnerabilitiesByPurlFuzzy, "pkg:maven/LOG4J-CORE"),
+			expected: "[osv/GHSA-jfh8-c2jp-5v3q]",
+		},
+		{
+			given:    query("cve", db.VulnerabilitiesByCVE, "cve-2020-8203"),
+			expected: "[github/GHSA-jf85-cpcp-j695 github/GHSA-p6mc-m468-83gw nvd/CVE-2020-8203]",
+		},
+		{
+			given:    queryTags("cpe:2.3:a:lodash:lodash:4.17.15:*:*:*:*:node.js:*:*"),
+			expected: "[CVE-2020-8203]",
+		},
+		{
+			given:    queryTags("cpe:2.3:a:lodash:lodash:4.17.19:*:*:*:*:node.js:*:*"),
+			expected: "[]",
+		},
+		{
+			given:    queryTags("cpe:2.3:a:lodash:*:*:*:*:*:*:*:*:*"),
+			expected: "[CVE-2020-8203]",
+		},
+		{
+			given:    queryTags("cpe:2.3:a:Lodash:LODASH:4.17.15:*:*:*:*:node.js:*:*"),
+			expected: "[CVE-2020-8203]",
+		},
+	}
+	for i, c := range testCases {
+		if c.given != c.expected {
+			t.Errorf("#%d expected %s, but got %s", i+1, c.expected, c.given)
+		}
+	}
+
+	// update incrementally, then reopen.
+	store = server.NewMemoryStore()
+	_ = store.Add(0, &schema.DatasetIngestResponse_WeaknessVulnerabilities{
+		WeaknessVulnerabilities: &schema.WeaknessVulnerabilities{
+			Items: []*schema.WeaknessVulnerability{
+				vuln("github", "GHSA-p6mc-m468-83gw", "pkg:npm/lodash", ">=3.7.0,<4.17.19", t2, true),
+				vuln("nvd", "CVE-2020-8203", "pkg:npm/lodash", "<4.17.16", t2, false),
+			},
+		},
+	})
+	var cli = &testingClient{store: store}
+	err = db.Update(context.Background(), cli)
+	if err != nil {
+		t.Fatalf("error updating database incrementally: %v", err)
+	}
+	if expected := t1; !cli.since[schema.DatasetIngestRequestType_Weakness_Vulnerability].Equal(expected) {
+		t.Errorf("expected updating since %v, but got %v", expected, cli.since)
+	}
+	_ = db.Close()
+	db, err = Open(path, WithReadOnly())
+	if err != nil {
+		t.Fatalf("error reopening database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	if mark, _ := db.Watermark(schema.DatasetIngestRequestType_Weakness_Vulnerability); !mark.Equal(t2) {
+		t.Errorf("expected watermark %v, but got %v", t2, mark)
+	}
+	if actual, expected := query("purl", db.VulnerabilitiesByPurl, "pkg:npm/lodash@4.17.15"), "[nvd/CVE-2020-8203]"; actual != expected {
+		t.Errorf("expected %s after updating, but got %s", expected, actual)
+	}
+	if actual, expected := query("purl", db.VulnerabilitiesByPurl, "pkg:npm/lodash@4.17.16"), "[]"; actual != expected {
+		t.Errorf("expected %s after updating, but got %s", expected, actual)
+	}
+	if actual, expected := query("cve", db.VulnerabilitiesByCVE, "CVE-2020-8203"), "[github/GHSA-jf85-cpcp-j695 nvd/CVE-2020-8203]"; actual != expected {
+		t.Errorf("expected %s after updating, but got %s", expected, actual)
+	}
+	if r, err := db.Vulnerability("github", "GHSA-p6mc-m468-83gw", "pkg:npm/lodash"); err != nil || r != nil {
+		t.Errorf("expected deprecated vulnerability deleted, but got %v, %v", r, err)
+	}
+	if r, err := db.VulnerabilityTag("CVE-2020-8203"); err != nil || r == nil {
+		t.Errorf("expected vulnerability tag kept, but got %v, %v", r, err)
+	}
+}
+
+// testingClient is an api.Client ingesting all items updated since the given time from a server.Store in a window of 1000 items.
+type testingClient struct {
+	store server.Store
+	since map[schema.DatasetIngestRequestType]time.Time
+}
+
+func (in *testingClient) Ingest(ctx context.Context, typ schema.DatasetIngestRequestType, since time.Time, parse api.IngestParser, _ ...api.IngestOption) error {
+	if in.since == nil {
+		in.since = map[schema.DatasetIngestRequestType]time.Time{}
+	}
+	in.since[typ] = since
+	var body, _, err = in.store.List(ctx, typ, since, 0, 1000)
+	if err != nil {
+		return err
+	}
+	return parse(0, body)
+}
+
+func (in *testingClient) IngestAll(ctx context.Context, since time.Time, parse api.IngestParser, opts ...api.IngestOption) error {
+	for typ := 0; typ < len(schema.DatasetIngestRequestType_name); typ++ {
+		var err = in.Ingest(ctx, schema.DatasetIngestRequestType(typ), since, parse, opts...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (in *testingClient) Close() error {
+	return nil
+}
@@ -0,0 +1,5 @@
+// Package vulndb provides an embedded vulnerability database,
+// which persists the ingested vulnerabilities, vulnerability tags and vulnerability features into a local bbolt file,
+// indexes the vulnerabilities by package URL, fuzzy package URL and CVE, and the vulnerability tags by CPE,
+// and updates incrementally from api.Client.
+package vulndb
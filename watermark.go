@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/seal-io/meta-api/schema"
+)
+
+// Watermark holds the last synced time of the specified type dataset.
+type Watermark struct {
+	// Type is the type of the synced dataset.
+	Type schema.DatasetIngestRequestType `json:"type"`
+	// Time is the latest update time of the synced items,
+	// which is used as the since condition of the next syncing.
+	Time time.Time `json:"time"`
+}
+
+// WatermarkStore holds the actions for persisting the Watermark of syncing.
+type WatermarkStore interface {
+	// Get returns the Watermark time of the given type,
+	// returns zero time if not found.
+	Get(ctx context.Context, typ schema.DatasetIngestRequestType) (time.Time, error)
+
+	// Set records the Watermark time of the given type.
+	Set(ctx context.Context, typ schema.DatasetIngestRequestType, t time.Time) error
+}
+
+// NewMemoryWatermarkStore returns a WatermarkStore keeping in memory,
+// which is lost after the process exits.
+func NewMemoryWatermarkStore() WatermarkStore {
+	return &memoryWatermarkStore{
+		records: map[schema.DatasetIngestRequestType]time.Time{},
+	}
+}
+
+type memoryWatermarkStore struct {
+	m       sync.RWMutex
+	records map[schema.DatasetIngestRequestType]time.Time
+}
+
+func (in *memoryWatermarkStore) Get(_ context.Context, typ schema.DatasetIngestRequestType) (time.Time, error) {
+	in.m.RLock()
+	defer in.m.RUnlock()
+	return in.records[typ], nil
+}
+
+func (in *memoryWatermarkStore) Set(_ context.Context, typ schema.DatasetIngestRequestType, t time.Time) error {
+	in.m.Lock()
+	defer in.m.Unlock()
+	in.records[typ] = t
+	return nil
+}
+
+// NewFileWatermarkStore returns a WatermarkStore persisting in the given file,
+// the file is created if not exists.
+func NewFileWatermarkStore(path string) (WatermarkStore, error) {
+	var wms []Watermark
+	var err = readJSONFile(path, "watermark", &wms)
+	if err != nil {
+		return nil, err
+	}
+	var records = make(map[schema.DatasetIngestRequestType]time.Time, len(wms))
+	for i := range wms {
+		records[wms[i].Type] = wms[i].Time
+	}
+	return &fileWatermarkStore{
+		memoryWatermarkStore: memoryWatermarkStore{
+			records: records,
+		},
+		path: path,
+	}, nil
+}
+
+type fileWatermarkStore struct {
+	memoryWatermarkStore
+	w    sync.Mutex
+	path string
+}
+
+func (in *fileWatermarkStore) Set(ctx context.Context, typ schema.DatasetIngestRequestType, t time.Time) error {
+	in.w.Lock()
+	defer in.w.Unlock()
+	var _ = in.memoryWatermarkStore.Set(ctx, typ, t)
+	return in.flush()
+}
+
+// flush persists all records.
+func (in *fileWatermarkStore) flush() error {
+	in.m.RLock()
+	var wms = make([]Watermark, 0, len(in.records))
+	for typ := range in.records {
+		wms = append(wms, Watermark{Type: typ, Time: in.records[typ]})
+	}
+	in.m.RUnlock()
+	sort.Slice(wms, func(i, j int) bool {
+		return wms[i].Type < wms[j].Type
+	})
+
+	return writeJSONFile(in.path, "watermark", wms)
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AdaptiveWindowPolicy holds the policy of adjusting the window size during ingesting,
+// the window size is halved if the received message or the parsing is heavy,
+// and doubled if the received message and the parsing are light.
+// The window is always kept aligned with the items received before,
+// so no item is skipped or duplicated after adjusting.
+type AdaptiveWindowPolicy struct {
+	// InitialWindowSize is the window size of the first request,
+	// which is overridden by WithWindowSize.
+	InitialWindowSize int32
+	// MinWindowSize is the lower limit of the window size.
+	MinWindowSize int32
+	// MaxWindowSize is the upper limit of the window size.
+	MaxWindowSize int32
+	// TargetMessageRatio is the ratio in range of (0, 1] of the max receive message size,
+	// the window size is adjusted to keep the received message around this ratio.
+	TargetMessageRatio float64
+	// TargetParseLatency is the expected duration of parsing a window,
+	// the window size is not adjusted by the parsing latency if it is not positive.
+	TargetParseLatency time.Duration
+}
+
+// DefaultAdaptiveWindowPolicy returns a default AdaptiveWindowPolicy,
+// which starts from 1000 items and keeps the received message around a quarter of the max receive message size.
+func DefaultAdaptiveWindowPolicy() AdaptiveWindowPolicy {
+	return AdaptiveWindowPolicy{
+		InitialWindowSize:  1000,
+		MinWindowSize:      1,
+		MaxWindowSize:      10000,
+		TargetMessageRatio: 0.25,
+	}
+}
+
+// windowSizer tracks the window size of ingesting one type dataset.
+type windowSizer struct {
+	// size is the current window size, zero means the window size is decided by the exposing service.
+	size int32
+	// responded is the window size responded by the exposing service when the size is not specified.
+	responded int32
+	adaptive  *AdaptiveWindowPolicy
+	// target is the expected size of the received message in bytes.
+	target int
+}
+
+func newWindowSizer(o _IngestOptions) *windowSizer {
+	var s = &windowSizer{
+		size: o.WindowSize,
+	}
+	if o.AdaptiveWindow == nil || o.maxMessageSize <= 0 {
+		return s
+	}
+	var p = *o.AdaptiveWindow
+	if p.MinWindowSize <= 0 {
+		p.MinWindowSize = 1
+	}
+	if p.MaxWindowSize < p.MinWindowSize {
+		p.MaxWindowSize = p.MinWindowSize
+	}
+	if p.TargetMessageRatio <= 0 || p.TargetMessageRatio > 1 {
+		p.TargetMessageRatio = 1
+	}
+	if s.size <= 0 {
+		s.size = p.InitialWindowSize
+	}
+	s.size = clampWindowSize(s.size, p.MinWindowSize, p.MaxWindowSize)
+	s.adaptive = &p
+	s.target = int(float64(o.maxMessageSize) * p.TargetMessageRatio)
+	return s
+}
+
+// request returns the window size to request, returns nil if not specified.
+func (in *windowSizer) request() *int32 {
+	if in.size <= 0 {
+		return nil
+	}
+	var size = in.size
+	return &size
+}
+
+// current returns the window size of the windows received so far,
+// returns 0 if it is unknown.
+func (in *windowSizer) current() int32 {
+	if in.size > 0 {
+		return in.size
+	}
+	return in.responded
+}
+
+// resume returns the next window of the given Checkpoint in the window size to request,
+// the window size of the Checkpoint is adopted if the requesting one cannot align with it.
+func (in *windowSizer) resume(cp *Checkpoint) int32 {
+	if cp.WindowSize <= 0 || cp.WindowSize == in.size {
+		return cp.NextWindow
+	}
+	var offset = int64(cp.NextWindow) * int64(cp.WindowSize)
+	if in.size <= 0 || offset%int64(in.size) != 0 {
+		in.size = cp.WindowSize
+	}
+	return int32(offset / int64(in.size))
+}
+
+// reconcile adopts the window size responded by the exposing service,
+// which might be different from the requested one, e.g. limited by the exposing service,
+// it returns the aligned window and false if the given window must be requested again.
+func (in *windowSizer) reconcile(window, respSize int32) (int32, bool) {
+	if in.size <= 0 {
+		in.responded = respSize
+		return window, true
+	}
+	if respSize <= 0 || respSize == in.size {
+		return window, true
+	}
+	var offset = int64(window) * int64(in.size)
+	if in.adaptive != nil && respSize < in.size {
+		// NB: respect the limit of the exposing service.
+		in.adaptive.MaxWindowSize = respSize
+	}
+	in.size = alignedWindowSize(offset, respSize, 1)
+	var aligned = int32(offset / int64(in.size))
+	return aligned, aligned == window && in.size == respSize
+}
+
+// shrink returns the aligned window in a smaller window size and true,
+// if the given error indicates the received message is too large.
+func (in *windowSizer) shrink(window int32, err error) (int32, bool) {
+	if in.adaptive == nil || !isMessageTooLarge(err) {
+		return window, false
+	}
+	var offset = int64(window) * int64(in.size)
+	var size = alignedWindowSize(offset, in.size/2, in.adaptive.MinWindowSize)
+	if size <= 0 || size >= in.size {
+		return window, false
+	}
+	in.size = size
+	return int32(offset / int64(size)), true
+}
+
+// observe adjusts the window size with the received message size and the parsing latency,
+// and returns the given next window aligned with the adjusted window size.
+func (in *windowSizer) observe(next int32, messageSize int, latency time.Duration) int32 {
+	if in.adaptive == nil || next <= 0 {
+		return next
+	}
+	var p = in.adaptive
+	var offset = int64(next) * int64(in.size)
+	var size int32
+	switch {
+	case messageSize > in.target || (p.TargetParseLatency > 0 && latency > p.TargetParseLatency):
+		size = alignedWindowSize(offset, in.size/2, p.MinWindowSize)
+	case messageSize*2 < in.target && (p.TargetParseLatency <= 0 || latency*2 < p.TargetParseLatency):
+		size = alignedWindowSize(offset, clampWindowSize(in.size*2, p.MinWindowSize, p.MaxWindowSize), in.size+1)
+	}
+	if size <= 0 || size == in.size {
+		return next
+	}
+	in.size = size
+	return int32(offset / int64(size))
+}
+
+// alignedWindowSize returns the largest window size in range of [lower, upper] which divides the given offset,
+// returns 0 if not found.
+func alignedWindowSize(offset int64, upper, lower int32) int32 {
+	for s := upper; s >= lower && s > 0; s-- {
+		if offset%int64(s) == 0 {
+			return s
+		}
+	}
+	return 0
+}
+
+func clampWindowSize(size, lower, upper int32) int32 {
+	if size < lower {
+		return lower
+	}
+	if size > upper {
+		return upper
+	}
+	return size
+}
+
+// isMessageTooLarge returns true if the given error is caused by receiving a message larger than the limit.
+func isMessageTooLarge(err error) bool {
+	var se interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &se) {
+		return false
+	}
+	return se.GRPCStatus().Code() == codes.ResourceExhausted
+}
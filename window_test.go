@@ -0,0 +1,339 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/seal-io/meta-api/schema"
+)
+
+func TestWindowSizer(t *testing.T) {
+	var p = AdaptiveWindowPolicy{
+		InitialWindowSize:  8,
+		MinWindowSize:      2,
+		MaxWindowSize:      32,
+		TargetMessageRatio: 0.5,
+		TargetParseLatency: time.Second,
+	}
+	var o = _IngestOptions{AdaptiveWindow: &p, maxMessageSize: 1000}
+
+	type input struct {
+		next        int32
+		messageSize int
+		latency     time.Duration
+	}
+	type output struct {
+		next int32
+		size int32
+	}
+	var testCases = []struct {
+		name     string
+		given    input
+		expected output
+	}{
+		{
+			name:     "keep",
+			given:    input{next: 3, messageSize: 400},
+			expected: output{next: 3, size: 8},
+		},
+		{
+			name:     "grow",
+			given:    input{next: 4, messageSize: 100},
+			expected: output{next: 2, size: 16},
+		},
+		{
+			name:     "grow aligned",
+			given:    input{next: 3, messageSize: 100},
+			expected: output{next: 2, size: 12},
+		},
+		{
+			name:     "shrink by message",
+			given:    input{next: 3, messageSize: 600},
+			expected: output{next: 6, size: 4},
+		},
+		{
+			name:     "shrink by latency",
+			given:    input{next: 3, messageSize: 100, latency: 2 * time.Second},
+			expected: output{next: 6, size: 4},
+		},
+		{
+			name:     "end",
+			given:    input{next: -1, messageSize: 100},
+			expected: output{next: -1, size: 8},
+		},
+	}
+	for _, c := range testCases {
+		var s = newWindowSizer(o)
+		var actual output
+		actual.next = s.observe(c.given.next, c.given.messageSize, c.given.latency)
+		actual.size = s.size
+		if actual != c.expected {
+			t.Errorf("%s: expected %+v, but got %+v", c.name, c.expected, actual)
+		}
+	}
+
+	var s = newWindowSizer(o)
+	var w, ok = s.shrink(3, fmt.Errorf("wrapped: %w", status.Error(codes.ResourceExhausted, "too large")))
+	if !ok || w != 6 || s.size != 4 {
+		t.Errorf("expected shrinking to window 6 of size 4, but got %d of size %d", w, s.size)
+	}
+	if _, ok = s.shrink(6, status.Error(codes.Unavailable, "x")); ok {
+		t.Error("expected not shrinking by unavailable error")
+	}
+	w, ok = s.reconcile(6, 3)
+	if ok || w != 8 || s.size != 3 || s.adaptive.MaxWindowSize != 3 {
+		t.Errorf("expected re-requesting window 8 of size 3, but got %d of size %d", w, s.size)
+	}
+}
+
+func TestClient_Ingest_WithWindowSize(t *testing.T) {
+	var typ = schema.DatasetIngestRequestType_Weakness_Vulnerability
+	type output struct {
+		names     int
+		duplicate bool
+		sizes     string
+	}
+	var testCases = []struct {
+		name     string
+		limit    int32
+		given    []IngestOption
+		expected output
+	}{
+		{
+			name:  "fixed",
+			limit: 64,
+			given: []IngestOption{WithWindowSize(20)},
+			expected: output{
+				names: 100,
+				sizes: "[20 20 20 20 20]",
+			},
+		},
+		{
+			name:  "adaptive shrink",
+			limit: 64,
+			given: []IngestOption{WithAdaptiveWindowSize(AdaptiveWindowPolicy{
+				InitialWindowSize:  32,
+				MinWindowSize:      1,
+				MaxWindowSize:      1000,
+				TargetMessageRatio: 0.5,
+			})},
+			expected: output{
+				names: 100,
+				// 32 items exceed the max receive message size,
+				// 16 items are around the target message size.
+				sizes: "[32 16 16 16 16 16]",
+			},
+		},
+		{
+			name:  "adaptive grow",
+			limit: 24,
+			given: []IngestOption{WithAdaptiveWindowSize(AdaptiveWindowPolicy{
+				InitialWindowSize:  2,
+				MinWindowSize:      1,
+				MaxWindowSize:      1000,
+				TargetMessageRatio: 1,
+			})},
+			expected: output{
+				names: 100,
+				// 2 items cannot grow to 4 items until 4 items received,
+				// 32 items are limited to 24 items by the exposing service,
+				// then the window is re-requested in 16 items to align with the received 32 items,
+				// and grows to 24 items.
+				sizes: "[2 2 4 8 16 32 16 24 24 24]",
+			},
+		},
+	}
+	for _, c := range testCases {
+		var srv = &testingSizedDatasetServer{
+			items:       100,
+			itemPayload: 100,
+			maxSize:     c.limit,
+		}
+		var cli = testingSizedClient(t, srv, 4096)
+		var actual output
+		var seen = map[string]bool{}
+		var err = cli.Ingest(context.Background(), typ, time.Time{}, func(_ int32, body schema.DatasetIngestResponseBody) error {
+			for _, item := range body.(*schema.DatasetIngestResponse_WeaknessVulnerabilities).WeaknessVulnerabilities.GetItems() {
+				if seen[item.GetName()] {
+					actual.duplicate = true
+				}
+				seen[item.GetName()] = true
+			}
+			return nil
+		}, c.given...)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+			continue
+		}
+		actual.names = len(seen)
+		actual.sizes = fmt.Sprint(srv.requestedSizes())
+		if actual != c.expected {
+			t.Errorf("%s: expected %+v, but got %+v", c.name, c.expected, actual)
+		}
+	}
+}
+
+func TestClient_Ingest_WithWindowSize_Resume(t *testing.T) {
+	var typ = schema.DatasetIngestRequestType_Weakness_Vulnerability
+	var testCases = []struct {
+		name     string
+		given    int32
+		expected string
+	}{
+		{
+			name: "aligned",
+			// 40 items received in 20 items.
+			given:    10,
+			expected: "[0 0 0 10 10 10 10 10 10]",
+		},
+		{
+			name: "unaligned",
+			// 40 items cannot be aligned in 15 items.
+			given:    15,
+			expected: "[0 0 0 20 20 20]",
+		},
+	}
+	for _, c := range testCases {
+		var srv = &testingSizedDatasetServer{
+			items:       100,
+			itemPayload: 1,
+			maxSize:     20,
+		}
+		var cli = testingSizedClient(t, srv, 4096)
+		var store = NewMemoryCheckpointStore()
+		var seen = map[string]int{}
+		var broken bool
+		var parse = func(_ int32, body schema.DatasetIngestResponseBody) error {
+			if len(seen) == 40 && !broken {
+				broken = true
+				return errors.New("broken")
+			}
+			for _, item := range body.(*schema.DatasetIngestResponse_WeaknessVulnerabilities).WeaknessVulnerabilities.GetItems() {
+				seen[item.GetName()]++
+			}
+			return nil
+		}
+
+		// Break under the window size decided by the exposing service.
+		var err = cli.Ingest(context.Background(), typ, time.Time{}, parse, WithCheckpoint(store))
+		if err == nil {
+			t.Errorf("%s: expected broken error, but got nil", c.name)
+			continue
+		}
+		var cp, _ = store.Get(context.Background(), typ, time.Time{})
+		if cp == nil || cp.NextWindow != 2 || cp.WindowSize != 20 {
+			t.Errorf("%s: expected checkpoint of window 2 in 20 items, but got %+v", c.name, cp)
+			continue
+		}
+
+		// Resume with the specified window size.
+		err = cli.Ingest(context.Background(), typ, time.Time{}, parse, WithCheckpoint(store), WithWindowSize(c.given))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+			continue
+		}
+		for name, n := range seen {
+			if n != 1 {
+				t.Errorf("%s: expected %s parsed once, but got %d", c.name, name, n)
+			}
+		}
+		if len(seen) != 100 {
+			t.Errorf("%s: expected 100 items, but got %d", c.name, len(seen))
+		}
+		if sizes := fmt.Sprint(srv.requestedSizes()); sizes != c.expected {
+			t.Errorf("%s: expected requesting %s, but got %s", c.name, c.expected, sizes)
+		}
+	}
+}
+
+// testingSizedDatasetServer is a fake schema.DatasetServiceServer honoring the requested window size,
+// which responses the given count of schema.WeaknessVulnerability items with the given payload bytes.
+type testingSizedDatasetServer struct {
+	schema.UnimplementedDatasetServiceServer
+
+	items       int
+	itemPayload int
+	maxSize     int32
+
+	m         sync.Mutex
+	requested []int32
+}
+
+func (s *testingSizedDatasetServer) Ingest(stream schema.DatasetService_IngestServer) error {
+	for {
+		var req, err = stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		var size = req.GetWindowSize()
+		s.m.Lock()
+		s.requested = append(s.requested, size)
+		s.m.Unlock()
+		if size <= 0 || size > s.maxSize {
+			size = s.maxSize
+		}
+		var resp = &schema.DatasetIngestResponse{
+			WindowSize: size,
+			Body: &schema.DatasetIngestResponse_WeaknessVulnerabilities{
+				WeaknessVulnerabilities: &schema.WeaknessVulnerabilities{},
+			},
+		}
+		var items = &resp.Body.(*schema.DatasetIngestResponse_WeaknessVulnerabilities).WeaknessVulnerabilities.Items
+		for i := int(req.GetWindow() * size); i < s.items && i < int((req.GetWindow()+1)*size); i++ {
+			*items = append(*items, &schema.WeaknessVulnerability{
+				Name:        fmt.Sprintf("CVE-2022-%04d", i),
+				Description: strings.Repeat("x", s.itemPayload),
+			})
+		}
+		if int((req.GetWindow()+1)*size) < s.items {
+			var next = req.GetWindow() + 1
+			resp.NextWindow = &next
+		}
+		err = stream.Send(resp)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *testingSizedDatasetServer) requestedSizes() []int32 {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return append([]int32(nil), s.requested...)
+}
+
+// testingSizedClient serves the given schema.DatasetServiceServer in memory,
+// and returns a Client connecting to it with the given max message size.
+func testingSizedClient(t *testing.T, srv schema.DatasetServiceServer, maxMessageSize int) Client {
+	var lis = bufconn.Listen(1024 * 1024)
+	var gs = grpc.NewServer()
+	schema.RegisterDatasetServiceServer(gs, srv)
+	go func() { _ = gs.Serve(lis) }()
+	t.Cleanup(gs.Stop)
+
+	var cli, err = GetClient(context.Background(), "bufnet",
+		WithMaxMessageSize(maxMessageSize),
+		WithDialOptions(grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		})))
+	if err != nil {
+		t.Fatalf("error creating client: %v", err)
+	}
+	t.Cleanup(func() { _ = cli.Close() })
+	return cli
+}